@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+func init() {
+	applyCmd.Flags().String("server", defaultLocalServerAPI, "The elrond server whose API will be queried.")
+	applyCmd.Flags().String("api-token", "", "The API token used to authenticate against the elrond server. Defaults to the ELROND_API_TOKEN environment variable.")
+	applyCmd.Flags().StringP("file", "f", "", "The YAML or JSON file describing the complete desired set of rings and installation groups.")
+	applyCmd.Flags().Bool("dry-run", false, "When set to true, only print the plan without applying it.")
+	applyCmd.Flags().Bool("prune", false, "Whether to delete the rings missing from the file. Requires the admin role.")
+	applyCmd.Flags().String("idempotency-key", "", "A unique key identifying this request, so that retrying it does not apply the topology twice.")
+	applyCmd.MarkFlagRequired("file") //nolint
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Converge the rings and installation groups to the topology described in a file.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		file, _ := command.Flags().GetString("file")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", file)
+		}
+
+		var topology model.Topology
+		if err = yaml.UnmarshalStrict(data, &topology); err != nil {
+			return errors.Wrapf(err, "failed to parse %s", file)
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		prune, _ := command.Flags().GetBool("prune")
+
+		plan, err := client.ApplyTopology(&model.ApplyTopologyRequest{
+			Topology: &topology,
+			DryRun:   dryRun,
+			Prune:    prune,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to apply topology")
+		}
+
+		return printTopologyPlan(plan)
+	},
+}
+
+func printTopologyPlan(plan *model.TopologyPlan) error {
+	if plan.IsEmpty() {
+		fmt.Println("No changes, the rings already match the topology.")
+		return nil
+	}
+
+	table := tablewriter.NewTable(os.Stdout)
+	table.Header("ACTION", "KIND", "NAME", "RING", "DETAILS")
+
+	for _, change := range plan.Changes {
+		if err := table.Append([]interface{}{change.Action, change.Kind, change.Name, change.Ring, strings.Join(change.Details, ", ")}); err != nil {
+			return errors.Wrap(err, "failed to append row to table")
+		}
+	}
+	if err := table.Render(); err != nil {
+		return errors.Wrap(err, "failed to render table")
+	}
+
+	if plan.Applied {
+		fmt.Printf("Applied %d changes.\n", len(plan.Changes))
+	} else {
+		fmt.Printf("Planned %d changes, nothing was applied.\n", len(plan.Changes))
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	blocklistCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The elrond server whose API will be queried.")
+	blocklistCmd.PersistentFlags().String("api-token", "", "The API token used to authenticate against the elrond server. Defaults to the ELROND_API_TOKEN environment variable.")
+
+	blocklistAddCmd.Flags().String("image", "", "The Mattermost image to block.")
+	blocklistAddCmd.Flags().String("version", "", "The Mattermost version to block.")
+	blocklistAddCmd.Flags().String("reason", "", "Why the release is blocked.")
+	blocklistAddCmd.MarkFlagRequired("image")   //nolint
+	blocklistAddCmd.MarkFlagRequired("version") //nolint
+
+	blocklistRemoveCmd.Flags().String("image", "", "The Mattermost image to unblock.")
+	blocklistRemoveCmd.Flags().String("version", "", "The Mattermost version to unblock.")
+	blocklistRemoveCmd.MarkFlagRequired("image")   //nolint
+	blocklistRemoveCmd.MarkFlagRequired("version") //nolint
+
+	blocklistListCmd.Flags().Bool("table", false, "Whether to display the returned blocked releases in a table or not")
+
+	blocklistCmd.AddCommand(blocklistAddCmd)
+	blocklistCmd.AddCommand(blocklistRemoveCmd)
+	blocklistCmd.AddCommand(blocklistListCmd)
+}
+
+var blocklistCmd = &cobra.Command{
+	Use:   "blocklist",
+	Short: "Manipulate the releases blocked by the elrond server.",
+}
+
+var blocklistAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Block an image and version from being released.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		image, _ := command.Flags().GetString("image")
+		version, _ := command.Flags().GetString("version")
+		reason, _ := command.Flags().GetString("reason")
+
+		blockedRelease, err := client.BlockRelease(&model.BlockReleaseRequest{
+			Image:   image,
+			Version: version,
+			Reason:  reason,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to block release")
+		}
+
+		if err = printJSON(blockedRelease); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var blocklistRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Allow a blocked image and version to be released again.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		image, _ := command.Flags().GetString("image")
+		version, _ := command.Flags().GetString("version")
+
+		err := client.UnblockRelease(&model.UnblockReleaseRequest{
+			Image:   image,
+			Version: version,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to unblock release")
+		}
+
+		return nil
+	},
+}
+
+var blocklistListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the blocked releases.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		blockedReleases, err := client.GetBlockedReleases()
+		if err != nil {
+			return errors.Wrap(err, "failed to query blocked releases")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewTable(os.Stdout)
+			table.Header("IMAGE", "VERSION", "REASON", "CREATED BY", "CREATED AT")
+
+			for _, blockedRelease := range blockedReleases {
+				createdAt := time.UnixMilli(blockedRelease.CreateAt).UTC().Format(time.RFC3339)
+				if appendErr := table.Append([]interface{}{blockedRelease.Image, blockedRelease.Version, blockedRelease.Reason, blockedRelease.CreatedBy, createdAt}); appendErr != nil {
+					return errors.Wrap(appendErr, "failed to append row to table")
+				}
+			}
+			if renderErr := table.Render(); renderErr != nil {
+				return errors.Wrap(renderErr, "failed to render table")
+			}
+
+			return nil
+		}
+
+		if err = printJSON(blockedReleases); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
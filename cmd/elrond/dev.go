@@ -0,0 +1,134 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/elrond/internal/fakeprovisioner"
+)
+
+func init() {
+	devFakeProvisionerCmd.Flags().String("listen", ":8075", "The interface and port on which to listen, matching the default provisioner server of elrond.")
+	devFakeProvisionerCmd.Flags().StringArray("group", []string{}, "A group to serve, for example 'id=group1,name=prod,image=mattermost/mattermost-enterprise-edition,version=10.0.0,installations=3'. Failures are injected with 'unstable=<count>', leaving installations in update-failed after each release, and 'fail-updates=true'. Accepts 'annotation=<name>' and multiple values.")
+	devFakeProvisionerCmd.Flags().Duration("release-delay", 30*time.Second, "How long the installations of a group take to update after the group is patched.")
+
+	devCmd.AddCommand(devFakeProvisionerCmd)
+}
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Tools to run elrond locally.",
+}
+
+var devFakeProvisionerCmd = &cobra.Command{
+	Use:   "fake-provisioner",
+	Short: "Serve an in-memory provisioner with the subset of the provisioner API used by elrond.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		releaseDelay, _ := command.Flags().GetDuration("release-delay")
+		if releaseDelay < 0 {
+			return errors.New("release delay cannot be negative")
+		}
+
+		provisioner := fakeprovisioner.New(releaseDelay, logger)
+
+		groups, _ := command.Flags().GetStringArray("group")
+		for _, value := range groups {
+			group, err := parseFakeProvisionerGroup(value)
+			if err != nil {
+				return err
+			}
+			if err = provisioner.AddGroup(*group); err != nil {
+				return errors.Wrap(err, "invalid group")
+			}
+		}
+
+		listen, _ := command.Flags().GetString("listen")
+		srv := &http.Server{
+			Addr:           listen,
+			Handler:        provisioner,
+			ReadTimeout:    180 * time.Second,
+			WriteTimeout:   180 * time.Second,
+			IdleTimeout:    time.Second * 180,
+			MaxHeaderBytes: 1 << 20,
+			ErrorLog:       log.New(&logrusWriter{logger}, "", 0),
+		}
+
+		go func() {
+			logger.WithField("addr", srv.Addr).Infof("Fake provisioner listening with %d groups", len(groups))
+			err := srv.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				logger.WithError(err).Error("Failed to listen and serve")
+			}
+		}()
+
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+		sig := <-c
+		logger.WithField("shutdown-signal", sig.String()).Info("Shutting down")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx) //nolint
+
+		return nil
+	},
+}
+
+// parseFakeProvisionerGroup parses a comma separated list of key=value settings into a group.
+func parseFakeProvisionerGroup(value string) (*fakeprovisioner.Group, error) {
+	group := &fakeprovisioner.Group{Installations: 1}
+
+	for _, setting := range strings.Split(value, ",") {
+		key, settingValue, found := strings.Cut(setting, "=")
+		if !found {
+			return nil, errors.Errorf("invalid group setting %q in %q, expected key=value", setting, value)
+		}
+
+		var err error
+		switch strings.TrimSpace(key) {
+		case "id":
+			group.ID = settingValue
+		case "name":
+			group.Name = settingValue
+		case "image":
+			group.Image = settingValue
+		case "version":
+			group.Version = settingValue
+		case "annotation":
+			group.Annotations = append(group.Annotations, settingValue)
+		case "installations":
+			group.Installations, err = strconv.Atoi(settingValue)
+		case "unstable":
+			group.UnstableInstallations, err = strconv.Atoi(settingValue)
+		case "fail-updates":
+			group.FailUpdates, err = strconv.ParseBool(settingValue)
+		default:
+			return nil, errors.Errorf("unknown group setting %q in %q", key, value)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s in group %q", key, value)
+		}
+	}
+	if group.Name == "" {
+		group.Name = group.ID
+	}
+
+	return group, nil
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	installationGroupCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The elrond server whose API will be queried.")
+	installationGroupCmd.PersistentFlags().String("api-token", "", "The API token used to authenticate against the elrond server. Defaults to the ELROND_API_TOKEN environment variable.")
+	installationGroupCmd.PersistentFlags().Bool("table", false, "Whether to display the returned installation groups in a table or not")
+
+	installationGroupGetCmd.Flags().String("installation-group", "", "The id of the installation group to be fetched.")
+	installationGroupGetCmd.MarkFlagRequired("installation-group") //nolint
+
+	installationGroupListCmd.Flags().String("ring", "", "The id or name of the ring by which to filter installation groups.")
+	installationGroupListCmd.Flags().String("state", "", "The state by which to filter installation groups.")
+	installationGroupListCmd.Flags().String("provisioner-group-id", "", "The provisioner group id by which to filter installation groups.")
+	installationGroupListCmd.Flags().Int("page", 0, "The page of installation groups to fetch, starting at 0.")
+	installationGroupListCmd.Flags().Int("per-page", 100, "The number of installation groups to fetch per page.")
+
+	installationGroupMoveCmd.Flags().String("installation-group", "", "The id of the installation group to be moved.")
+	installationGroupMoveCmd.Flags().String("ring", "", "The id or name of the ring to move the installation group to.")
+	installationGroupMoveCmd.MarkFlagRequired("installation-group") //nolint
+	installationGroupMoveCmd.MarkFlagRequired("ring")               //nolint
+
+	installationGroupCmd.AddCommand(installationGroupGetCmd)
+	installationGroupCmd.AddCommand(installationGroupListCmd)
+	installationGroupCmd.AddCommand(installationGroupMoveCmd)
+	installationGroupCmd.AddCommand(installationGroupVerifyCmd)
+}
+
+var installationGroupCmd = &cobra.Command{
+	Use:   "installation-group",
+	Short: "Manipulate installation groups managed by the elrond server.",
+}
+
+var installationGroupGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a particular installation group.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		installationGroupID, _ := command.Flags().GetString("installation-group")
+		installationGroup, err := client.GetInstallationGroup(installationGroupID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to query installation group %s", installationGroupID)
+		}
+		if installationGroup == nil {
+			return nil
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			return printInstallationGroupsTable([]*model.InstallationGroup{installationGroup})
+		}
+
+		if err = printJSON(installationGroup); err != nil {
+			return errors.Wrapf(err, "failed to print installation group %s response", installationGroupID)
+		}
+
+		return nil
+	},
+}
+
+var installationGroupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installation groups.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		ring, _ := command.Flags().GetString("ring")
+		state, _ := command.Flags().GetString("state")
+		provisionerGroupID, _ := command.Flags().GetString("provisioner-group-id")
+		page, _ := command.Flags().GetInt("page")
+		perPage, _ := command.Flags().GetInt("per-page")
+
+		var ringID string
+		if ring != "" {
+			var err error
+			ringID, err = resolveRingID(client, ring)
+			if err != nil {
+				return err
+			}
+		}
+
+		installationGroups, err := client.GetInstallationGroups(&model.GetInstallationGroupsRequest{
+			RingID:             ringID,
+			State:              state,
+			ProvisionerGroupID: provisionerGroupID,
+			Page:               page,
+			PerPage:            perPage,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query installation groups")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			return printInstallationGroupsTable(installationGroups)
+		}
+
+		if err = printJSON(installationGroups); err != nil {
+			return errors.Wrap(err, "failed to print installation group list response")
+		}
+
+		return nil
+	},
+}
+
+var installationGroupMoveCmd = &cobra.Command{
+	Use:   "move",
+	Short: "Move an installation group to another ring, keeping its settings.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		installationGroupID, _ := command.Flags().GetString("installation-group")
+		ring, _ := command.Flags().GetString("ring")
+		ringID, err := resolveRingID(client, ring)
+		if err != nil {
+			return err
+		}
+
+		installationGroup, err := client.MoveInstallationGroup(installationGroupID, &model.MoveInstallationGroupRequest{RingID: ringID})
+		if err != nil {
+			return errors.Wrapf(err, "failed to move installation group %s", installationGroupID)
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			return printInstallationGroupsTable([]*model.InstallationGroup{installationGroup})
+		}
+
+		if err = printJSON(installationGroup); err != nil {
+			return errors.Wrapf(err, "failed to print installation group %s response", installationGroupID)
+		}
+
+		return nil
+	},
+}
+
+var installationGroupVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that the provisioner group of every registered installation group exists and is registered once.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		verifications, err := client.VerifyInstallationGroups()
+		if err != nil {
+			return errors.Wrap(err, "failed to verify installation groups")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewTable(os.Stdout)
+			table.Header("ID", "NAME", "RING", "PROVISIONER TARGET", "PROVISIONER GROUP", "PROBLEMS")
+
+			for _, verification := range verifications {
+				if appendErr := table.Append([]interface{}{
+					verification.InstallationGroupID,
+					verification.InstallationGroupName,
+					verification.RingID,
+					verification.ProvisionerTarget,
+					verification.ProvisionerGroupID,
+					strings.Join(verification.Problems, "; "),
+				}); appendErr != nil {
+					return errors.Wrap(appendErr, "failed to append row to table")
+				}
+			}
+			if renderErr := table.Render(); renderErr != nil {
+				return errors.Wrap(renderErr, "failed to render table")
+			}
+		} else if err = printJSON(verifications); err != nil {
+			return errors.Wrap(err, "failed to print installation group verifications")
+		}
+
+		invalid := 0
+		for _, verification := range verifications {
+			if len(verification.Problems) > 0 {
+				invalid++
+			}
+		}
+		if invalid > 0 {
+			return errors.Errorf("%d of %d installation group registrations are invalid", invalid, len(verifications))
+		}
+
+		return nil
+	},
+}
+
+func printInstallationGroupsTable(installationGroups []*model.InstallationGroup) error {
+	table := tablewriter.NewTable(os.Stdout)
+	table.Header("ID", "NAME", "STATE", "RING", "PRIORITY", "PAUSED", "SOAK TIME", "PROVISIONER GROUP", "RELEASE AT")
+
+	for _, installationGroup := range installationGroups {
+		if appendErr := table.Append([]interface{}{
+			installationGroup.ID,
+			installationGroup.Name,
+			installationGroup.State,
+			installationGroup.RingID,
+			strconv.Itoa(installationGroup.Priority),
+			strconv.FormatBool(installationGroup.Paused),
+			strconv.Itoa(installationGroup.SoakTime),
+			installationGroup.ProvisionerGroupID,
+			strconv.FormatInt(installationGroup.ReleaseAt, 10),
+		}); appendErr != nil {
+			return errors.Wrap(appendErr, "failed to append row to table")
+		}
+	}
+	if renderErr := table.Render(); renderErr != nil {
+		return errors.Wrap(renderErr, "failed to render table")
+	}
+
+	return nil
+}
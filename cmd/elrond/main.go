@@ -26,9 +26,16 @@ func init() {
 
 	rootCmd.AddCommand(serverCmd)
 	rootCmd.AddCommand(ringCmd)
+	rootCmd.AddCommand(installationGroupCmd)
 	rootCmd.AddCommand(schemaCmd)
 	rootCmd.AddCommand(webhookCmd)
 	rootCmd.AddCommand(securityCmd)
+	rootCmd.AddCommand(tokenCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(topologyCmd)
+	rootCmd.AddCommand(blocklistCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(devCmd)
 }
 
 func main() {
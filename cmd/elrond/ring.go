@@ -23,6 +23,7 @@ import (
 
 func init() {
 	ringCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The ring server whose API will be queried.")
+	ringCmd.PersistentFlags().String("api-token", "", "The API token used to authenticate against the elrond server. Defaults to the ELROND_API_TOKEN environment variable.")
 	ringCmd.PersistentFlags().Bool("dry-run", false, "When set to true, only print the API request without sending it.")
 
 	ringCreateCmd.Flags().String("name", "", "The name that identifies the deployment ring.")
@@ -30,39 +31,62 @@ func init() {
 	ringCreateCmd.Flags().String("installation-group-name", "", "The installation group name to register with the ring.")
 	ringCreateCmd.Flags().Int("installation-group-soak-time", 0, "The installation group soak time.")
 	ringCreateCmd.Flags().String("installation-group-provisioner-group-id", "", "The installation group provisioner group ID to associate.")
+	ringCreateCmd.Flags().String("installation-group-provisioner-target", "", "The name of the provisioner target managing the installation group provisioner group. Defaults to the provisioner server of elrond.")
 
 	ringCreateCmd.Flags().Int("soak-time", 7200, "The soak time to consider a ring release stable.")
 	ringCreateCmd.Flags().String("image", "", "The Mattermost image to associate with this release ring.")
 	ringCreateCmd.Flags().String("version", "", "The Mattermost version to associate with this release ring.")
+	ringCreateCmd.Flags().StringArray("depends-on", []string{}, "The id or name of a ring to release before this ring. Accepts multiple values.")
+
+	ringCreateCmd.Flags().String("idempotency-key", "", "A unique key identifying this request, so that retrying it does not create the ring twice.")
 
 	ringCreateCmd.MarkFlagRequired("priority") //nolint
 
-	ringUpdateCmd.Flags().String("ring", "", "The id of the ring to update.")
+	ringUpdateCmd.Flags().String("ring", "", "The id or name of the ring to update.")
 	ringUpdateCmd.Flags().String("name", "", "The name to set to the deployment ring.")
 	ringUpdateCmd.Flags().Int("priority", 0, "The priority to set to the deployment ring.")
 	ringUpdateCmd.Flags().Int("soak-time", 0, "The soak time to set to the deployment ring.")
 	ringUpdateCmd.Flags().String("image", "", "The Mattermost image to set to the deployment ring. This will not force a release.")
 	ringUpdateCmd.Flags().String("version", "", "The Mattermost version to set to the deployment ring. This will not force a release.")
+	ringUpdateCmd.Flags().StringArray("depends-on", []string{}, "The id or name of a ring to release before this ring, replacing the current dependencies. Accepts multiple values. Pass an empty value to remove all dependencies.")
 
 	ringUpdateCmd.MarkFlagRequired("ring") //nolint
 
-	ringReleaseCmd.Flags().String("ring", "", "The id of the ring to be released.")
+	ringReleaseCmd.Flags().String("ring", "", "The id or name of the ring to be released.")
 	ringReleaseCmd.Flags().String("image", "", "The Mattermost image to release to.")
 	ringReleaseCmd.Flags().String("version", "", "The Mattermost version to release to.")
 	ringReleaseCmd.Flags().Bool("force", false, "When set to true a release is forced and soaking times are ignored.")
+	ringReleaseCmd.Flags().Bool("allow-downgrade", false, "Whether to allow releasing an older version than the active release of a ring.")
 	ringReleaseCmd.Flags().Bool("all-rings", false, "Whether all rings should be released.")
 	ringReleaseCmd.Flags().Bool("pause", false, "Whether to pause a release in progress.")
 	ringReleaseCmd.Flags().Bool("resume", false, "Whether to resume a paused release.")
 	ringReleaseCmd.Flags().Bool("cancel", false, "Whether to cancel a release.")
+	ringReleaseCmd.Flags().Bool("retry", false, "Whether to retry the failed release of the ring, resuming from the installation groups that did not complete it.")
 	ringReleaseCmd.Flags().StringArray("env-variable", []string{}, "Additional environment variables for the installation group release. Accepts multiple values, for example: '... --env-variable TEST_NAME:TEST_VALUE --env-variable TEST_NAME_2:TEST_VALUE_2'")
+	ringReleaseCmd.Flags().StringArray("exclude-installation-group", []string{}, "The ID or name of an installation group to leave out of the release. Accepts multiple values.")
+
+	ringReleaseCmd.Flags().String("idempotency-key", "", "A unique key identifying this request, so that retrying it does not apply the release twice.")
+
+	ringReleaseListCmd.Flags().String("image", "", "The Mattermost image by which to filter releases.")
+	ringReleaseListCmd.Flags().String("version", "", "The Mattermost version by which to filter releases.")
+	ringReleaseListCmd.Flags().String("created-after", "", "Only list releases created after the given RFC 3339 time, for example 2024-01-02T15:04:05Z.")
+	ringReleaseListCmd.Flags().Bool("force", false, "When set, only list forced releases, or unforced releases with --force=false.")
+	ringReleaseListCmd.Flags().Int("page", 0, "The page of releases to fetch, starting at 0.")
+	ringReleaseListCmd.Flags().Int("per-page", 100, "The number of releases to fetch per page.")
+	ringReleaseListCmd.Flags().Bool("table", false, "Whether to display the returned release list in a table or not")
 
 	ringReleaseGetCmd.Flags().String("release", "", "The id of the release to return info.")
 	ringReleaseGetCmd.MarkFlagRequired("release") //nolint
 
-	ringDeleteCmd.Flags().String("ring", "", "The id of the ring to be deleted.")
+	ringPromoteCmd.Flags().String("ring", "", "The id or name of the ring to promote the release to.")
+	ringPromoteCmd.Flags().String("from", "", "The id or name of the stable ring whose active release is promoted.")
+	ringPromoteCmd.MarkFlagRequired("ring") //nolint
+	ringPromoteCmd.MarkFlagRequired("from") //nolint
+
+	ringDeleteCmd.Flags().String("ring", "", "The id or name of the ring to be deleted.")
 	ringDeleteCmd.MarkFlagRequired("ring") //nolint
 
-	ringGetCmd.Flags().String("ring", "", "The id of the ring to be fetched.")
+	ringGetCmd.Flags().String("ring", "", "The id or name of the ring to be fetched.")
 	ringGetCmd.MarkFlagRequired("ring") //nolint
 
 	ringListCmd.Flags().Int("page", 0, "The page of rings to fetch, starting at 0.")
@@ -70,13 +94,18 @@ func init() {
 	ringListCmd.Flags().Bool("include-deleted", false, "Whether to include deleted rings.")
 	ringListCmd.Flags().Bool("table", false, "Whether to display the returned ring list in a table or not")
 
+	ringGraphCmd.Flags().Bool("table", false, "Whether to display the returned ring graph in a table or not")
+
 	ringCmd.AddCommand(ringCreateCmd)
 	ringCmd.AddCommand(ringReleaseCmd)
 	ringCmd.AddCommand(ringReleaseGetCmd)
+	ringReleaseCmd.AddCommand(ringReleaseListCmd)
+	ringCmd.AddCommand(ringPromoteCmd)
 	ringCmd.AddCommand(ringUpdateCmd)
 	ringCmd.AddCommand(ringDeleteCmd)
 	ringCmd.AddCommand(ringGetCmd)
 	ringCmd.AddCommand(ringListCmd)
+	ringCmd.AddCommand(ringGraphCmd)
 	ringCmd.AddCommand(ringInstallationGroupCmd)
 }
 
@@ -102,21 +131,24 @@ var ringCreateCmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		name, _ := command.Flags().GetString("name")
 		priority, _ := command.Flags().GetInt("priority")
 		installationGroupName, _ := command.Flags().GetString("installation-group-name")
 		installationGroupSoakTime, _ := command.Flags().GetInt("installation-group-soak-time")
 		installationGroupProvisionerGroupID, _ := command.Flags().GetString("installation-group-provisioner-group-id")
+		installationGroupProvisionerTarget, _ := command.Flags().GetString("installation-group-provisioner-target")
 		soakTime, _ := command.Flags().GetInt("soak-time")
 		image, _ := command.Flags().GetString("image")
 		version, _ := command.Flags().GetString("version")
+		dependsOn, _ := command.Flags().GetStringArray("depends-on")
 
 		installationGroup := &model.InstallationGroup{
 			Name:               installationGroupName,
 			SoakTime:           installationGroupSoakTime,
 			ProvisionerGroupID: installationGroupProvisionerGroupID,
+			ProvisionerTarget:  installationGroupProvisionerTarget,
 		}
 
 		request := &model.CreateRingRequest{
@@ -126,6 +158,7 @@ var ringCreateCmd = &cobra.Command{
 			SoakTime:          soakTime,
 			Image:             image,
 			Version:           version,
+			DependsOn:         dependsOn,
 		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
@@ -138,6 +171,12 @@ var ringCreateCmd = &cobra.Command{
 			return nil
 		}
 
+		dependsOn, err := resolveRingIDs(client, dependsOn)
+		if err != nil {
+			return err
+		}
+		request.DependsOn = dependsOn
+
 		ring, err := client.CreateRing(request)
 		if err != nil {
 			return errors.Wrapf(err, "failed to create ring %s", request.Name)
@@ -162,7 +201,7 @@ var ringUpdateCmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		ringID, _ := command.Flags().GetString("ring")
 		name, _ := command.Flags().GetString("name")
@@ -178,6 +217,10 @@ var ringUpdateCmd = &cobra.Command{
 			Image:    image,
 			Version:  version,
 		}
+		if command.Flags().Changed("depends-on") {
+			dependsOn, _ := command.Flags().GetStringArray("depends-on")
+			request.DependsOn = &dependsOn
+		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
 		if dryRun {
@@ -189,6 +232,19 @@ var ringUpdateCmd = &cobra.Command{
 			return nil
 		}
 
+		ringID, err := resolveRingID(client, ringID)
+		if err != nil {
+			return err
+		}
+
+		if request.DependsOn != nil {
+			dependsOn, resolveErr := resolveRingIDs(client, *request.DependsOn)
+			if resolveErr != nil {
+				return resolveErr
+			}
+			request.DependsOn = &dependsOn
+		}
+
 		ring, err := client.UpdateRing(ringID, request)
 		if err != nil {
 			return errors.Wrapf(err, "failed to update ring %s", request.Name)
@@ -213,16 +269,19 @@ var ringReleaseCmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 		ringID, _ := command.Flags().GetString("ring")
 		image, _ := command.Flags().GetString("image")
 		version, _ := command.Flags().GetString("version")
 		force, _ := command.Flags().GetBool("force")
+		allowDowngrade, _ := command.Flags().GetBool("allow-downgrade")
 		releaseAllRings, _ := command.Flags().GetBool("all-rings")
 		pauseRelease, _ := command.Flags().GetBool("pause")
 		resumeRelease, _ := command.Flags().GetBool("resume")
 		cancelRelease, _ := command.Flags().GetBool("cancel")
+		retryRelease, _ := command.Flags().GetBool("retry")
 		envVariables, _ := command.Flags().GetStringArray("env-variable")
+		excludedInstallationGroups, _ := command.Flags().GetStringArray("exclude-installation-group")
 
 		mattermostEnvVariables := make(cmodel.EnvVarMap)
 		if len(envVariables) > 0 {
@@ -236,10 +295,12 @@ var ringReleaseCmd = &cobra.Command{
 			}
 		}
 		request := &model.RingReleaseRequest{
-			Image:        image,
-			Version:      version,
-			Force:        force,
-			EnvVariables: mattermostEnvVariables,
+			Image:                      image,
+			Version:                    version,
+			Force:                      force,
+			EnvVariables:               mattermostEnvVariables,
+			ExcludedInstallationGroups: excludedInstallationGroups,
+			AllowDowngrade:             allowDowngrade,
 		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
@@ -279,6 +340,22 @@ var ringReleaseCmd = &cobra.Command{
 			return nil
 		}
 
+		if retryRelease {
+			ringID, err := resolveRingID(client, ringID)
+			if err != nil {
+				return err
+			}
+			ring, err := client.RetryReleaseRing(ringID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to retry the release of ring %s", ringID)
+			}
+			if err = printJSON(ring); err != nil {
+				return errors.Wrapf(err, "failed to print ring %s retry response", ringID)
+			}
+
+			return nil
+		}
+
 		if releaseAllRings {
 			rings, err := client.ReleaseAllRings(request)
 			if err != nil {
@@ -288,6 +365,10 @@ var ringReleaseCmd = &cobra.Command{
 				return errors.Wrap(err, "failed to print release response for an all rings release")
 			}
 		} else {
+			ringID, err := resolveRingID(client, ringID)
+			if err != nil {
+				return err
+			}
 			ring, err := client.ReleaseRing(ringID, request)
 			if err != nil {
 				return errors.Wrapf(err, "failed to release a ring %s", ringID)
@@ -301,6 +382,84 @@ var ringReleaseCmd = &cobra.Command{
 	},
 }
 
+var ringReleaseListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List ring releases, with the rings and installation groups running or desiring each one.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		image, _ := command.Flags().GetString("image")
+		version, _ := command.Flags().GetString("version")
+		page, _ := command.Flags().GetInt("page")
+		perPage, _ := command.Flags().GetInt("per-page")
+		request := &model.GetRingReleasesRequest{
+			Image:   image,
+			Version: version,
+			Page:    page,
+			PerPage: perPage,
+		}
+
+		createdAfter, _ := command.Flags().GetString("created-after")
+		if createdAfter != "" {
+			createdAfterTime, err := time.Parse(time.RFC3339, createdAfter)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse created-after time")
+			}
+			request.CreatedAfter = createdAfterTime.UnixNano()
+		}
+		if command.Flags().Changed("force") {
+			force, _ := command.Flags().GetBool("force")
+			request.Force = &force
+		}
+
+		ringReleases, err := client.GetRingReleases(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to query ring releases")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewTable(os.Stdout)
+			table.Header("ID", "IMAGE", "VERSION", "FORCE", "CREATED AT", "CREATED BY", "ACTIVE RINGS", "DESIRED RINGS", "ACTIVE INSTALLATION GROUPS", "DESIRED INSTALLATION GROUPS")
+
+			for _, ringRelease := range ringReleases {
+				if appendErr := table.Append([]interface{}{
+					ringRelease.ID,
+					ringRelease.Image,
+					ringRelease.Version,
+					strconv.FormatBool(ringRelease.Force),
+					time.Unix(0, ringRelease.CreateAt).UTC().Format(time.RFC3339),
+					ringRelease.CreatedBy,
+					strings.Join(ringRelease.ActiveRings, "\n"),
+					strings.Join(ringRelease.DesiredRings, "\n"),
+					strconv.Itoa(len(ringRelease.ActiveInstallationGroups)),
+					strconv.Itoa(len(ringRelease.DesiredInstallationGroups)),
+				}); appendErr != nil {
+					return errors.Wrap(appendErr, "failed to append row to table")
+				}
+			}
+			if renderErr := table.Render(); renderErr != nil {
+				return errors.Wrap(renderErr, "failed to render table")
+			}
+
+			return nil
+		}
+
+		if err = printJSON(ringReleases); err != nil {
+			return errors.Wrap(err, "failed to print ring release list response")
+		}
+
+		return nil
+	},
+}
+
 var ringReleaseGetCmd = &cobra.Command{
 	Use:   "get-release",
 	Short: "Get a particular ring release.",
@@ -312,7 +471,7 @@ var ringReleaseGetCmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		releaseID, _ := command.Flags().GetString("release")
 		ringRelease, err := client.GetRingRelease(releaseID)
@@ -331,6 +490,54 @@ var ringReleaseGetCmd = &cobra.Command{
 	},
 }
 
+var ringPromoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Release the active release of a stable ring to another ring.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		ring, _ := command.Flags().GetString("ring")
+		from, _ := command.Flags().GetString("from")
+
+		ringID, err := resolveRingID(client, ring)
+		if err != nil {
+			return err
+		}
+		sourceRingID, err := resolveRingID(client, from)
+		if err != nil {
+			return err
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			err = printJSON(map[string]string{"ring": ringID, "from": sourceRingID})
+			if err != nil {
+				return errors.Wrap(err, "failed to print API request")
+			}
+
+			return nil
+		}
+
+		promotedRing, err := client.PromoteRing(ringID, sourceRingID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to promote the release of ring %s to ring %s", from, ring)
+		}
+
+		if err = printJSON(promotedRing); err != nil {
+			return errors.Wrapf(err, "failed to print ring %s response", ring)
+		}
+
+		return nil
+	},
+}
+
 var ringDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a ring.",
@@ -342,11 +549,15 @@ var ringDeleteCmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		ringID, _ := command.Flags().GetString("ring")
+		ringID, err := resolveRingID(client, ringID)
+		if err != nil {
+			return err
+		}
 
-		err := client.DeleteRing(ringID)
+		err = client.DeleteRing(ringID)
 		if err != nil {
 			return errors.Wrapf(err, "failed to delete ring %s", ringID)
 		}
@@ -366,9 +577,13 @@ var ringGetCmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		ringID, _ := command.Flags().GetString("ring")
+		ringID, err := resolveRingID(client, ringID)
+		if err != nil {
+			return err
+		}
 		ring, err := client.GetRing(ringID)
 		if err != nil {
 			return errors.Wrapf(err, "failed to query ring %s", ringID)
@@ -396,7 +611,7 @@ var ringListCmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		page, _ := command.Flags().GetInt("page")
 		perPage, _ := command.Flags().GetInt("per-page")
@@ -470,3 +685,104 @@ var ringListCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var ringGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Show the order in which the rings are released.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		graph, err := client.GetRingGraph()
+		if err != nil {
+			return errors.Wrap(err, "failed to query ring graph")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			names := make(map[string]string)
+			for _, ring := range graph.Rings {
+				names[ring.ID] = ring.Name
+			}
+
+			table := tablewriter.NewTable(os.Stdout)
+			table.Header("STAGE", "ID", "NAME", "STATE", "PRIORITY", "DEPENDS ON")
+
+			for _, ring := range graph.Rings {
+				var dependsOn []string
+				for _, dependencyID := range ring.DependsOn {
+					dependsOn = append(dependsOn, names[dependencyID])
+				}
+
+				if appendErr := table.Append([]interface{}{
+					strconv.Itoa(ring.Stage),
+					ring.ID,
+					ring.Name,
+					ring.State,
+					strconv.Itoa(ring.Priority),
+					strings.Join(dependsOn, ", "),
+				}); appendErr != nil {
+					return errors.Wrap(appendErr, "failed to append row to table")
+				}
+			}
+			if renderErr := table.Render(); renderErr != nil {
+				return errors.Wrap(renderErr, "failed to render table")
+			}
+
+			return nil
+		}
+
+		if err = printJSON(graph); err != nil {
+			return errors.Wrap(err, "failed to print ring graph response")
+		}
+
+		return nil
+	},
+}
+
+// resolveRingID returns the ID of the ring identified by the given ID or
+// name. IDs take precedence over names.
+func resolveRingID(client *model.Client, ring string) (string, error) {
+	if len(ring) == 26 {
+		found, err := client.GetRing(ring)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to query ring %s", ring)
+		}
+		if found != nil {
+			return found.ID, nil
+		}
+	}
+
+	found, err := client.GetRingByName(ring)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to query ring %s", ring)
+	}
+	if found == nil {
+		return "", errors.Errorf("no ring found with id or name %s", ring)
+	}
+
+	return found.ID, nil
+}
+
+// resolveRingIDs resolves the given ring IDs or names to ring IDs, ignoring empty values.
+func resolveRingIDs(client *model.Client, rings []string) ([]string, error) {
+	ringIDs := []string{}
+	for _, ring := range rings {
+		if ring == "" {
+			continue
+		}
+		ringID, err := resolveRingID(client, ring)
+		if err != nil {
+			return nil, err
+		}
+		ringIDs = append(ringIDs, ringID)
+	}
+
+	return ringIDs, nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	ringImportCmd.Flags().StringArray("rule", []string{}, "A rule mapping provisioner groups to a ring, for example 'ring=production,pattern=prod-*,priority=3,soak-time=3600'. Groups may also be matched with 'annotation=<name>'. Rules are evaluated in order and accept multiple values.")
+	ringImportCmd.Flags().String("provisioner-target", "", "The name of the provisioner target to import the groups from. Defaults to the provisioner server of elrond.")
+	ringImportCmd.Flags().Bool("preview", false, "When set to true, only show the rings and installation groups the import would create, without creating them.")
+	ringImportCmd.Flags().Bool("table", false, "Whether to display the import in a table or not")
+	_ = ringImportCmd.MarkFlagRequired("rule")
+
+	ringCmd.AddCommand(ringImportCmd)
+}
+
+var ringImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Create rings and installation groups from the existing provisioner groups.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		rules, _ := command.Flags().GetStringArray("rule")
+		provisionerTarget, _ := command.Flags().GetString("provisioner-target")
+		preview, _ := command.Flags().GetBool("preview")
+
+		request := &model.ImportRingsRequest{ProvisionerTarget: provisionerTarget, DryRun: preview}
+		for _, value := range rules {
+			rule, err := parseRingImportRule(value)
+			if err != nil {
+				return err
+			}
+			request.Rules = append(request.Rules, rule)
+		}
+		request.SetDefaults()
+		if err := request.Validate(); err != nil {
+			return errors.Wrap(err, "invalid import rules")
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			err := printJSON(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to print API request")
+			}
+
+			return nil
+		}
+
+		ringImport, err := client.ImportRings(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to import rings")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			return printRingImport(ringImport)
+		}
+
+		if err = printJSON(ringImport); err != nil {
+			return errors.Wrap(err, "failed to print ring import response")
+		}
+
+		return nil
+	},
+}
+
+// parseRingImportRule parses a comma separated list of key=value settings into a rule.
+func parseRingImportRule(value string) (*model.RingImportRule, error) {
+	rule := &model.RingImportRule{}
+
+	for _, setting := range strings.Split(value, ",") {
+		key, settingValue, found := strings.Cut(setting, "=")
+		if !found {
+			return nil, errors.Errorf("invalid rule setting %q in %q, expected key=value", setting, value)
+		}
+
+		var err error
+		switch strings.TrimSpace(key) {
+		case "ring":
+			rule.Ring = settingValue
+		case "pattern":
+			rule.NamePattern = settingValue
+		case "annotation":
+			rule.Annotation = settingValue
+		case "priority":
+			rule.Priority, err = strconv.Atoi(settingValue)
+		case "soak-time":
+			rule.SoakTime, err = strconv.Atoi(settingValue)
+		default:
+			return nil, errors.Errorf("unknown rule setting %q in %q", key, value)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s in rule %q", key, value)
+		}
+	}
+
+	return rule, nil
+}
+
+func printRingImport(ringImport *model.RingImport) error {
+	table := tablewriter.NewTable(os.Stdout)
+	table.Header("RING", "ACTION", "INSTALLATION GROUP", "PROVISIONER GROUP", "RELEASE")
+
+	for _, ring := range ringImport.Rings {
+		action := "register"
+		if ring.Create {
+			action = "create"
+		}
+		for _, installationGroup := range ring.InstallationGroups {
+			if err := table.Append([]interface{}{
+				ring.Name,
+				action,
+				installationGroup.Name,
+				installationGroup.ProvisionerGroupID,
+				installationGroup.Image + ":" + installationGroup.Version,
+			}); err != nil {
+				return errors.Wrap(err, "failed to append row to table")
+			}
+		}
+	}
+	for _, skipped := range ringImport.Skipped {
+		if err := table.Append([]interface{}{"", "skip", skipped.Name, skipped.ProvisionerGroupID, skipped.Reason}); err != nil {
+			return errors.Wrap(err, "failed to append row to table")
+		}
+	}
+	if err := table.Render(); err != nil {
+		return errors.Wrap(err, "failed to render table")
+	}
+
+	var count int
+	for _, ring := range ringImport.Rings {
+		count += len(ring.InstallationGroups)
+	}
+	if ringImport.Applied {
+		fmt.Printf("Imported %d installation groups, skipped %d provisioner groups.\n", count, len(ringImport.Skipped))
+	} else {
+		fmt.Printf("Would import %d installation groups, skipping %d provisioner groups. Nothing was imported.\n", count, len(ringImport.Skipped))
+	}
+
+	return nil
+}
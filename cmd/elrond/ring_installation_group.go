@@ -13,9 +13,12 @@ import (
 func init() {
 	ringInstallationGroupRegisterCmd.Flags().String("installation-group-name", "", "Additional installation group for the ring.")
 
-	ringInstallationGroupRegisterCmd.Flags().String("ring", "", "The id of the ring to register the installation groups.")
+	ringInstallationGroupRegisterCmd.Flags().String("ring", "", "The id or name of the ring to register the installation groups.")
 	ringInstallationGroupRegisterCmd.Flags().String("provisioner-group-id", "", "The id of the provisioner group that will have 1to1 relationship with the elrond installation group.")
+	ringInstallationGroupRegisterCmd.Flags().String("provisioner-target", "", "The name of the provisioner target managing the provisioner group. Defaults to the provisioner server of elrond.")
 	ringInstallationGroupRegisterCmd.Flags().Int("soak-time", 0, "The soak time to consider an installation group release stable.")
+	ringInstallationGroupRegisterCmd.Flags().Int("priority", 0, "The release priority of the installation group within the ring. Lower priorities are released first.")
+	ringInstallationGroupRegisterCmd.Flags().String("idempotency-key", "", "A unique key identifying this request, so that retrying it does not register the installation group twice.")
 	_ = ringInstallationGroupRegisterCmd.MarkFlagRequired("ring")
 	_ = ringInstallationGroupRegisterCmd.MarkFlagRequired("installation-group-name")
 	_ = ringInstallationGroupRegisterCmd.MarkFlagRequired("provisioner-group-id")
@@ -23,10 +26,13 @@ func init() {
 	ringInstallationGroupUpdateCmd.Flags().String("installation-group", "", "The id of the installation group to update.")
 	ringInstallationGroupUpdateCmd.Flags().String("name", "", "The name to set to the installation group.")
 	ringInstallationGroupUpdateCmd.Flags().String("provisioner-group-id", "", "The id of the provisioner group that will have 1to1 relationship with the elrond installation group.")
+	ringInstallationGroupUpdateCmd.Flags().String("provisioner-target", "", "The name of the provisioner target managing the provisioner group. Pass an empty value to use the provisioner server of elrond.")
 	ringInstallationGroupUpdateCmd.Flags().Int("soak-time", 0, "The soak time to set to the installation group.")
+	ringInstallationGroupUpdateCmd.Flags().Int("priority", 0, "The release priority to set to the installation group within its ring. Lower priorities are released first.")
+	ringInstallationGroupUpdateCmd.Flags().Bool("paused", false, "Whether the installation group is paused. Paused installation groups are skipped by ring releases.")
 
 	ringInstallationGroupDeleteCmd.Flags().String("installation-group", "", "ID of the installation group to be removed from the ring.")
-	ringInstallationGroupDeleteCmd.Flags().String("ring", "", "The id of the ring from which installation group should be removed.")
+	ringInstallationGroupDeleteCmd.Flags().String("ring", "", "The id or name of the ring from which installation group should be removed.")
 	_ = ringInstallationGroupDeleteCmd.MarkFlagRequired("ring")
 	_ = ringInstallationGroupDeleteCmd.MarkFlagRequired("installation-group")
 
@@ -47,17 +53,21 @@ var ringInstallationGroupRegisterCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		ringID, _ := command.Flags().GetString("ring")
 		installationGroupName, _ := command.Flags().GetString("installation-group-name")
 		soakTime, _ := command.Flags().GetInt("soak-time")
 		provisionerGroupID, _ := command.Flags().GetString("provisioner-group-id")
+		provisionerTarget, _ := command.Flags().GetString("provisioner-target")
+		priority, _ := command.Flags().GetInt("priority")
 
 		request := &model.RegisterInstallationGroupRequest{
 			Name:               installationGroupName,
 			SoakTime:           soakTime,
 			ProvisionerGroupID: provisionerGroupID,
+			ProvisionerTarget:  provisionerTarget,
+			Priority:           priority,
 		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
@@ -65,6 +75,11 @@ var ringInstallationGroupRegisterCmd = &cobra.Command{
 			return runDryRun(request)
 		}
 
+		ringID, err := resolveRingID(client, ringID)
+		if err != nil {
+			return err
+		}
+
 		ring, err := client.RegisterRingInstallationGroup(ringID, request)
 		if err != nil {
 			return errors.Wrap(err, "failed to add ring installation group")
@@ -86,12 +101,17 @@ var ringInstallationGroupDeleteCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		RingID, _ := command.Flags().GetString("ring")
 		installationGroup, _ := command.Flags().GetString("installation-group")
 
-		err := client.DeleteRingInstallationGroup(RingID, installationGroup)
+		RingID, err := resolveRingID(client, RingID)
+		if err != nil {
+			return err
+		}
+
+		err = client.DeleteRingInstallationGroup(RingID, installationGroup)
 		if err != nil {
 			return errors.Wrap(err, "failed to delete ring installation group")
 		}
@@ -107,7 +127,7 @@ var ringInstallationGroupUpdateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		installationGroupID, _ := command.Flags().GetString("installation-group")
 		name, _ := command.Flags().GetString("name")
@@ -119,6 +139,18 @@ var ringInstallationGroupUpdateCmd = &cobra.Command{
 			SoakTime:           soakTime,
 			ProvisionerGroupID: provisionerGroupID,
 		}
+		if command.Flags().Changed("provisioner-target") {
+			provisionerTarget, _ := command.Flags().GetString("provisioner-target")
+			request.ProvisionerTarget = &provisionerTarget
+		}
+		if command.Flags().Changed("priority") {
+			priority, _ := command.Flags().GetInt("priority")
+			request.Priority = &priority
+		}
+		if command.Flags().Changed("paused") {
+			paused, _ := command.Flags().GetBool("paused")
+			request.Paused = &paused
+		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
 		if dryRun {
@@ -5,13 +5,22 @@
 package main
 
 import (
+	"os"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
 	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/model"
 )
 
 func init() {
+	schemaRestoreCmd.Flags().String("file", "", "The YAML or JSON file produced by 'elrond topology export'.")
+	schemaRestoreCmd.MarkFlagRequired("file") //nolint
+
 	schemaCmd.AddCommand(schemaMigrateCmd)
+	schemaCmd.AddCommand(schemaRestoreCmd)
 	schemaCmd.PersistentFlags().String("database", "sqlite://elrond.db", "The database backing the elrond server.")
 }
 
@@ -44,3 +53,39 @@ var schemaMigrateCmd = &cobra.Command{
 		return sqlStore.Migrate()
 	},
 }
+
+var schemaRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Migrate an empty database and restore a topology export into it.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		file, _ := command.Flags().GetString("file")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", file)
+		}
+
+		var export model.TopologyExport
+		if err = yaml.Unmarshal(data, &export); err != nil {
+			return errors.Wrapf(err, "failed to parse %s", file)
+		}
+
+		sqlStore, err := sqlStore(command)
+		if err != nil {
+			return err
+		}
+
+		if err = sqlStore.Migrate(); err != nil {
+			return errors.Wrap(err, "failed to migrate the database")
+		}
+
+		if err = sqlStore.RestoreTopology(&export); err != nil {
+			return errors.Wrap(err, "failed to restore topology")
+		}
+
+		logger.Infof("Restored %d rings, %d webhooks, %d releases and %d blocked releases", len(export.Rings), len(export.Webhooks), len(export.Releases), len(export.BlockedReleases))
+
+		return nil
+	},
+}
@@ -7,15 +7,15 @@ package main
 import (
 	"net/url"
 
-	"github.com/mattermost/elrond/model"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	securityCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The elrond server whose API will be queried.")
+	securityCmd.PersistentFlags().String("api-token", "", "The API token used to authenticate against the elrond server. Defaults to the ELROND_API_TOKEN environment variable.")
 
-	securityRingCmd.PersistentFlags().String("ring", "", "The id of the ring.")
+	securityRingCmd.PersistentFlags().String("ring", "", "The id or name of the ring.")
 	securityRingCmd.MarkPersistentFlagRequired("ring") //nolint
 
 	securityCmd.AddCommand(securityRingCmd)
@@ -44,10 +44,14 @@ var securityRingLockAPICmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		ringID, _ := command.Flags().GetString("ring")
-		err := client.LockAPIForRing(ringID)
+		ringID, err := resolveRingID(client, ringID)
+		if err != nil {
+			return err
+		}
+		err = client.LockAPIForRing(ringID)
 		if err != nil {
 			return errors.Wrap(err, "failed to lock ring API")
 		}
@@ -67,10 +71,14 @@ var securityRingUnlockAPICmd = &cobra.Command{
 			return errors.Wrap(err, "provided server address not a valid address")
 		}
 
-		client := model.NewClient(serverAddress)
+		client := newClient(command, serverAddress)
 
 		ringID, _ := command.Flags().GetString("ring")
-		err := client.UnlockAPIForRing(ringID)
+		ringID, err := resolveRingID(client, ringID)
+		if err != nil {
+			return err
+		}
+		err = client.UnlockAPIForRing(ringID)
 		if err != nil {
 			return errors.Wrap(err, "failed to unlock ring API")
 		}
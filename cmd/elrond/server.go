@@ -15,6 +15,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/elrond/internal/api"
+	"github.com/mattermost/elrond/internal/auth"
 	"github.com/mattermost/elrond/internal/elrond"
 	"github.com/mattermost/elrond/internal/store"
 	"github.com/mattermost/elrond/internal/supervisor"
@@ -43,15 +44,34 @@ func init() {
 	serverCmd.PersistentFlags().String("provisioner-client-id", "", "The client ID for the provisioning server.")
 	serverCmd.PersistentFlags().String("provisioner-client-secret", "", "The client secret for the provisioning server.")
 	serverCmd.PersistentFlags().String("provisioner-token-endpoint", "", "The token endpoint for the provisioning server.")
+	serverCmd.PersistentFlags().String("provisioner-targets-file", "", "The YAML or JSON file listing the named provisioner targets installation groups can reference, in addition to the provisioner server.")
 	serverCmd.PersistentFlags().Int("provisioner-group-release-timeout", 3600, "The provisioner group release timeout")
+	serverCmd.PersistentFlags().Int("provisioner-group-unstable-threshold", 0, "The number of installations allowed to end a provisioner group release in an unstable state, such as update-failed, before the installation group release fails.")
+	serverCmd.PersistentFlags().Duration("provisioner-group-poll-interval", time.Minute, "How often the progress of a provisioner group release is checked.")
 	serverCmd.PersistentFlags().String("grafana-url", "", "The Grafana url for the Grafana integration.")
 	serverCmd.PersistentFlags().StringSlice("grafana-token", []string{""}, "The grafana token registered with Grafana Org. You can pass multiple entries.")
 	serverCmd.PersistentFlags().String("thanos-url", "", "The Thanos url for the SLO checks while Soaking. If not added SLO metric checks are ignored")
 
+	// Authentication
+	serverCmd.PersistentFlags().Bool("auth", false, "Whether API requests must be authenticated with an API token or JWT bearer token.")
+	serverCmd.PersistentFlags().String("auth-jwks-file", "", "The JWKS file holding the keys used to validate JWT bearer tokens. If not set only API tokens are accepted.")
+	serverCmd.PersistentFlags().String("auth-jwt-issuer", "", "The issuer JWT bearer tokens must be issued by.")
+	serverCmd.PersistentFlags().String("auth-jwt-audience", "", "The audience JWT bearer tokens must be intended for.")
+	serverCmd.PersistentFlags().String("auth-jwt-role-claim", "elrond_role", "The JWT claim holding the elrond role of the caller.")
+
+	// Idempotency
+	serverCmd.PersistentFlags().Duration("idempotency-retention", api.DefaultIdempotencyRetention, "How long responses to requests made with an Idempotency-Key header are kept and replayed.")
+
+	// Release blocklist
+	serverCmd.PersistentFlags().String("blocked-release-action", model.BlockedReleaseActionPause, "What to do with the rings releasing an image and version when it is blocked: pause or rollback.")
+
 	// Supervisors
 	serverCmd.PersistentFlags().Int("poll", 30, "The interval in seconds to poll for background work.")
 	serverCmd.PersistentFlags().Bool("ring-supervisor", true, "Whether this server will run a ring supervisor or not.")
 	serverCmd.PersistentFlags().Bool("installationgroup-supervisor", true, "Whether this server will run an installation group supervisor or not.")
+	serverCmd.PersistentFlags().Bool("drift-supervisor", true, "Whether this server will run a supervisor detecting provisioner groups that drifted from their release or not.")
+	serverCmd.PersistentFlags().Duration("drift-check-interval", 15*time.Minute, "How often the provisioner groups of stable installation groups are checked for drift.")
+	serverCmd.PersistentFlags().Bool("drift-remediate", false, "Whether to release drifted provisioner groups their release again.")
 }
 
 var serverCmd = &cobra.Command{
@@ -75,7 +95,25 @@ var serverCmd = &cobra.Command{
 		provisionerClientSecret, _ := command.Flags().GetString("provisioner-client-secret")
 		provisionerTokenEndpoint, _ := command.Flags().GetString("provisioner-token-endpoint")
 
+		var provisionerTargets []elrond.ProvisionerTarget
+		provisionerTargetsFile, _ := command.Flags().GetString("provisioner-targets-file")
+		if provisionerTargetsFile != "" {
+			var err error
+			provisionerTargets, err = elrond.LoadProvisionerTargets(provisionerTargetsFile)
+			if err != nil {
+				return err
+			}
+		}
+
 		provisionerGroupReleaseTimeout, _ := command.Flags().GetInt("provisioner-group-release-timeout")
+		provisionerGroupUnstableThreshold, _ := command.Flags().GetInt("provisioner-group-unstable-threshold")
+		if provisionerGroupUnstableThreshold < 0 {
+			return errors.New("provisioner group unstable threshold cannot be negative")
+		}
+		provisionerGroupPollInterval, _ := command.Flags().GetDuration("provisioner-group-poll-interval")
+		if provisionerGroupPollInterval <= 0 {
+			return errors.New("provisioner group poll interval must be positive")
+		}
 
 		logger := logger.WithField("instance", instanceID)
 
@@ -111,8 +149,17 @@ var serverCmd = &cobra.Command{
 			logger.Warn("The thanos-url flag was empty; no Thanos integration configured for SLO checks during Soak time")
 		}
 
+		authenticator, err := newAuthenticator(command, sqlStore)
+		if err != nil {
+			return err
+		}
+		if authenticator == nil {
+			logger.Warn("The auth flag was not set; the API is accessible without authentication")
+		}
+
 		ringSupervisor, _ := command.Flags().GetBool("ring-supervisor")
 		installationGroupSupervisor, _ := command.Flags().GetBool("installationgroup-supervisor")
+		driftSupervisor, _ := command.Flags().GetBool("drift-supervisor")
 		if !ringSupervisor && !installationGroupSupervisor {
 			logger.Warn("Server will be running with no supervisors. Only API functionality will work.")
 		}
@@ -125,8 +172,10 @@ var serverCmd = &cobra.Command{
 
 		logger.WithFields(logrus.Fields{
 			"build-hash":                   model.BuildHash,
+			"auth":                         authenticator != nil,
 			"ring-supervisor":              ringSupervisor,
 			"installationgroup-supervisor": installationGroupSupervisor,
+			"drift-supervisor":             driftSupervisor,
 			"store-version":                currentVersion,
 			"working-directory":            wd,
 		}).Info("Starting Mattermost Elrond Server")
@@ -134,10 +183,13 @@ var serverCmd = &cobra.Command{
 		deprecationWarnings(logger, command)
 
 		provisioningParams := elrond.ProvisioningParams{
-			ProvisionerGroupReleaseTimeout: provisionerGroupReleaseTimeout,
-			GrafanaURL:                     grafanaURL,
-			GrafanaTokens:                  grafanaTokens,
-			ThanosURL:                      thanosURL,
+			ProvisionerGroupReleaseTimeout:    provisionerGroupReleaseTimeout,
+			ProvisionerGroupUnstableThreshold: provisionerGroupUnstableThreshold,
+			ProvisionerGroupPollInterval:      provisionerGroupPollInterval,
+			GrafanaURL:                        grafanaURL,
+			GrafanaTokens:                     grafanaTokens,
+			ThanosURL:                         thanosURL,
+			ProvisionerTargets:                provisionerTargets,
 		}
 
 		// Setup the provisioner.
@@ -150,13 +202,27 @@ var serverCmd = &cobra.Command{
 			provisionerTokenEndpoint,
 		)
 
-		var multiDoer supervisor.MultiDoer
+		idempotencyRetention, _ := command.Flags().GetDuration("idempotency-retention")
+
+		blockedReleaseAction, _ := command.Flags().GetString("blocked-release-action")
+		if !model.IsValidBlockedReleaseAction(blockedReleaseAction) {
+			return errors.Errorf("invalid blocked release action %q, must be %s or %s", blockedReleaseAction, model.BlockedReleaseActionPause, model.BlockedReleaseActionRollback)
+		}
+
+		multiDoer := supervisor.MultiDoer{
+			supervisor.NewIdempotencySupervisor(sqlStore, idempotencyRetention, logger),
+		}
 		if ringSupervisor {
 			multiDoer = append(multiDoer, supervisor.NewRingSupervisor(sqlStore, elrondProvisioner, instanceID, logger))
 		}
 		if installationGroupSupervisor {
 			multiDoer = append(multiDoer, supervisor.NewInstallationGroupSupervisor(sqlStore, elrondProvisioner, instanceID, logger))
 		}
+		if driftSupervisor {
+			driftCheckInterval, _ := command.Flags().GetDuration("drift-check-interval")
+			driftRemediate, _ := command.Flags().GetBool("drift-remediate")
+			multiDoer = append(multiDoer, supervisor.NewDriftSupervisor(sqlStore, elrondProvisioner, instanceID, driftCheckInterval, driftRemediate, logger))
+		}
 
 		// Setup the supervisor to effect any requested changes. It is wrapped in a
 		// scheduler to trigger it periodically in addition to being poked by the API
@@ -176,11 +242,14 @@ var serverCmd = &cobra.Command{
 		router := mux.NewRouter()
 
 		api.Register(router, &api.Context{
-			Store:             sqlStore,
-			Supervisor:        supervisor,
-			Elrond:            elrondProvisioner,
-			Logger:            logger,
-			ProvisionerServer: provisionerServer,
+			Store:                sqlStore,
+			Supervisor:           supervisor,
+			Elrond:               elrondProvisioner,
+			Logger:               logger,
+			ProvisionerServer:    provisionerServer,
+			Authenticator:        authenticator,
+			IdempotencyRetention: idempotencyRetention,
+			BlockedReleaseAction: blockedReleaseAction,
 		})
 
 		listen, _ := command.Flags().GetString("listen")
@@ -226,3 +295,34 @@ var serverCmd = &cobra.Command{
 func deprecationWarnings(_ logrus.FieldLogger, _ *cobra.Command) {
 	// Add deprecation logic here.
 }
+
+// newAuthenticator builds the authenticator for API requests from the auth flags,
+// returning nil when authentication is disabled.
+func newAuthenticator(command *cobra.Command, sqlStore *store.SQLStore) (auth.Authenticator, error) {
+	enabled, _ := command.Flags().GetBool("auth")
+	if !enabled {
+		return nil, nil
+	}
+
+	chain := auth.Chain{auth.NewTokenAuthenticator(sqlStore)}
+
+	jwksFile, _ := command.Flags().GetString("auth-jwks-file")
+	if jwksFile != "" {
+		issuer, _ := command.Flags().GetString("auth-jwt-issuer")
+		audience, _ := command.Flags().GetString("auth-jwt-audience")
+		roleClaim, _ := command.Flags().GetString("auth-jwt-role-claim")
+
+		jwtAuthenticator, err := auth.NewJWTAuthenticator(auth.JWTConfig{
+			JWKSFile:  jwksFile,
+			Issuer:    issuer,
+			Audience:  audience,
+			RoleClaim: roleClaim,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to configure JWT authentication")
+		}
+		chain = append(chain, jwtAuthenticator)
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	statusCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The elrond server whose API will be queried.")
+	statusCmd.PersistentFlags().String("api-token", "", "The API token used to authenticate against the elrond server. Defaults to the ELROND_API_TOKEN environment variable.")
+	statusCmd.Flags().Bool("json", false, "Whether to print the version matrix as JSON instead of a table.")
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which release every ring and installation group runs.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		matrix, err := client.GetRingMatrix()
+		if err != nil {
+			return errors.Wrap(err, "failed to query ring matrix")
+		}
+
+		outputToJSON, _ := command.Flags().GetBool("json")
+		if outputToJSON {
+			if err = printJSON(matrix); err != nil {
+				return errors.Wrap(err, "failed to print ring matrix response")
+			}
+
+			return nil
+		}
+
+		table := tablewriter.NewTable(os.Stdout)
+		table.Header("RING", "INSTALLATION GROUP", "STATE", "ACTIVE RELEASE", "DESIRED RELEASE", "SINCE RELEASE", "DRIFT")
+
+		for _, ring := range matrix.Rings {
+			if appendErr := table.Append([]interface{}{
+				ring.Name,
+				"",
+				ring.State,
+				ring.ActiveRelease.String(),
+				ring.DesiredRelease.String(),
+				formatSinceRelease(ring.SecondsSinceRelease),
+				strings.Join(ring.Drift, ", "),
+			}); appendErr != nil {
+				return errors.Wrap(appendErr, "failed to append row to table")
+			}
+
+			for _, installationGroup := range ring.InstallationGroups {
+				if appendErr := table.Append([]interface{}{
+					ring.Name,
+					installationGroup.Name,
+					installationGroup.State,
+					installationGroup.ActiveRelease.String(),
+					installationGroup.DesiredRelease.String(),
+					formatSinceRelease(installationGroup.SecondsSinceRelease),
+					strings.Join(installationGroup.Drift, ", "),
+				}); appendErr != nil {
+					return errors.Wrap(appendErr, "failed to append row to table")
+				}
+			}
+		}
+		if renderErr := table.Render(); renderErr != nil {
+			return errors.Wrap(renderErr, "failed to render table")
+		}
+
+		return nil
+	},
+}
+
+func formatSinceRelease(seconds int64) string {
+	if seconds == 0 {
+		return "never"
+	}
+
+	return (time.Duration(seconds) * time.Second).String()
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"net/url"
+	"os"
+
+	"github.com/mattermost/elrond/internal/auth"
+	"github.com/mattermost/elrond/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	tokenCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The elrond server whose API will be queried.")
+	tokenCmd.PersistentFlags().String("api-token", "", "The API token used to authenticate against the elrond server. Defaults to the ELROND_API_TOKEN environment variable.")
+
+	tokenCreateCmd.Flags().String("name", "", "A name describing who or what will use the API token.")
+	tokenCreateCmd.Flags().String("role", model.RoleViewer, "The role granted to the API token. One of viewer, releaser or admin.")
+	tokenCreateCmd.MarkFlagRequired("name") //nolint
+
+	tokenListCmd.Flags().Int("page", 0, "The page of API tokens to fetch, starting at 0.")
+	tokenListCmd.Flags().Int("per-page", 100, "The number of API tokens to fetch per page.")
+	tokenListCmd.Flags().Bool("include-deleted", false, "Whether to include revoked API tokens.")
+	tokenListCmd.Flags().Bool("table", false, "Whether to display the returned API token list in a table or not")
+
+	tokenDeleteCmd.Flags().String("token", "", "The id of the API token to be revoked.")
+	tokenDeleteCmd.MarkFlagRequired("token") //nolint
+
+	tokenBootstrapCmd.Flags().String("database", "sqlite://elrond.db", "The database backing the elrond server.")
+	tokenBootstrapCmd.Flags().String("name", "bootstrap", "A name describing who or what will use the API token.")
+
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenDeleteCmd)
+	tokenCmd.AddCommand(tokenBootstrapCmd)
+}
+
+// newClient creates a client to the given elrond server, authenticating with the
+// API token from the api-token flag or the ELROND_API_TOKEN environment variable,
+// and sending the idempotency key from the idempotency-key flag if set.
+func newClient(command *cobra.Command, serverAddress string) *model.Client {
+	token, _ := command.Flags().GetString("api-token")
+	if token == "" {
+		token = os.Getenv("ELROND_API_TOKEN")
+	}
+
+	client := model.NewClient(serverAddress)
+	if token != "" {
+		client = model.NewClientWithToken(serverAddress, token)
+	}
+
+	idempotencyKey, _ := command.Flags().GetString("idempotency-key")
+	if idempotencyKey != "" {
+		client = client.WithIdempotencyKey(idempotencyKey)
+	}
+
+	return client
+}
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manipulate API tokens used to authenticate against the elrond server.",
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API token.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		name, _ := command.Flags().GetString("name")
+		role, _ := command.Flags().GetString("role")
+
+		apiToken, err := client.CreateAPIToken(&model.CreateAPITokenRequest{
+			Name: name,
+			Role: role,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to create API token")
+		}
+
+		if err = printJSON(apiToken); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List created API tokens.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		page, _ := command.Flags().GetInt("page")
+		perPage, _ := command.Flags().GetInt("per-page")
+		includeDeleted, _ := command.Flags().GetBool("include-deleted")
+		apiTokens, err := client.GetAPITokens(&model.GetAPITokensRequest{
+			Page:           page,
+			PerPage:        perPage,
+			IncludeDeleted: includeDeleted,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query API tokens")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewTable(os.Stdout)
+			table.Header("ID", "NAME", "ROLE", "CREATED BY")
+
+			for _, apiToken := range apiTokens {
+				if appendErr := table.Append([]interface{}{apiToken.ID, apiToken.Name, apiToken.Role, apiToken.CreatedBy}); appendErr != nil {
+					return errors.Wrap(appendErr, "failed to append row to table")
+				}
+			}
+			if renderErr := table.Render(); renderErr != nil {
+				return errors.Wrap(renderErr, "failed to render table")
+			}
+
+			return nil
+		}
+
+		if err = printJSON(apiTokens); err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var tokenDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Revoke an API token.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		client := newClient(command, serverAddress)
+
+		tokenID, _ := command.Flags().GetString("token")
+
+		if err := client.DeleteAPIToken(tokenID); err != nil {
+			return errors.Wrap(err, "failed to delete API token")
+		}
+
+		return nil
+	},
+}
+
+var tokenBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Create an admin API token directly in the database, to be used when no token exists yet.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		sqlStore, err := sqlStore(command)
+		if err != nil {
+			return err
+		}
+
+		token, err := auth.GenerateToken()
+		if err != nil {
+			return err
+		}
+
+		name, _ := command.Flags().GetString("name")
+		apiToken := &model.APIToken{
+			Name:      name,
+			Role:      model.RoleAdmin,
+			TokenHash: auth.HashToken(token),
+			CreatedBy: "bootstrap",
+		}
+		if err = sqlStore.CreateAPIToken(apiToken); err != nil {
+			return errors.Wrap(err, "failed to create API token")
+		}
+
+		return printJSON(&model.CreateAPITokenResponse{APIToken: apiToken, Token: token})
+	},
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+func init() {
+	topologyCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The elrond server whose API will be queried.")
+	topologyCmd.PersistentFlags().String("api-token", "", "The API token used to authenticate against the elrond server. Defaults to the ELROND_API_TOKEN environment variable.")
+
+	topologyExportCmd.Flags().String("format", "yaml", "The format of the export. One of yaml or json.")
+
+	topologyCmd.AddCommand(topologyExportCmd)
+}
+
+var topologyCmd = &cobra.Command{
+	Use:   "topology",
+	Short: "Manipulate the complete set of rings and installation groups of the elrond server.",
+}
+
+var topologyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the rings, installation groups, webhooks and releases, to be restored with 'elrond schema restore'.",
+	RunE: func(command *cobra.Command, _ []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		if _, err := url.Parse(serverAddress); err != nil {
+			return errors.Wrap(err, "provided server address not a valid address")
+		}
+
+		format, _ := command.Flags().GetString("format")
+		if format != "yaml" && format != "json" {
+			return errors.Errorf("unsupported format %s", format)
+		}
+
+		client := newClient(command, serverAddress)
+
+		export, err := client.ExportTopology()
+		if err != nil {
+			return errors.Wrap(err, "failed to export topology")
+		}
+
+		if format == "json" {
+			return printJSON(export)
+		}
+
+		data, err := yaml.Marshal(export)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode topology export")
+		}
+		fmt.Print(string(data))
+
+		return nil
+	},
+}
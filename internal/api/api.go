@@ -15,6 +15,10 @@ func Register(rootRouter *mux.Router, context *Context) {
 	apiRouter := rootRouter.PathPrefix("/api").Subrouter()
 	initRing(apiRouter, context)
 	initInstallationGroup(apiRouter, context)
+	initTopology(apiRouter, context)
+	initBlocklist(apiRouter, context)
 	initWebhook(apiRouter, context)
 	initSecurity(apiRouter, context)
+	initToken(apiRouter, context)
+	initOpenAPI(apiRouter, context)
 }
@@ -0,0 +1,262 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/webhook"
+	"github.com/mattermost/elrond/model"
+)
+
+// initBlocklist registers release blocklist endpoints on the given router.
+func initBlocklist(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc, role string) *contextHandler {
+		return newContextHandler(context, handler, role)
+	}
+
+	blocklistRouter := apiRouter.PathPrefix("/releases/blocklist").Subrouter()
+	blocklistRouter.Handle("", addContext(handleGetBlockedReleases, model.RoleViewer)).Methods("GET")
+	blocklistRouter.Handle("", addContext(handleBlockRelease, model.RoleReleaser)).Methods("POST")
+	blocklistRouter.Handle("", addContext(handleUnblockRelease, model.RoleAdmin)).Methods("DELETE")
+}
+
+// handleGetBlockedReleases responds to GET /api/releases/blocklist, returning the blocked
+// releases.
+func handleGetBlockedReleases(c *Context, w http.ResponseWriter, _ *http.Request) {
+	blockedReleases, err := c.Store.GetBlockedReleases()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query blocked releases")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if blockedReleases == nil {
+		blockedReleases = []*model.BlockedRelease{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, blockedReleases)
+}
+
+// handleBlockRelease responds to POST /api/releases/blocklist, blocking an image and
+// version from being released. Rings currently releasing the image and version are
+// paused or rolled back depending on the configured blocked release action.
+func handleBlockRelease(c *Context, w http.ResponseWriter, r *http.Request) {
+	blockReleaseRequest, err := model.NewBlockReleaseRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.WithField("blocked-release", blockReleaseRequest.Image+":"+blockReleaseRequest.Version)
+
+	existing, err := c.Store.GetBlockedRelease(blockReleaseRequest.Image, blockReleaseRequest.Version)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query blocked release")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if existing != nil {
+		c.Logger.Warn("release is already blocked")
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	blockedRelease := &model.BlockedRelease{
+		Image:     blockReleaseRequest.Image,
+		Version:   blockReleaseRequest.Version,
+		Reason:    blockReleaseRequest.Reason,
+		CreatedBy: c.Caller(),
+	}
+
+	rings, err := releasingRings(c, blockedRelease)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get rings releasing the blocked release")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if len(rings) > 0 {
+		var ringIDs []string
+		for _, ring := range rings {
+			ringIDs = append(ringIDs, ring.ID)
+		}
+
+		status, unlockOnce := lockRings(c, ringIDs)
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		defer unlockOnce()
+	}
+
+	if err = c.Store.CreateBlockedRelease(blockedRelease); err != nil {
+		c.Logger.WithError(err).Error("failed to create blocked release")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	action := c.BlockedReleaseAction
+	if action == "" {
+		action = model.BlockedReleaseActionPause
+	}
+
+	var webhookPayloads []*model.WebhookPayload
+	for _, ring := range rings {
+		oldState := ring.State
+		switch action {
+		case model.BlockedReleaseActionPause:
+			// Releases in progress are held by the supervisors before releasing further
+			// installation groups.
+			if ring.State == model.RingStateReleasePending {
+				ring.State = model.RingStateReleasePaused
+			}
+		case model.BlockedReleaseActionRollback:
+			if ring.State == model.RingStateReleasePending || ring.State == model.RingStateReleasePaused {
+				ring.State = model.RingStateStable
+			} else {
+				ring.State = model.RingStateReleaseRollbackRequested
+				if err = cancelPendingInstallationGroups(c, ring.ID); err != nil {
+					c.Logger.WithError(err).Error("failed to cancel pending installation group releases")
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+			}
+			ring.DesiredReleaseID = ring.ActiveReleaseID
+		}
+
+		if ring.State != oldState {
+			webhookPayloads = append(webhookPayloads, &model.WebhookPayload{
+				Type:      model.TypeRing,
+				ID:        ring.ID,
+				Name:      ring.Name,
+				NewState:  ring.State,
+				OldState:  oldState,
+				Timestamp: time.Now().UnixNano(),
+				Actor:     c.Caller(),
+				ExtraData: map[string]string{"Environment": c.Environment, "Event": "release-blocked", "BlockedRelease": blockedRelease.Image + ":" + blockedRelease.Version},
+			})
+		}
+	}
+
+	if len(rings) > 0 {
+		c.Logger.Debugf("Applying the %s action to %d rings releasing the blocked release", action, len(rings))
+		if err = c.Store.UpdateRings(rings); err != nil {
+			c.Logger.WithError(err).Error("failed to update rings releasing the blocked release")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	for _, payload := range webhookPayloads {
+		if err := webhook.SendToAllWebhooks(c.Store, payload, c.Logger.WithField("webhookEvent", payload.NewState)); err != nil {
+			c.Logger.WithError(err).Error("unable to process and send webhooks")
+		}
+	}
+
+	c.Supervisor.Do() //nolint
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	outputJSON(c, w, blockedRelease)
+}
+
+// handleUnblockRelease responds to DELETE /api/releases/blocklist?image={image}&version={version},
+// allowing the image and version to be released again.
+func handleUnblockRelease(c *Context, w http.ResponseWriter, r *http.Request) {
+	image := r.URL.Query().Get("image")
+	version := r.URL.Query().Get("version")
+	if image == "" || version == "" {
+		c.Logger.Warn("an image and a version are required to unblock a release")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.WithField("blocked-release", image+":"+version)
+
+	blockedRelease, err := c.Store.GetBlockedRelease(image, version)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query blocked release")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if blockedRelease == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err = c.Store.DeleteBlockedRelease(image, version); err != nil {
+		c.Logger.WithError(err).Error("failed to delete blocked release")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// releasingRings returns the rings with a release of the blocked image and version
+// pending or in progress.
+func releasingRings(c *Context, blockedRelease *model.BlockedRelease) ([]*model.Ring, error) {
+	rings, err := c.Store.GetRings(&model.RingFilter{PerPage: model.AllPerPage})
+	if err != nil {
+		return nil, err
+	}
+
+	var releasing []*model.Ring
+	for _, ring := range rings {
+		if !ring.IsReleasing() || ring.DesiredReleaseID == ring.ActiveReleaseID {
+			continue
+		}
+		release, err := c.Store.GetRingRelease(ring.DesiredReleaseID)
+		if err != nil {
+			return nil, err
+		}
+		if blockedRelease.Blocks(release) {
+			releasing = append(releasing, ring)
+		}
+	}
+
+	return releasing, nil
+}
+
+// cancelPendingInstallationGroups moves the installation groups of the ring that are
+// waiting to be released back to stable, on the release they were running.
+func cancelPendingInstallationGroups(c *Context, ringID string) error {
+	installationGroups, err := c.Store.GetInstallationGroupsForRing(ringID)
+	if err != nil {
+		return err
+	}
+
+	for _, installationGroup := range installationGroups {
+		if installationGroup.State != model.InstallationGroupReleasePending {
+			continue
+		}
+		installationGroup.State = model.InstallationGroupStable
+		installationGroup.DesiredReleaseID = installationGroup.ActiveReleaseID
+		if err = c.Store.UpdateInstallationGroup(installationGroup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkReleaseBlocked returns the status code to respond with when the given image and
+// version are blocked from being released.
+func checkReleaseBlocked(c *Context, image, version string) int {
+	blockedRelease, err := c.Store.GetBlockedRelease(image, version)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query blocked release")
+		return http.StatusInternalServerError
+	}
+	if blockedRelease != nil {
+		c.Logger.Warnf("refusing to release blocked release %s:%s: %s", image, version, blockedRelease.Reason)
+		return http.StatusBadRequest
+	}
+
+	return 0
+}
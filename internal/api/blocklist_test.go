@@ -0,0 +1,147 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/api"
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklist(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	ring, err := client.CreateRing(&model.CreateRingRequest{Name: "ring", Priority: 1, Image: "image", Version: "7.8.0"})
+	require.NoError(t, err)
+	ring.State = model.RingStateStable
+	require.NoError(t, sqlStore.UpdateRing(ring))
+
+	blockedReleases, err := client.GetBlockedReleases()
+	require.NoError(t, err)
+	require.Empty(t, blockedReleases)
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err := client.BlockRelease(&model.BlockReleaseRequest{Image: "image"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("pause a pending release", func(t *testing.T) {
+		ring, err := client.ReleaseRing(ring.ID, &model.RingReleaseRequest{Image: "image", Version: "7.9.0"})
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateReleasePending, ring.State)
+
+		blockedRelease, err := client.BlockRelease(&model.BlockReleaseRequest{Image: "image", Version: "7.9.0", Reason: "broken"})
+		require.NoError(t, err)
+		require.NotEmpty(t, blockedRelease.ID)
+		require.Equal(t, "broken", blockedRelease.Reason)
+
+		ring, err = client.GetRing(ring.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateReleasePaused, ring.State)
+
+		ring.State = model.RingStateStable
+		ring.DesiredReleaseID = ring.ActiveReleaseID
+		require.NoError(t, sqlStore.UpdateRing(ring))
+	})
+
+	t.Run("already blocked", func(t *testing.T) {
+		_, err := client.BlockRelease(&model.BlockReleaseRequest{Image: "image", Version: "7.9.0"})
+		require.EqualError(t, err, "failed with status code 409")
+	})
+
+	t.Run("refuse blocked releases", func(t *testing.T) {
+		_, err := client.ReleaseRing(ring.ID, &model.RingReleaseRequest{Image: "image", Version: "7.9.0"})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.ReleaseAllRings(&model.RingReleaseRequest{Image: "image", Version: "7.9.0"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		blockedReleases, err := client.GetBlockedReleases()
+		require.NoError(t, err)
+		require.Len(t, blockedReleases, 1)
+		require.Equal(t, "7.9.0", blockedReleases[0].Version)
+	})
+
+	t.Run("unblock", func(t *testing.T) {
+		err := client.UnblockRelease(&model.UnblockReleaseRequest{Image: "image", Version: "7.9.1"})
+		require.EqualError(t, err, "failed with status code 404")
+
+		err = client.UnblockRelease(&model.UnblockReleaseRequest{Image: "image", Version: "7.9.0"})
+		require.NoError(t, err)
+
+		ring, err := client.ReleaseRing(ring.ID, &model.RingReleaseRequest{Image: "image", Version: "7.9.0"})
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateReleasePending, ring.State)
+	})
+}
+
+func TestBlocklistRollback(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:                sqlStore,
+		Supervisor:           &mockSupervisor{},
+		Logger:               logger,
+		BlockedReleaseAction: model.BlockedReleaseActionRollback,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	pending, err := client.CreateRing(&model.CreateRingRequest{Name: "pending", Priority: 1, Image: "image", Version: "7.8.0"})
+	require.NoError(t, err)
+	inProgress, err := client.CreateRing(&model.CreateRingRequest{Name: "in-progress", Priority: 1, Image: "image", Version: "7.8.0"})
+	require.NoError(t, err)
+	for _, ring := range []*model.Ring{pending, inProgress} {
+		ring.State = model.RingStateStable
+		require.NoError(t, sqlStore.UpdateRing(ring))
+	}
+
+	_, err = client.ReleaseAllRings(&model.RingReleaseRequest{Image: "image", Version: "7.9.0"})
+	require.NoError(t, err)
+
+	inProgress, err = client.GetRing(inProgress.ID)
+	require.NoError(t, err)
+	inProgress.State = model.RingStateReleaseInProgress
+	require.NoError(t, sqlStore.UpdateRing(inProgress))
+
+	_, err = client.BlockRelease(&model.BlockReleaseRequest{Image: "image", Version: "7.9.0"})
+	require.NoError(t, err)
+
+	pending, err = client.GetRing(pending.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateStable, pending.State)
+	require.Equal(t, pending.ActiveReleaseID, pending.DesiredReleaseID)
+
+	inProgress, err = client.GetRing(inProgress.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateReleaseRollbackRequested, inProgress.State)
+	require.Equal(t, inProgress.ActiveReleaseID, inProgress.DesiredReleaseID)
+}
@@ -4,9 +4,36 @@
 
 package api_test
 
+import "github.com/mattermost/elrond/model"
+
 type mockSupervisor struct {
 }
 
 func (s *mockSupervisor) Do() error {
 	return nil
 }
+
+type mockElrond struct {
+	ProvisionerTargets map[string]bool
+	ProvisionerGroups  map[string]bool
+	Groups             []*model.ProvisionerGroup
+}
+
+func (e *mockElrond) ProvisionerTargetExists(target string) bool {
+	return target == "" || e.ProvisionerTargets[target]
+}
+
+func (e *mockElrond) ProvisionerGroupExists(_, provisionerGroupID string) (bool, error) {
+	return e.ProvisionerGroups[provisionerGroupID], nil
+}
+
+func (e *mockElrond) GetProvisionerGroups(target string) ([]*model.ProvisionerGroup, error) {
+	var groups []*model.ProvisionerGroup
+	for _, group := range e.Groups {
+		if group.ProvisionerTarget == target {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
+}
@@ -5,6 +5,9 @@
 package api
 
 import (
+	"time"
+
+	"github.com/mattermost/elrond/internal/auth"
 	"github.com/mattermost/elrond/model"
 	"github.com/sirupsen/logrus"
 )
@@ -18,6 +21,7 @@ type Supervisor interface {
 type Store interface {
 	CreateRing(ring *model.Ring, installationGroup *model.InstallationGroup) error
 	GetRing(ringID string) (*model.Ring, error)
+	GetRingByName(name string) (*model.Ring, error)
 	GetRings(filter *model.RingFilter) ([]*model.Ring, error)
 	UpdateRing(ring *model.Ring) error
 	UpdateRings(rings []*model.Ring) error
@@ -28,6 +32,11 @@ type Store interface {
 	LockRingAPI(ringID string) error
 	UnlockRingAPI(ringID string) error
 	DeleteRing(ringID string) error
+	GetRingDependencies() (map[string][]string, error)
+	SetRingDependencies(ringID string, dependsOn []string) error
+	ApplyTopology(plan *model.TopologyPlan, releaseID string) ([]*model.Ring, error)
+	ExportTopology() (*model.TopologyExport, error)
+	ImportRings(ringImport *model.RingImport) ([]*model.Ring, error)
 
 	GetInstallationGroupsForRings(filter *model.RingFilter) (map[string][]*model.InstallationGroup, error)
 	GetInstallationGroupsForRing(ringID string) ([]*model.InstallationGroup, error)
@@ -35,22 +44,43 @@ type Store interface {
 	DeleteRingInstallationGroup(ringID string, installationGroup string) error
 	UpdateInstallationGroup(installationGroup *model.InstallationGroup) error
 	GetInstallationGroupByID(installationGroupID string) (*model.InstallationGroup, error)
+	GetInstallationGroupByName(name string) (*model.InstallationGroup, error)
+	GetInstallationGroups(filter *model.InstallationGroupFilter) ([]*model.InstallationGroup, error)
+	MoveInstallationGroup(installationGroupID, fromRingID, toRingID string) error
 	LockRingInstallationGroup(installationGroupID, lockerID string) (bool, error)
 	UnlockRingInstallationGroup(installationGroupID, lockerID string, force bool) (bool, error)
 
 	GetRingRelease(releaseID string) (*model.RingRelease, error)
+	GetRingReleases(filter *model.RingReleaseFilter) ([]*model.RingRelease, error)
 	GetOrCreateRingRelease(ringRelease *model.RingRelease) (*model.RingRelease, error)
 	GetUnlockedRingsPendingWork() ([]*model.Ring, error)
 	GetRingsInPendingState() ([]*model.Ring, error)
 
+	GetBlockedRelease(image, version string) (*model.BlockedRelease, error)
+	GetBlockedReleases() ([]*model.BlockedRelease, error)
+	CreateBlockedRelease(blockedRelease *model.BlockedRelease) error
+	DeleteBlockedRelease(image, version string) error
+
 	CreateWebhook(webhook *model.Webhook) error
 	GetWebhook(webhookID string) (*model.Webhook, error)
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
 	DeleteWebhook(webhookID string) error
+
+	CreateAPIToken(apiToken *model.APIToken) error
+	GetAPIToken(apiTokenID string) (*model.APIToken, error)
+	GetAPITokens(filter *model.APITokenFilter) ([]*model.APIToken, error)
+	DeleteAPIToken(apiTokenID string) error
+
+	ReserveIdempotencyKey(record *model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+	CompleteIdempotencyRecord(key string, statusCode int, body []byte) error
+	DeleteIdempotencyRecord(key string) error
 }
 
 // Elrond describes the interface.
 type Elrond interface {
+	ProvisionerTargetExists(target string) bool
+	ProvisionerGroupExists(target, provisionerGroupID string) (bool, error)
+	GetProvisionerGroups(target string) ([]*model.ProvisionerGroup, error)
 }
 
 // Context provides the API with all necessary data and interfaces for responding to requests.
@@ -64,14 +94,35 @@ type Context struct {
 	Environment       string
 	Logger            logrus.FieldLogger
 	ProvisionerServer string
+	Authenticator     auth.Authenticator
+	Identity          *auth.Identity
+	// IdempotencyRetention is how long responses to requests made with an
+	// idempotency key are replayed. Defaults to DefaultIdempotencyRetention.
+	IdempotencyRetention time.Duration
+	// BlockedReleaseAction is applied to the rings releasing an image and version when
+	// it is blocked. Defaults to model.BlockedReleaseActionPause.
+	BlockedReleaseAction string
 }
 
 // Clone creates a shallow copy of context, allowing clones to apply per-request changes.
 func (c *Context) Clone() *Context {
 	return &Context{
-		Store:      c.Store,
-		Supervisor: c.Supervisor,
-		Elrond:     c.Elrond,
-		Logger:     c.Logger,
+		Store:                c.Store,
+		Supervisor:           c.Supervisor,
+		Elrond:               c.Elrond,
+		Logger:               c.Logger,
+		Authenticator:        c.Authenticator,
+		IdempotencyRetention: c.IdempotencyRetention,
+		BlockedReleaseAction: c.BlockedReleaseAction,
 	}
 }
+
+// Caller returns the subject of the authenticated caller, or an empty string when
+// authentication is disabled.
+func (c *Context) Caller() string {
+	if c.Identity == nil {
+		return ""
+	}
+
+	return c.Identity.Subject
+}
@@ -16,6 +16,7 @@ type contextHandlerFunc func(c *Context, w http.ResponseWriter, r *http.Request)
 type contextHandler struct {
 	context *Context
 	handler contextHandlerFunc
+	role    string
 }
 
 func (h contextHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -26,12 +27,49 @@ func (h contextHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"request": context.RequestID,
 	})
 
+	if !authorize(context, w, r, h.role) {
+		return
+	}
+
 	h.handler(context, w, r)
 }
 
-func newContextHandler(context *Context, handler contextHandlerFunc) *contextHandler {
+// newContextHandler wraps the given handler, requiring callers to hold at least the
+// given role. An empty role leaves the endpoint accessible without authentication.
+func newContextHandler(context *Context, handler contextHandlerFunc, role string) *contextHandler {
 	return &contextHandler{
 		context: context,
 		handler: handler,
+		role:    role,
+	}
+}
+
+// authorize authenticates the caller and checks it holds the required role, writing
+// the appropriate status code and returning false when the request must not proceed.
+func authorize(c *Context, w http.ResponseWriter, r *http.Request, role string) bool {
+	if c.Authenticator == nil || role == "" {
+		return true
+	}
+
+	identity, err := c.Authenticator.Authenticate(r)
+	if err != nil {
+		c.Logger.WithError(err).Warn("failed to authenticate request")
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+
+	c.Identity = identity
+	c.Logger = c.Logger.WithFields(log.Fields{
+		"caller":      identity.Subject,
+		"auth_method": identity.Method,
+	})
+
+	if !model.RoleAllows(identity.Role, role) {
+		c.Logger.Warnf("caller with role %s requires role %s", identity.Role, role)
+		w.WriteHeader(http.StatusForbidden)
+		return false
 	}
+
+	return true
 }
@@ -0,0 +1,168 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/elrond/model"
+)
+
+// DefaultIdempotencyRetention is how long idempotency records are kept when no
+// retention is configured.
+const DefaultIdempotencyRetention = 24 * time.Hour
+
+// idempotencyRecorder captures the response written by a handler so that it can be
+// replayed for retries of the same request.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *idempotencyRecorder) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// idempotent wraps the given handler so that requests carrying an Idempotency-Key
+// header are applied at most once: retries replay the original response instead of
+// being processed again.
+func idempotent(handler contextHandlerFunc) contextHandlerFunc {
+	return func(c *Context, w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(model.IdempotencyKeyHeader)
+		if key == "" {
+			handler(c, w, r)
+			return
+		}
+		c.Logger = c.Logger.WithField("idempotency-key", key)
+
+		if len(key) > model.IdempotencyKeyMaxLength {
+			c.Logger.Warnf("idempotency key exceeds %d characters", model.IdempotencyKeyMaxLength)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to read request body")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &model.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: requestFingerprint(c, r, body),
+		}
+
+		existing, err := c.Store.ReserveIdempotencyKey(record)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to reserve idempotency key")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if existing != nil && existing.CreateAt < time.Now().Add(-c.idempotencyRetention()).UnixMilli() {
+			c.Logger.Debug("idempotency record expired, processing request again")
+			if err = c.Store.DeleteIdempotencyRecord(key); err != nil {
+				c.Logger.WithError(err).Error("failed to delete expired idempotency record")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			existing, err = c.Store.ReserveIdempotencyKey(record)
+			if err != nil {
+				c.Logger.WithError(err).Error("failed to reserve idempotency key")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if existing != nil {
+			replayIdempotentResponse(c, w, existing, record.Fingerprint)
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: w}
+		handler(c, recorder, r)
+
+		if recorder.statusCode == 0 {
+			recorder.statusCode = http.StatusOK
+		}
+
+		// Server errors are not stored so that the request can be retried.
+		if recorder.statusCode >= http.StatusInternalServerError {
+			if err = c.Store.DeleteIdempotencyRecord(key); err != nil {
+				c.Logger.WithError(err).Error("failed to release idempotency key")
+			}
+			return
+		}
+
+		if err = c.Store.CompleteIdempotencyRecord(key, recorder.statusCode, recorder.body.Bytes()); err != nil {
+			c.Logger.WithError(err).Error("failed to store idempotent response")
+		}
+	}
+}
+
+func replayIdempotentResponse(c *Context, w http.ResponseWriter, record *model.IdempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		c.Logger.Warn("idempotency key reused with a different request")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+	if !record.IsComplete() {
+		c.Logger.Warn("request with the same idempotency key is still in progress")
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	c.Logger.Info("replaying response of request with the same idempotency key")
+
+	w.Header().Set(model.IdempotentReplayedHeader, "true")
+	if len(record.Body) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(record.StatusCode)
+	if _, err := w.Write(record.Body); err != nil {
+		c.Logger.WithError(err).Error("failed to write replayed response")
+	}
+}
+
+// requestFingerprint identifies the request made under an idempotency key, so that
+// a key reused for a different request or by a different caller can be detected.
+func requestFingerprint(c *Context, r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Caller()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(r.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *Context) idempotencyRetention() time.Duration {
+	if c.IdempotencyRetention <= 0 {
+		return DefaultIdempotencyRetention
+	}
+
+	return c.IdempotencyRetention
+}
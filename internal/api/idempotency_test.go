@@ -0,0 +1,114 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/api"
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	createRequest := &model.CreateRingRequest{Name: "ring-1", Priority: 1, Image: "image", Version: "1.0.0"}
+
+	t.Run("retried create ring is replayed", func(t *testing.T) {
+		keyedClient := client.WithIdempotencyKey("create-ring-1")
+
+		ring1, err := keyedClient.CreateRing(createRequest)
+		require.NoError(t, err)
+
+		ring2, err := keyedClient.CreateRing(createRequest)
+		require.NoError(t, err)
+		require.Equal(t, ring1.ID, ring2.ID)
+
+		rings, err := client.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, rings, 1)
+	})
+
+	t.Run("replayed header", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/rings", strings.NewReader(`{"Name":"ring-3","Priority":1}`))
+		require.NoError(t, err)
+		req.Header.Set(model.IdempotencyKeyHeader, "create-ring-2")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		require.Empty(t, resp.Header.Get(model.IdempotentReplayedHeader))
+
+		req, err = http.NewRequest(http.MethodPost, ts.URL+"/api/rings", strings.NewReader(`{"Name":"ring-3","Priority":1}`))
+		require.NoError(t, err)
+		req.Header.Set(model.IdempotencyKeyHeader, "create-ring-2")
+
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		require.Equal(t, "true", resp.Header.Get(model.IdempotentReplayedHeader))
+	})
+
+	t.Run("key reused with different request", func(t *testing.T) {
+		_, err := client.WithIdempotencyKey("create-ring-1").CreateRing(&model.CreateRingRequest{Name: "ring-2", Priority: 2})
+		require.EqualError(t, err, "failed with status code 422")
+	})
+
+	t.Run("key held by another request", func(t *testing.T) {
+		_, err := sqlStore.ReserveIdempotencyKey(&model.IdempotencyRecord{Key: "held", Fingerprint: "unknown"})
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/rings/release", bytes.NewReader(nil))
+		require.NoError(t, err)
+		req.Header.Set(model.IdempotencyKeyHeader, "held")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		_, err := client.WithIdempotencyKey(strings.Repeat("a", model.IdempotencyKeyMaxLength+1)).CreateRing(createRequest)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("client errors are replayed", func(t *testing.T) {
+		keyedClient := client.WithIdempotencyKey("invalid-ring")
+
+		_, err := keyedClient.CreateRing(&model.CreateRingRequest{Name: "no-priority"})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = keyedClient.CreateRing(&model.CreateRingRequest{Name: "no-priority"})
+		require.EqualError(t, err, "failed with status code 400")
+
+		record, err := sqlStore.GetIdempotencyRecord("invalid-ring")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, record.StatusCode)
+	})
+}
@@ -5,20 +5,85 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/webhook"
 	"github.com/mattermost/elrond/model"
 )
 
-// initRing registers ring endpoints on the given router.
+// initInstallationGroup registers installation group endpoints on the given router.
 func initInstallationGroup(apiRouter *mux.Router, context *Context) {
-	addContext := func(handler contextHandlerFunc) *contextHandler {
-		return newContextHandler(context, handler)
+	addContext := func(handler contextHandlerFunc, role string) *contextHandler {
+		return newContextHandler(context, handler, role)
 	}
 
+	apiRouter.Handle("/installationgroups", addContext(handleGetInstallationGroups, model.RoleViewer)).Methods("GET")
+	apiRouter.Handle("/installationgroups/verify", addContext(handleVerifyInstallationGroups, model.RoleViewer)).Methods("GET")
+
 	installationGroupRouter := apiRouter.PathPrefix("/installationgroup/{installationgroup:[A-Za-z0-9]{26}}").Subrouter()
-	installationGroupRouter.Handle("/update", addContext(handleUpdateInstallationGroup)).Methods("POST")
+	installationGroupRouter.Handle("", addContext(handleGetInstallationGroup, model.RoleViewer)).Methods("GET")
+	installationGroupRouter.Handle("/update", addContext(handleUpdateInstallationGroup, model.RoleReleaser)).Methods("POST")
+	installationGroupRouter.Handle("/move", addContext(handleMoveInstallationGroup, model.RoleReleaser)).Methods("POST")
+}
+
+// handleGetInstallationGroups responds to GET /api/installationgroups, returning a list of
+// installation groups.
+func handleGetInstallationGroups(c *Context, w http.ResponseWriter, r *http.Request) {
+	page, perPage, _, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.InstallationGroupFilter{
+		RingID:             r.URL.Query().Get("ring"),
+		State:              r.URL.Query().Get("state"),
+		ProvisionerGroupID: r.URL.Query().Get("provisioner_group_id"),
+		Page:               page,
+		PerPage:            perPage,
+	}
+
+	installationGroups, err := c.Store.GetInstallationGroups(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation groups")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if installationGroups == nil {
+		installationGroups = []*model.InstallationGroup{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, installationGroups)
+}
+
+// handleGetInstallationGroup responds to GET /api/installationgroup/{installationgroup},
+// returning the installation group in question.
+func handleGetInstallationGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationGroupID := vars["installationgroup"]
+	c.Logger = c.Logger.WithField("installationgroup", installationGroupID)
+
+	installationGroup, err := c.Store.GetInstallationGroupByID(installationGroupID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation group")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if installationGroup == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, installationGroup)
 }
 
 // handleUpdateInstallationGroup responds to POST /api/installationgroup/{installationgroup}/update,
@@ -30,19 +95,19 @@ func handleUpdateInstallationGroup(c *Context, w http.ResponseWriter, r *http.Re
 		WithField("installationgroup", installationGroupID).
 		WithField("action", "update-installation-group")
 
-	installationGroup, err := c.Store.GetInstallationGroupByID(installationGroupID)
+	updateInstallationGroupRequest, err := model.NewUpdateInstallationGroupRequestFromReader(r.Body)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to update installation group")
+		c.Logger.WithError(err).Error("failed to deserialize ring update request body")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	updateInstallationGroupRequest, err := model.NewUpdateInstallationGroupRequestFromReader(r.Body)
-	if err != nil {
-		c.Logger.WithError(err).Error("failed to deserialize ring update request body")
-		w.WriteHeader(http.StatusInternalServerError)
+	installationGroup, status, unlockInstallationGroup := lockRingInstallationGroup(c, installationGroupID)
+	if status != 0 {
+		w.WriteHeader(status)
 		return
 	}
+	defer unlockInstallationGroup()
 
 	if updateInstallationGroupRequest.Name != "" {
 		installationGroup.Name = updateInstallationGroupRequest.Name
@@ -52,8 +117,34 @@ func handleUpdateInstallationGroup(c *Context, w http.ResponseWriter, r *http.Re
 		installationGroup.SoakTime = updateInstallationGroupRequest.SoakTime
 	}
 
+	provisionerTarget := installationGroup.ProvisionerTarget
+	if updateInstallationGroupRequest.ProvisionerTarget != nil {
+		provisionerTarget = *updateInstallationGroupRequest.ProvisionerTarget
+	}
+	provisionerGroupID := installationGroup.ProvisionerGroupID
 	if updateInstallationGroupRequest.ProvisionerGroupID != "" {
-		installationGroup.ProvisionerGroupID = updateInstallationGroupRequest.ProvisionerGroupID
+		provisionerGroupID = updateInstallationGroupRequest.ProvisionerGroupID
+	}
+	if provisionerTarget != installationGroup.ProvisionerTarget || provisionerGroupID != installationGroup.ProvisionerGroupID {
+		if installationGroup.State != model.InstallationGroupStable {
+			c.Logger.Warnf("unable to change the provisioner group of an installation group in state %s", installationGroup.State)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if status := checkProvisionerGroup(c, installationGroup.ID, provisionerTarget, provisionerGroupID); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		installationGroup.ProvisionerTarget = provisionerTarget
+		installationGroup.ProvisionerGroupID = provisionerGroupID
+	}
+
+	if updateInstallationGroupRequest.Priority != nil {
+		installationGroup.Priority = *updateInstallationGroupRequest.Priority
+	}
+
+	if updateInstallationGroupRequest.Paused != nil {
+		installationGroup.Paused = *updateInstallationGroupRequest.Paused
 	}
 
 	if err = c.Store.UpdateInstallationGroup(installationGroup); err != nil {
@@ -66,3 +157,244 @@ func handleUpdateInstallationGroup(c *Context, w http.ResponseWriter, r *http.Re
 	w.WriteHeader(http.StatusAccepted)
 	outputJSON(c, w, installationGroup)
 }
+
+// handleMoveInstallationGroup responds to POST /api/installationgroup/{installationgroup}/move,
+// registering an installation group to another ring while keeping its identity and settings.
+// sample body:
+//
+//	{
+//	    "ringID": "abcdefghijklmnopqrstuvwxyz"
+//	}
+func handleMoveInstallationGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationGroupID := vars["installationgroup"]
+	c.Logger = c.Logger.
+		WithField("installationgroup", installationGroupID).
+		WithField("action", "move-installation-group")
+
+	moveInstallationGroupRequest, err := model.NewMoveInstallationGroupRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationGroup, status, unlockInstallationGroup := lockRingInstallationGroup(c, installationGroupID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockInstallationGroup()
+
+	fromRingID := installationGroup.RingID
+	toRingID := moveInstallationGroupRequest.RingID
+	c.Logger = c.Logger.WithField("from-ring", fromRingID).WithField("to-ring", toRingID)
+
+	if fromRingID == "" {
+		c.Logger.Warn("unable to move an installation group that is not registered to a ring")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if fromRingID == toRingID {
+		c.Logger.Warn("installation group is already registered to the ring")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	status, unlockRings := lockRings(c, []string{fromRingID, toRingID})
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockRings()
+
+	fromRing, err := c.Store.GetRing(fromRingID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query ring")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	toRing, err := c.Store.GetRing(toRingID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query ring")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if fromRing == nil || toRing == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if toRing.DeleteAt != 0 {
+		c.Logger.Warn("unable to move an installation group to a deleted ring")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if fromRing.APISecurityLock || toRing.APISecurityLock {
+		logSecurityLockConflict("ring", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if installationGroup.IsReleasing() {
+		c.Logger.Warnf("unable to move installation group while in state %s", installationGroup.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	for _, ring := range []*model.Ring{fromRing, toRing} {
+		if ring.IsReleasing() {
+			c.Logger.Warnf("unable to move installation group while ring %s is in state %s", ring.ID, ring.State)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err = c.Store.MoveInstallationGroup(installationGroup.ID, fromRing.ID, toRing.ID); err != nil {
+		c.Logger.WithError(err).Error("failed to move installation group")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	installationGroup.RingID = toRing.ID
+
+	for _, move := range []struct {
+		ring  *model.Ring
+		event string
+	}{
+		{fromRing, "installation-group-moved-out"},
+		{toRing, "installation-group-moved-in"},
+	} {
+		ring, event := move.ring, move.event
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeRing,
+			ID:        ring.ID,
+			Name:      ring.Name,
+			NewState:  ring.State,
+			OldState:  ring.State,
+			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
+			ExtraData: map[string]string{
+				"Environment":           c.Environment,
+				"Event":                 event,
+				"InstallationGroupID":   installationGroup.ID,
+				"InstallationGroupName": installationGroup.Name,
+			},
+		}
+		if err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", event)); err != nil {
+			c.Logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, installationGroup)
+}
+
+// handleVerifyInstallationGroups responds to GET /api/installationgroups/verify, checking the
+// provisioner group of every installation group registered to a ring.
+func handleVerifyInstallationGroups(c *Context, w http.ResponseWriter, _ *http.Request) {
+	installationGroups, err := c.Store.GetInstallationGroups(&model.InstallationGroupFilter{PerPage: model.AllPerPage})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation groups")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Provisioner group IDs are only unique within a provisioner target.
+	registered := make(map[[2]string][]*model.InstallationGroup)
+	for _, installationGroup := range installationGroups {
+		if installationGroup.RingID != "" && installationGroup.ProvisionerGroupID != "" {
+			key := [2]string{installationGroup.ProvisionerTarget, installationGroup.ProvisionerGroupID}
+			registered[key] = append(registered[key], installationGroup)
+		}
+	}
+
+	verifications := []*model.InstallationGroupVerification{}
+	for _, installationGroup := range installationGroups {
+		if installationGroup.RingID == "" {
+			continue
+		}
+
+		verification := &model.InstallationGroupVerification{
+			InstallationGroupID:   installationGroup.ID,
+			InstallationGroupName: installationGroup.Name,
+			RingID:                installationGroup.RingID,
+			ProvisionerGroupID:    installationGroup.ProvisionerGroupID,
+			ProvisionerTarget:     installationGroup.ProvisionerTarget,
+			Problems:              []string{},
+		}
+		verifications = append(verifications, verification)
+
+		if installationGroup.ProvisionerGroupID == "" {
+			verification.Problems = append(verification.Problems, "no provisioner group is registered")
+			continue
+		}
+
+		for _, other := range registered[[2]string{installationGroup.ProvisionerTarget, installationGroup.ProvisionerGroupID}] {
+			if other.ID != installationGroup.ID {
+				verification.Problems = append(verification.Problems, fmt.Sprintf("the provisioner group is also registered to installation group %s", other.Name))
+			}
+		}
+
+		if c.Elrond == nil {
+			continue
+		}
+		if !c.Elrond.ProvisionerTargetExists(installationGroup.ProvisionerTarget) {
+			verification.Problems = append(verification.Problems, fmt.Sprintf("the provisioner target %s is not configured", installationGroup.ProvisionerTarget))
+			continue
+		}
+		exists, err := c.Elrond.ProvisionerGroupExists(installationGroup.ProvisionerTarget, installationGroup.ProvisionerGroupID)
+		if err != nil {
+			verification.Problems = append(verification.Problems, fmt.Sprintf("failed to get the provisioner group: %s", err))
+		} else if !exists {
+			verification.Problems = append(verification.Problems, "the provisioner group does not exist")
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, verifications)
+}
+
+// checkProvisionerGroup returns the status code to respond with when the given provisioner
+// group of the provisioner target cannot be registered to the installation group with the
+// given ID, empty for a new registration, either because the target is not configured, the
+// group does not exist or another installation group registered to a ring uses it.
+func checkProvisionerGroup(c *Context, installationGroupID, provisionerTarget, provisionerGroupID string) int {
+	if c.Elrond != nil && !c.Elrond.ProvisionerTargetExists(provisionerTarget) {
+		c.Logger.Warnf("provisioner target %s is not configured", provisionerTarget)
+		return http.StatusBadRequest
+	}
+	if provisionerGroupID == "" {
+		return 0
+	}
+
+	installationGroups, err := c.Store.GetInstallationGroups(&model.InstallationGroupFilter{
+		ProvisionerGroupID: provisionerGroupID,
+		PerPage:            model.AllPerPage,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation groups of the provisioner group")
+		return http.StatusInternalServerError
+	}
+	for _, installationGroup := range installationGroups {
+		if installationGroup.RingID != "" && installationGroup.ID != installationGroupID && installationGroup.ProvisionerTarget == provisionerTarget {
+			c.Logger.Warnf("provisioner group %s is already registered to installation group %s", provisionerGroupID, installationGroup.Name)
+			return http.StatusConflict
+		}
+	}
+
+	if c.Elrond == nil {
+		return 0
+	}
+	exists, err := c.Elrond.ProvisionerGroupExists(provisionerTarget, provisionerGroupID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to check the provisioner group")
+		return http.StatusInternalServerError
+	}
+	if !exists {
+		c.Logger.Warnf("provisioner group %s does not exist", provisionerGroupID)
+		return http.StatusBadRequest
+	}
+
+	return 0
+}
@@ -0,0 +1,407 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/api"
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationGroups(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	t.Run("no installation groups", func(t *testing.T) {
+		installationGroups, err := client.GetInstallationGroups(&model.GetInstallationGroupsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Empty(t, installationGroups)
+	})
+
+	t.Run("unknown installation group", func(t *testing.T) {
+		installationGroup, err := client.GetInstallationGroup(model.NewID())
+		require.NoError(t, err)
+		require.Nil(t, installationGroup)
+	})
+
+	ring1 := &model.Ring{Name: "ring-1", State: model.RingStateStable}
+	err := sqlStore.CreateRing(ring1, &model.InstallationGroup{Name: "ig-1", State: model.InstallationGroupStable, ProvisionerGroupID: "pg1"})
+	require.NoError(t, err)
+	ring2 := &model.Ring{Name: "ring-2", State: model.RingStateStable}
+	err = sqlStore.CreateRing(ring2, &model.InstallationGroup{Name: "ig-2", State: model.InstallationGroupReleasePending, ProvisionerGroupID: "pg2"})
+	require.NoError(t, err)
+
+	t.Run("list installation groups", func(t *testing.T) {
+		installationGroups, err := client.GetInstallationGroups(&model.GetInstallationGroupsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, installationGroups, 2)
+		require.Equal(t, ring1.ID, installationGroups[0].RingID)
+
+		installationGroups, err = client.GetInstallationGroups(&model.GetInstallationGroupsRequest{PerPage: 1, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, installationGroups, 1)
+		require.Equal(t, "ig-2", installationGroups[0].Name)
+	})
+
+	t.Run("filter installation groups", func(t *testing.T) {
+		installationGroups, err := client.GetInstallationGroups(&model.GetInstallationGroupsRequest{RingID: ring2.ID, PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, installationGroups, 1)
+		require.Equal(t, "ig-2", installationGroups[0].Name)
+
+		installationGroups, err = client.GetInstallationGroups(&model.GetInstallationGroupsRequest{State: model.InstallationGroupStable, PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, installationGroups, 1)
+		require.Equal(t, "ig-1", installationGroups[0].Name)
+
+		installationGroups, err = client.GetInstallationGroups(&model.GetInstallationGroupsRequest{ProvisionerGroupID: "pg2", PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, installationGroups, 1)
+		require.Equal(t, "ig-2", installationGroups[0].Name)
+	})
+
+	t.Run("get installation group", func(t *testing.T) {
+		installationGroups, err := client.GetInstallationGroups(&model.GetInstallationGroupsRequest{PerPage: 10})
+		require.NoError(t, err)
+
+		installationGroup, err := client.GetInstallationGroup(installationGroups[1].ID)
+		require.NoError(t, err)
+		require.Equal(t, "ig-2", installationGroup.Name)
+		require.Equal(t, ring2.ID, installationGroup.RingID)
+	})
+
+	t.Run("update priority", func(t *testing.T) {
+		installationGroups, err := client.GetInstallationGroups(&model.GetInstallationGroupsRequest{PerPage: 10})
+		require.NoError(t, err)
+
+		priority := 5
+		installationGroup, err := client.UpdateInstallationGroup(installationGroups[0].ID, &model.UpdateInstallationGroupRequest{Priority: &priority})
+		require.NoError(t, err)
+		require.Equal(t, 5, installationGroup.Priority)
+
+		installationGroup, err = client.UpdateInstallationGroup(installationGroups[0].ID, &model.UpdateInstallationGroupRequest{SoakTime: 60})
+		require.NoError(t, err)
+		require.Equal(t, 5, installationGroup.Priority)
+
+		priority = 0
+		installationGroup, err = client.UpdateInstallationGroup(installationGroups[0].ID, &model.UpdateInstallationGroupRequest{Priority: &priority})
+		require.NoError(t, err)
+		require.Zero(t, installationGroup.Priority)
+	})
+}
+
+func TestMoveInstallationGroup(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	installationGroup := &model.InstallationGroup{Name: "ig-1", State: model.InstallationGroupStable, SoakTime: 30, ProvisionerGroupID: "pg1"}
+	canary := &model.Ring{Name: "canary", State: model.RingStateStable}
+	err := sqlStore.CreateRing(canary, installationGroup)
+	require.NoError(t, err)
+	production := &model.Ring{Name: "production", State: model.RingStateStable}
+	err = sqlStore.CreateRing(production, nil)
+	require.NoError(t, err)
+
+	t.Run("missing ring", func(t *testing.T) {
+		_, err = client.MoveInstallationGroup(installationGroup.ID, &model.MoveInstallationGroupRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("unknown installation group", func(t *testing.T) {
+		_, err = client.MoveInstallationGroup(model.NewID(), &model.MoveInstallationGroupRequest{RingID: production.ID})
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("unknown ring", func(t *testing.T) {
+		_, err = client.MoveInstallationGroup(installationGroup.ID, &model.MoveInstallationGroupRequest{RingID: model.NewID()})
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("same ring", func(t *testing.T) {
+		_, err = client.MoveInstallationGroup(installationGroup.ID, &model.MoveInstallationGroupRequest{RingID: canary.ID})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("ring mid-release", func(t *testing.T) {
+		production.State = model.RingStateReleaseInProgress
+		require.NoError(t, sqlStore.UpdateRing(production))
+		defer func() {
+			production.State = model.RingStateStable
+			require.NoError(t, sqlStore.UpdateRing(production))
+		}()
+
+		_, err = client.MoveInstallationGroup(installationGroup.ID, &model.MoveInstallationGroupRequest{RingID: production.ID})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("installation group mid-release", func(t *testing.T) {
+		installationGroup.State = model.InstallationGroupReleaseRequested
+		require.NoError(t, sqlStore.UpdateInstallationGroup(installationGroup))
+		defer func() {
+			installationGroup.State = model.InstallationGroupStable
+			require.NoError(t, sqlStore.UpdateInstallationGroup(installationGroup))
+		}()
+
+		_, err = client.MoveInstallationGroup(installationGroup.ID, &model.MoveInstallationGroupRequest{RingID: production.ID})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("security lock", func(t *testing.T) {
+		require.NoError(t, sqlStore.LockRingAPI(canary.ID))
+		defer sqlStore.UnlockRingAPI(canary.ID) //nolint
+
+		_, err = client.MoveInstallationGroup(installationGroup.ID, &model.MoveInstallationGroupRequest{RingID: production.ID})
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("move", func(t *testing.T) {
+		moved, err := client.MoveInstallationGroup(installationGroup.ID, &model.MoveInstallationGroupRequest{RingID: production.ID})
+		require.NoError(t, err)
+		require.Equal(t, installationGroup.ID, moved.ID)
+		require.Equal(t, production.ID, moved.RingID)
+		require.Equal(t, 30, moved.SoakTime)
+
+		ring, err := client.GetRing(canary.ID)
+		require.NoError(t, err)
+		require.Empty(t, ring.InstallationGroups)
+
+		ring, err = client.GetRing(production.ID)
+		require.NoError(t, err)
+		require.Len(t, ring.InstallationGroups, 1)
+	})
+}
+
+func TestProvisionerGroupValidation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Elrond:     &mockElrond{ProvisionerGroups: map[string]bool{"pg1": true, "pg2": true, "pg3": true}},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	ring, err := client.CreateRing(&model.CreateRingRequest{
+		Name:              "ring-1",
+		Priority:          1,
+		InstallationGroup: &model.InstallationGroup{Name: "ig-1", ProvisionerGroupID: "pg1"},
+	})
+	require.NoError(t, err)
+
+	t.Run("create ring with unknown provisioner group", func(t *testing.T) {
+		_, err = client.CreateRing(&model.CreateRingRequest{
+			Name:              "ring-2",
+			Priority:          2,
+			InstallationGroup: &model.InstallationGroup{Name: "ig-2", ProvisionerGroupID: "unknown"},
+		})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("create ring with registered provisioner group", func(t *testing.T) {
+		_, err = client.CreateRing(&model.CreateRingRequest{
+			Name:              "ring-2",
+			Priority:          2,
+			InstallationGroup: &model.InstallationGroup{Name: "ig-2", ProvisionerGroupID: "pg1"},
+		})
+		require.EqualError(t, err, "failed with status code 409")
+	})
+
+	t.Run("register unknown provisioner group", func(t *testing.T) {
+		_, err = client.RegisterRingInstallationGroup(ring.ID, &model.RegisterInstallationGroupRequest{Name: "ig-2", ProvisionerGroupID: "unknown"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("register registered provisioner group", func(t *testing.T) {
+		_, err = client.RegisterRingInstallationGroup(ring.ID, &model.RegisterInstallationGroupRequest{Name: "ig-2", ProvisionerGroupID: "pg1"})
+		require.EqualError(t, err, "failed with status code 409")
+	})
+
+	_, err = client.RegisterRingInstallationGroup(ring.ID, &model.RegisterInstallationGroupRequest{Name: "ig-2", ProvisionerGroupID: "pg2"})
+	require.NoError(t, err)
+	installationGroup, err := sqlStore.GetInstallationGroupByName("ig-2")
+	require.NoError(t, err)
+
+	t.Run("update to unknown provisioner group", func(t *testing.T) {
+		_, err = client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{ProvisionerGroupID: "unknown"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("update to registered provisioner group", func(t *testing.T) {
+		_, err = client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{ProvisionerGroupID: "pg1"})
+		require.EqualError(t, err, "failed with status code 409")
+	})
+
+	t.Run("update provisioner group", func(t *testing.T) {
+		updated, err := client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{ProvisionerGroupID: "pg3"})
+		require.NoError(t, err)
+		require.Equal(t, "pg3", updated.ProvisionerGroupID)
+	})
+
+	t.Run("update unknown installation group", func(t *testing.T) {
+		_, err = client.UpdateInstallationGroup(model.NewID(), &model.UpdateInstallationGroupRequest{ProvisionerGroupID: "pg2"})
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("update provisioner group while releasing", func(t *testing.T) {
+		releasing, err := sqlStore.GetInstallationGroupByID(installationGroup.ID)
+		require.NoError(t, err)
+		releasing.State = model.InstallationGroupReleaseRequested
+		require.NoError(t, sqlStore.UpdateInstallationGroup(releasing))
+		defer func() {
+			releasing.State = model.InstallationGroupStable
+			require.NoError(t, sqlStore.UpdateInstallationGroup(releasing))
+		}()
+
+		_, err = client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{ProvisionerGroupID: "pg2"})
+		require.EqualError(t, err, "failed with status code 400")
+
+		// Other settings can still be changed.
+		priority := 3
+		updated, err := client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{Priority: &priority})
+		require.NoError(t, err)
+		require.Equal(t, "pg3", updated.ProvisionerGroupID)
+	})
+
+	t.Run("update locked installation group", func(t *testing.T) {
+		locked, err := sqlStore.LockRingInstallationGroup(installationGroup.ID, "supervisor")
+		require.NoError(t, err)
+		require.True(t, locked)
+		defer sqlStore.UnlockRingInstallationGroup(installationGroup.ID, "supervisor", false) //nolint
+
+		_, err = client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{ProvisionerGroupID: "pg2"})
+		require.EqualError(t, err, "failed with status code 409")
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		verifications, err := client.VerifyInstallationGroups()
+		require.NoError(t, err)
+		require.Len(t, verifications, 2)
+		for _, verification := range verifications {
+			require.Empty(t, verification.Problems, verification.InstallationGroupName)
+		}
+
+		// Registrations made before validation, or groups deleted from the provisioner,
+		// are reported.
+		broken := &model.Ring{Name: "ring-3", State: model.RingStateStable}
+		err = sqlStore.CreateRing(broken, &model.InstallationGroup{Name: "ig-3", State: model.InstallationGroupStable, ProvisionerGroupID: "pg1"})
+		require.NoError(t, err)
+		missing := &model.Ring{Name: "ring-4", State: model.RingStateStable}
+		err = sqlStore.CreateRing(missing, &model.InstallationGroup{Name: "ig-4", State: model.InstallationGroupStable, ProvisionerGroupID: "deleted"})
+		require.NoError(t, err)
+
+		verifications, err = client.VerifyInstallationGroups()
+		require.NoError(t, err)
+		problems := make(map[string][]string)
+		for _, verification := range verifications {
+			problems[verification.InstallationGroupName] = verification.Problems
+		}
+		require.Equal(t, []string{"the provisioner group is also registered to installation group ig-3"}, problems["ig-1"])
+		require.Empty(t, problems["ig-2"])
+		require.Equal(t, []string{"the provisioner group is also registered to installation group ig-1"}, problems["ig-3"])
+		require.Equal(t, []string{"the provisioner group does not exist"}, problems["ig-4"])
+	})
+}
+
+func TestProvisionerTargetValidation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Elrond: &mockElrond{
+			ProvisionerTargets: map[string]bool{"eu": true},
+			ProvisionerGroups:  map[string]bool{"pg1": true, "pg2": true},
+		},
+		Logger: logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	ring, err := client.CreateRing(&model.CreateRingRequest{
+		Name:              "ring-1",
+		Priority:          1,
+		InstallationGroup: &model.InstallationGroup{Name: "ig-1", ProvisionerGroupID: "pg1"},
+	})
+	require.NoError(t, err)
+
+	t.Run("register with unknown provisioner target", func(t *testing.T) {
+		_, err = client.RegisterRingInstallationGroup(ring.ID, &model.RegisterInstallationGroupRequest{Name: "ig-2", ProvisionerGroupID: "pg2", ProvisionerTarget: "us"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("register the same provisioner group ID of another provisioner target", func(t *testing.T) {
+		_, err = client.RegisterRingInstallationGroup(ring.ID, &model.RegisterInstallationGroupRequest{Name: "ig-2", ProvisionerGroupID: "pg1", ProvisionerTarget: "eu"})
+		require.NoError(t, err)
+
+		installationGroup, err := sqlStore.GetInstallationGroupByName("ig-2")
+		require.NoError(t, err)
+		require.Equal(t, "eu", installationGroup.ProvisionerTarget)
+	})
+
+	installationGroup, err := sqlStore.GetInstallationGroupByName("ig-2")
+	require.NoError(t, err)
+
+	t.Run("update to unknown provisioner target", func(t *testing.T) {
+		target := "us"
+		_, err = client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{ProvisionerTarget: &target})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("update to the default provisioner target with a registered provisioner group", func(t *testing.T) {
+		target := ""
+		_, err = client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{ProvisionerTarget: &target})
+		require.EqualError(t, err, "failed with status code 409")
+	})
+
+	t.Run("update to the default provisioner target", func(t *testing.T) {
+		target := ""
+		updated, err := client.UpdateInstallationGroup(installationGroup.ID, &model.UpdateInstallationGroupRequest{ProvisionerTarget: &target, ProvisionerGroupID: "pg2"})
+		require.NoError(t, err)
+		require.Empty(t, updated.ProvisionerTarget)
+		require.Equal(t, "pg2", updated.ProvisionerGroupID)
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	_ "embed" // required for go:embed
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// OpenAPISpec is the OpenAPI 3 document describing every route registered by Register.
+//
+// It must be updated whenever a route is added or changed.
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
+
+// initOpenAPI registers the OpenAPI document endpoint on the given router.
+func initOpenAPI(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc, role string) *contextHandler {
+		return newContextHandler(context, handler, role)
+	}
+
+	apiRouter.Handle("/openapi.json", addContext(handleGetOpenAPI, "")).Methods("GET")
+}
+
+// handleGetOpenAPI responds to GET /api/openapi.json, returning the OpenAPI document
+// describing the API.
+func handleGetOpenAPI(c *Context, w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(OpenAPISpec); err != nil {
+		c.Logger.WithError(err).Error("failed to write OpenAPI document")
+	}
+}
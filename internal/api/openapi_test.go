@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/api"
+	"github.com/mattermost/elrond/internal/auth"
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPIDocument struct {
+	OpenAPI string                                `json:"openapi"`
+	Paths   map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// routeVariablePattern matches mux route variables with an optional pattern, such as
+// {ring:[A-Za-z0-9]{26}}, which are documented as plain {ring} path parameters.
+var routeVariablePattern = regexp.MustCompile(`\{([^:{}]+)(:(?:[^{}]|\{[^{}]*\})*)?\}`)
+
+func TestOpenAPISpecCoversAllRoutes(t *testing.T) {
+	var spec openAPIDocument
+	require.NoError(t, json.Unmarshal(api.OpenAPISpec, &spec))
+	require.True(t, strings.HasPrefix(spec.OpenAPI, "3."))
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{})
+
+	documented := make(map[string]bool)
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Subrouters carry no methods of their own.
+			return nil
+		}
+
+		path := routeVariablePattern.ReplaceAllString(template, "{$1}")
+		for _, method := range methods {
+			operations, ok := spec.Paths[path]
+			require.Truef(t, ok, "route %s is missing from the OpenAPI spec", path)
+			_, ok = operations[strings.ToLower(method)]
+			require.Truef(t, ok, "route %s %s is missing from the OpenAPI spec", method, path)
+			documented[method+" "+path] = true
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			require.Truef(t, documented[strings.ToUpper(method)+" "+path], "OpenAPI spec documents %s %s which is not a registered route", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestGetOpenAPISpec(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		Logger:        logger,
+		Authenticator: auth.NewTokenAuthenticator(sqlStore),
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/openapi.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var spec openAPIDocument
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&spec))
+	require.NotEmpty(t, spec.Paths)
+}
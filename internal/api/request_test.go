@@ -16,6 +16,7 @@ import (
 func TestNewCreateRingRequestFromReader(t *testing.T) {
 	defaultCreateRingRequest := func() *model.CreateRingRequest {
 		return &model.CreateRingRequest{
+			Name:     "ring-1",
 			Priority: 1,
 			InstallationGroup: &model.InstallationGroup{
 				Name: "prod-1234",
@@ -34,7 +35,7 @@ func TestNewCreateRingRequestFromReader(t *testing.T) {
 
 	t.Run("partial request", func(t *testing.T) {
 		ringRequest, err := model.NewCreateRingRequestFromReader(bytes.NewReader([]byte(
-			`{"Priority": 2, "InstallationGroup": {"Name": "prod-1234"}}`,
+			`{"Name": "ring-1", "Priority": 2, "InstallationGroup": {"Name": "prod-1234"}}`,
 		)))
 		require.NoError(t, err)
 		modifiedDefaultCreateRingRequest := defaultCreateRingRequest()
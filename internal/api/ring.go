@@ -16,30 +16,37 @@ import (
 
 // initRing registers ring endpoints on the given router.
 func initRing(apiRouter *mux.Router, context *Context) {
-	addContext := func(handler contextHandlerFunc) *contextHandler {
-		return newContextHandler(context, handler)
+	addContext := func(handler contextHandlerFunc, role string) *contextHandler {
+		return newContextHandler(context, handler, role)
 	}
 
 	ringsRouter := apiRouter.PathPrefix("/rings").Subrouter()
-	ringsRouter.Handle("", addContext(handleGetRings)).Methods("GET")
-	ringsRouter.Handle("", addContext(handleCreateRing)).Methods("POST")
-	ringsRouter.Handle("/release", addContext(handleReleaseAllRings)).Methods("POST")
-	ringsRouter.Handle("/release/pause", addContext(handlePauseReleaseRing)).Methods("POST")
-	ringsRouter.Handle("/release/resume", addContext(handleResumeReleaseRing)).Methods("POST")
-	ringsRouter.Handle("/release/cancel", addContext(handleCancelReleaseRing)).Methods("POST")
+	ringsRouter.Handle("", addContext(handleGetRings, model.RoleViewer)).Methods("GET")
+	ringsRouter.Handle("", addContext(idempotent(handleCreateRing), model.RoleReleaser)).Methods("POST")
+	ringsRouter.Handle("/graph", addContext(handleGetRingGraph, model.RoleViewer)).Methods("GET")
+	ringsRouter.Handle("/matrix", addContext(handleGetRingMatrix, model.RoleViewer)).Methods("GET")
+	ringsRouter.Handle("/import", addContext(handleImportRings, model.RoleReleaser)).Methods("POST")
+	ringsRouter.Handle("/release", addContext(idempotent(handleReleaseAllRings), model.RoleReleaser)).Methods("POST")
+	ringsRouter.Handle("/release/pause", addContext(handlePauseReleaseRing, model.RoleReleaser)).Methods("POST")
+	ringsRouter.Handle("/release/resume", addContext(handleResumeReleaseRing, model.RoleReleaser)).Methods("POST")
+	ringsRouter.Handle("/release/cancel", addContext(handleCancelReleaseRing, model.RoleReleaser)).Methods("POST")
 
 	ringRouter := apiRouter.PathPrefix("/ring/{ring:[A-Za-z0-9]{26}}").Subrouter()
-	ringRouter.Handle("", addContext(handleGetRing)).Methods("GET")
-	ringRouter.Handle("", addContext(handleRetryCreateRing)).Methods("POST")
-	ringRouter.Handle("/update", addContext(handleUpdateRing)).Methods("POST")
-	ringRouter.Handle("/release", addContext(handleReleaseRing)).Methods("POST")
-	ringRouter.Handle("/release", addContext(handleRetryReleaseRing)).Methods("POST")
-	ringRouter.Handle("/installationgroup", addContext(handleRegisterRingInstallationGroup)).Methods("POST")
-	ringRouter.Handle("/installationgroup/{installation-group-id}", addContext(handleDeleteRingInstallationGroup)).Methods("DELETE")
-	ringRouter.Handle("", addContext(handleDeleteRing)).Methods("DELETE")
+	ringRouter.Handle("", addContext(handleGetRing, model.RoleViewer)).Methods("GET")
+	ringRouter.Handle("", addContext(handleRetryCreateRing, model.RoleReleaser)).Methods("POST")
+	ringRouter.Handle("/update", addContext(handleUpdateRing, model.RoleReleaser)).Methods("POST")
+	ringRouter.Handle("/release", addContext(idempotent(handleReleaseRing), model.RoleReleaser)).Methods("POST")
+	ringRouter.Handle("/release/retry", addContext(handleRetryReleaseRing, model.RoleReleaser)).Methods("POST")
+	ringRouter.Handle("/promote", addContext(handlePromoteRing, model.RoleReleaser)).Methods("POST")
+	ringRouter.Handle("/installationgroup", addContext(idempotent(handleRegisterRingInstallationGroup), model.RoleReleaser)).Methods("POST")
+	ringRouter.Handle("/installationgroup/{installation-group-id}", addContext(handleDeleteRingInstallationGroup, model.RoleReleaser)).Methods("DELETE")
+	ringRouter.Handle("", addContext(handleDeleteRing, model.RoleAdmin)).Methods("DELETE")
+
+	apiRouter.Handle("/ring/name/{name}", addContext(handleGetRingByName, model.RoleViewer)).Methods("GET")
 
 	ringReleaseRouter := apiRouter.PathPrefix("/release/{release:[A-Za-z0-9]{26}}").Subrouter()
-	ringReleaseRouter.Handle("", addContext(handleGetRingRelease)).Methods("GET")
+	ringReleaseRouter.Handle("", addContext(handleGetRingRelease, model.RoleViewer)).Methods("GET")
+	apiRouter.Handle("/releases", addContext(handleGetRingReleases, model.RoleViewer)).Methods("GET")
 
 }
 
@@ -69,6 +76,56 @@ func handleGetRing(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	ring.InstallationGroups = installationGroups
 
+	dependencies, err := c.Store.GetRingDependencies()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring dependencies")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ring.DependsOn = dependencies[ring.ID]
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, ring)
+}
+
+// handleGetRingByName responds to GET /api/ring/name/{name}, returning the non-deleted
+// ring with the given name.
+func handleGetRingByName(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	c.Logger = c.Logger.WithField("ring-name", name)
+
+	ring, err := c.Store.GetRingByName(name)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query ring")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if ring == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	installationGroups, err := c.Store.GetInstallationGroupsForRing(ring.ID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get installation groups for ring")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ring.InstallationGroups = installationGroups
+
+	dependencies, err := c.Store.GetRingDependencies()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring dependencies")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ring.DependsOn = dependencies[ring.ID]
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	outputJSON(c, w, ring)
@@ -107,8 +164,16 @@ func handleGetRings(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dependencies, err := c.Store.GetRingDependencies()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring dependencies")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	for _, r := range rings {
 		r.InstallationGroups = installationGroups[r.ID]
+		r.DependsOn = dependencies[r.ID]
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -116,6 +181,87 @@ func handleGetRings(c *Context, w http.ResponseWriter, r *http.Request) {
 	outputJSON(c, w, rings)
 }
 
+// handleGetRingGraph responds to GET /api/rings/graph, returning the order in which the
+// non-deleted rings are released.
+func handleGetRingGraph(c *Context, w http.ResponseWriter, r *http.Request) {
+	rings, err := c.Store.GetRings(&model.RingFilter{PerPage: model.AllPerPage})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query rings")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	dependencies, err := c.Store.GetRingDependencies()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring dependencies")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	graph, err := model.NewRingGraph(rings, dependencies)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to build ring graph")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, graph)
+}
+
+// handleGetRingMatrix responds to GET /api/rings/matrix, returning the active and desired
+// release of every non-deleted ring and installation group.
+func handleGetRingMatrix(c *Context, w http.ResponseWriter, r *http.Request) {
+	filter := &model.RingFilter{PerPage: model.AllPerPage}
+	rings, err := c.Store.GetRings(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query rings")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	installationGroups, err := c.Store.GetInstallationGroupsForRings(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get installation groups for rings")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	dependencies, err := c.Store.GetRingDependencies()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring dependencies")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	releases := make(map[string]*model.RingRelease)
+	for _, ring := range rings {
+		ring.InstallationGroups = installationGroups[ring.ID]
+
+		releaseIDs := []string{ring.ActiveReleaseID, ring.DesiredReleaseID}
+		for _, installationGroup := range ring.InstallationGroups {
+			releaseIDs = append(releaseIDs, installationGroup.ActiveReleaseID)
+		}
+		for _, releaseID := range releaseIDs {
+			if _, ok := releases[releaseID]; ok || releaseID == "" {
+				continue
+			}
+			release, err := c.Store.GetRingRelease(releaseID)
+			if err != nil {
+				c.Logger.WithError(err).Error("failed to get ring release details")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			releases[releaseID] = release
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, model.NewRingMatrix(rings, releases, dependencies, time.Now()))
+}
+
 // handleCreateRing responds to POST /api/rings, beginning the process of creating a new
 // ring.
 // sample body:
@@ -131,12 +277,30 @@ func handleCreateRing(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	existingRing, err := c.Store.GetRingByName(createRingRequest.Name)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query ring by name")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if existingRing != nil {
+		c.Logger.Warnf("a ring named %s already exists", createRingRequest.Name)
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	if status := checkRingDependencies(c, "", createRingRequest.DependsOn); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
 	release, err := c.Store.GetOrCreateRingRelease(&model.RingRelease{
 		Version:      createRingRequest.Version,
 		Image:        createRingRequest.Image,
 		Force:        false,
 		EnvVariables: nil,
 		CreateAt:     time.Now().UnixNano(),
+		CreatedBy:    c.Caller(),
 	})
 
 	if err != nil {
@@ -154,6 +318,7 @@ func handleCreateRing(c *Context, w http.ResponseWriter, r *http.Request) {
 		Provisioner:      "elrond",
 		APISecurityLock:  createRingRequest.APISecurityLock,
 		State:            model.RingStateCreationRequested,
+		DependsOn:        createRingRequest.DependsOn,
 	}
 	iGroup := model.InstallationGroup{}
 	if createRingRequest.InstallationGroup != nil {
@@ -162,11 +327,17 @@ func handleCreateRing(c *Context, w http.ResponseWriter, r *http.Request) {
 				Name:               createRingRequest.InstallationGroup.Name,
 				State:              model.InstallationGroupStable,
 				ProvisionerGroupID: createRingRequest.InstallationGroup.ProvisionerGroupID,
+				ProvisionerTarget:  createRingRequest.InstallationGroup.ProvisionerTarget,
 				SoakTime:           createRingRequest.InstallationGroup.SoakTime,
 			}
 		}
 	}
 
+	if status := checkProvisionerGroup(c, "", iGroup.ProvisionerTarget, iGroup.ProvisionerGroupID); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
 	if err = c.Store.CreateRing(&ring, &iGroup); err != nil {
 		c.Logger.WithError(err).Error("failed to create ring")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -182,6 +353,7 @@ func handleCreateRing(c *Context, w http.ResponseWriter, r *http.Request) {
 		NewState:  model.RingStateCreationRequested,
 		OldState:  "n/a",
 		Timestamp: time.Now().UnixNano(),
+		Actor:     c.Caller(),
 	}
 	if err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState)); err != nil {
 		c.Logger.WithError(err).Error("Unable to process and send webhooks")
@@ -224,6 +396,7 @@ func handleRetryCreateRing(c *Context, w http.ResponseWriter, r *http.Request) {
 			NewState:  newState,
 			OldState:  ring.State,
 			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
 		}
 		ring.State = newState
 
@@ -270,11 +443,22 @@ func handleUpdateRing(c *Context, w http.ResponseWriter, r *http.Request) {
 	updateRingRequest, err := model.NewUpdateRingRequestFromReader(r.Body)
 	if err != nil {
 		c.Logger.WithError(err).Error("failed to deserialize ring update request body")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	if updateRingRequest.Name != "" {
+	if updateRingRequest.Name != "" && updateRingRequest.Name != ring.Name {
+		existingRing, err := c.Store.GetRingByName(updateRingRequest.Name)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query ring by name")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if existingRing != nil {
+			c.Logger.Warnf("a ring named %s already exists", updateRingRequest.Name)
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
 		ring.Name = updateRingRequest.Name
 	}
 
@@ -286,12 +470,35 @@ func handleUpdateRing(c *Context, w http.ResponseWriter, r *http.Request) {
 		ring.Priority = updateRingRequest.Priority
 	}
 
+	if updateRingRequest.DependsOn != nil {
+		if status := checkRingDependencies(c, ring.ID, *updateRingRequest.DependsOn); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
 	if err = c.Store.UpdateRing(ring); err != nil {
 		c.Logger.WithError(err).Error("failed to update ring")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	if updateRingRequest.DependsOn != nil {
+		if err = c.Store.SetRingDependencies(ring.ID, *updateRingRequest.DependsOn); err != nil {
+			c.Logger.WithError(err).Error("failed to update ring dependencies")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	dependencies, err := c.Store.GetRingDependencies()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring dependencies")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	ring.DependsOn = dependencies[ring.ID]
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusAccepted)
 	outputJSON(c, w, ring)
@@ -307,6 +514,11 @@ func handleReleaseAllRings(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if status := checkReleaseBlocked(c, ringReleaseRequest.Image, ringReleaseRequest.Version); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
 	rings, err := c.Store.GetRings(&model.RingFilter{
 		IncludeDeleted: false,
 		PerPage:        10000,
@@ -347,11 +559,13 @@ func handleReleaseAllRings(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 
 	ringRelease := model.RingRelease{
-		Version:      ringReleaseRequest.Version,
-		Image:        ringReleaseRequest.Image,
-		Force:        ringReleaseRequest.Force,
-		EnvVariables: ringReleaseRequest.EnvVariables,
-		CreateAt:     time.Now().UnixNano(),
+		Version:                    ringReleaseRequest.Version,
+		Image:                      ringReleaseRequest.Image,
+		Force:                      ringReleaseRequest.Force,
+		EnvVariables:               ringReleaseRequest.EnvVariables,
+		ExcludedInstallationGroups: ringReleaseRequest.ExcludedInstallationGroups,
+		CreateAt:                   time.Now().UnixNano(),
+		CreatedBy:                  c.Caller(),
 	}
 
 	//Proactively checking or creating a ring release entry so that all rings to be released get the same release version
@@ -375,6 +589,11 @@ func handleReleaseAllRings(c *Context, w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+		// All rings receive the same release, so only downgrades are checked.
+		if status := checkReleaseVersion(c, ring, ringReleaseRequest.Version, ringReleaseRequest.Force || ringReleaseRequest.AllowDowngrade, false); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
 		if ring.State != model.RingStateReleasePending {
 			webhookPayload := &model.WebhookPayload{
 				Type:      model.TypeRing,
@@ -383,6 +602,7 @@ func handleReleaseAllRings(c *Context, w http.ResponseWriter, r *http.Request) {
 				NewState:  model.RingStateReleasePending,
 				OldState:  ring.State,
 				Timestamp: time.Now().UnixNano(),
+				Actor:     c.Caller(),
 				ExtraData: map[string]string{"Environment": c.Environment},
 			}
 			activeRelease, getErr := c.Store.GetRingRelease(ring.ActiveReleaseID)
@@ -454,6 +674,16 @@ func handleReleaseRing(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if status := checkReleaseBlocked(c, ringReleaseRequest.Image, ringReleaseRequest.Version); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	if status := checkReleaseVersion(c, ring, ringReleaseRequest.Version, ringReleaseRequest.Force || ringReleaseRequest.AllowDowngrade, true); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
 	if ring.State != model.RingStateReleasePending {
 		webhookPayload := &model.WebhookPayload{
 			Type:      model.TypeRing,
@@ -462,6 +692,7 @@ func handleReleaseRing(c *Context, w http.ResponseWriter, r *http.Request) {
 			NewState:  model.RingStateReleasePending,
 			OldState:  ring.State,
 			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
 			ExtraData: map[string]string{"Environment": c.Environment},
 		}
 
@@ -475,11 +706,13 @@ func handleReleaseRing(c *Context, w http.ResponseWriter, r *http.Request) {
 		if activeRelease.Image != ringReleaseRequest.Image || activeRelease.Version != ringReleaseRequest.Version || ringReleaseRequest.EnvVariables != nil {
 
 			ringRelease := model.RingRelease{
-				Version:      ringReleaseRequest.Version,
-				Image:        ringReleaseRequest.Image,
-				Force:        ringReleaseRequest.Force,
-				EnvVariables: ringReleaseRequest.EnvVariables,
-				CreateAt:     time.Now().UnixNano(),
+				Version:                    ringReleaseRequest.Version,
+				Image:                      ringReleaseRequest.Image,
+				Force:                      ringReleaseRequest.Force,
+				EnvVariables:               ringReleaseRequest.EnvVariables,
+				ExcludedInstallationGroups: ringReleaseRequest.ExcludedInstallationGroups,
+				CreateAt:                   time.Now().UnixNano(),
+				CreatedBy:                  c.Caller(),
 			}
 
 			desiredRelease, err := c.Store.GetOrCreateRingRelease(&ringRelease)
@@ -513,8 +746,123 @@ func handleReleaseRing(c *Context, w http.ResponseWriter, r *http.Request) {
 	outputJSON(c, w, ring)
 }
 
-// handleRetryReleaseRing responds to POST /api/ring/{ring}/release, retrying a previously
-// failed creation.
+// handlePromoteRing responds to POST /api/ring/{ring}/promote?from={sourceRing},
+// releasing the active release of the source ring to the ring.
+func handlePromoteRing(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ringID := vars["ring"]
+	c.Logger = c.Logger.WithField("ring", ringID)
+
+	sourceRingID := r.URL.Query().Get("from")
+	if sourceRingID == "" || sourceRingID == ringID {
+		c.Logger.Warn("a source ring other than the promoted ring is required")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.WithField("source-ring", sourceRingID)
+
+	ring, status, unlockOnce := lockRing(c, ringID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if ring.APISecurityLock {
+		logSecurityLockConflict("ring", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	sourceRing, err := c.Store.GetRing(sourceRingID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query source ring")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if sourceRing == nil || sourceRing.DeleteAt != 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !sourceRing.HasCompletedRelease() {
+		c.Logger.Warnf("unable to promote the release of a source ring in state %s", sourceRing.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	soakEndAt := sourceRing.ReleaseAt + int64(sourceRing.SoakTime)*int64(time.Second)
+	if time.Now().UnixNano() < soakEndAt {
+		c.Logger.Warnf("unable to promote the release of a source ring soaking for another %d seconds", (soakEndAt-time.Now().UnixNano())/int64(time.Second))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !ring.ValidTransitionState(model.RingStateReleasePending) {
+		c.Logger.Warnf("unable to do a ring release while in state %s", ring.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	sourceRelease, err := c.Store.GetRingRelease(sourceRing.ActiveReleaseID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get source ring active release details")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if sourceRelease == nil {
+		c.Logger.Warn("the source ring has no active release")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if status := checkReleaseBlocked(c, sourceRelease.Image, sourceRelease.Version); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	if status := checkReleaseVersion(c, ring, sourceRelease.Version, false, true); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	if ring.State != model.RingStateReleasePending && ring.ActiveReleaseID != sourceRing.ActiveReleaseID {
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeRing,
+			ID:        ring.ID,
+			Name:      ring.Name,
+			NewState:  model.RingStateReleasePending,
+			OldState:  ring.State,
+			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
+			ExtraData: map[string]string{"Environment": c.Environment, "PromotedFrom": sourceRing.ID},
+		}
+
+		ring.State = model.RingStateReleasePending
+		ring.DesiredReleaseID = sourceRing.ActiveReleaseID
+
+		if err = c.Store.UpdateRing(ring); err != nil {
+			c.Logger.WithError(err).Error("failed to update ring")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState)); err != nil {
+			c.Logger.WithError(err).Error("unable to process and send webhooks")
+		}
+	}
+
+	// Notify even if we didn't make changes, to expedite even the no-op operations above.
+	unlockOnce()
+	c.Supervisor.Do() //nolint
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, ring)
+}
+
+// handleRetryReleaseRing responds to POST /api/ring/{ring}/release/retry, retrying a
+// previously failed release from the installation groups that did not complete it.
 func handleRetryReleaseRing(c *Context, w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	ringID := vars["ring"]
@@ -535,6 +883,19 @@ func handleRetryReleaseRing(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	desiredRelease, err := c.Store.GetRingRelease(ring.DesiredReleaseID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring desired release details")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if desiredRelease != nil {
+		if status := checkReleaseBlocked(c, desiredRelease.Image, desiredRelease.Version); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
 	if ring.State != newState {
 		webhookPayload := &model.WebhookPayload{
 			Type:      model.TypeRing,
@@ -543,6 +904,7 @@ func handleRetryReleaseRing(c *Context, w http.ResponseWriter, r *http.Request)
 			NewState:  newState,
 			OldState:  ring.State,
 			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
 		}
 		ring.State = newState
 
@@ -658,6 +1020,70 @@ func handleGetRingRelease(c *Context, w http.ResponseWriter, r *http.Request) {
 	outputJSON(c, w, ringRelease)
 }
 
+// handleGetRingReleases responds to GET /api/releases, returning the specified page of ring
+// releases together with the rings and installation groups running or desiring each one.
+func handleGetRingReleases(c *Context, w http.ResponseWriter, r *http.Request) {
+	page, perPage, _, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	createdAfter, err := parseInt64(r.URL, "created_after", 0)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse created_after parameter")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.RingReleaseFilter{
+		Image:        r.URL.Query().Get("image"),
+		Version:      r.URL.Query().Get("version"),
+		CreatedAfter: createdAfter,
+		Page:         page,
+		PerPage:      perPage,
+	}
+	if r.URL.Query().Get("force") != "" {
+		force, err := parseBool(r.URL, "force", false)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to parse force parameter")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		filter.Force = &force
+	}
+
+	ringReleases, err := c.Store.GetRingReleases(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query ring releases")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ringFilter := &model.RingFilter{PerPage: model.AllPerPage}
+	rings, err := c.Store.GetRings(ringFilter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query rings")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	installationGroups, err := c.Store.GetInstallationGroupsForRings(ringFilter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get installation groups for rings")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, ring := range rings {
+		ring.InstallationGroups = installationGroups[ring.ID]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, model.NewRingReleaseUsages(ringReleases, rings))
+}
+
 // handleDeleteRing responds to DELETE /api/ring/{ring}, beginning the process of
 // deleting the ring.
 func handleDeleteRing(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -694,6 +1120,7 @@ func handleDeleteRing(c *Context, w http.ResponseWriter, r *http.Request) {
 			NewState:  newState,
 			OldState:  ring.State,
 			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
 		}
 		ring.State = newState
 
@@ -745,6 +1172,13 @@ func handleRegisterRingInstallationGroup(c *Context, w http.ResponseWriter, r *h
 		SoakTime:           installationGroupRequest.SoakTime,
 		State:              model.InstallationGroupStable,
 		ProvisionerGroupID: installationGroupRequest.ProvisionerGroupID,
+		ProvisionerTarget:  installationGroupRequest.ProvisionerTarget,
+		Priority:           installationGroupRequest.Priority,
+	}
+
+	if status := checkProvisionerGroup(c, "", iGroup.ProvisionerTarget, iGroup.ProvisionerGroupID); status != 0 {
+		w.WriteHeader(status)
+		return
 	}
 
 	installationGroup, err := c.Store.CreateRingInstallationGroup(ringID, &iGroup)
@@ -795,3 +1229,95 @@ func handleDeleteRingInstallationGroup(c *Context, w http.ResponseWriter, r *htt
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// checkRingDependencies checks that the rings the given ring is to depend on exist and
+// that the dependencies do not form a cycle, returning the status code to respond with
+// when they are invalid. An empty ring ID stands for a ring that is not created yet.
+func checkRingDependencies(c *Context, ringID string, dependsOn []string) int {
+	if len(dependsOn) == 0 {
+		return 0
+	}
+
+	for _, dependencyID := range dependsOn {
+		dependency, err := c.Store.GetRing(dependencyID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query ring dependency")
+			return http.StatusInternalServerError
+		}
+		if dependency == nil || dependency.DeleteAt != 0 {
+			c.Logger.Warnf("ring dependency %s not found", dependencyID)
+			return http.StatusBadRequest
+		}
+	}
+
+	if ringID == "" {
+		return 0
+	}
+
+	dependencies, err := c.Store.GetRingDependencies()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring dependencies")
+		return http.StatusInternalServerError
+	}
+	dependencies[ringID] = dependsOn
+
+	if err = model.CheckRingDependencies(dependencies); err != nil {
+		c.Logger.WithError(err).Warn("invalid ring dependencies")
+		return http.StatusBadRequest
+	}
+
+	return 0
+}
+
+// checkReleaseVersion applies the semantic version guardrails to releasing the given
+// version to the ring, returning the status code to respond with when the release is
+// refused. Unless allowed, a release may not downgrade the active release of the ring.
+// When checkEarlierRings is set, the release may also not be newer than the active
+// release of any ring released before the ring. Versions that are not semantic versions
+// are not checked.
+func checkReleaseVersion(c *Context, ring *model.Ring, version string, allowDowngrade, checkEarlierRings bool) int {
+	if _, ok := model.ParseReleaseVersion(version); !ok {
+		return 0
+	}
+
+	if !allowDowngrade {
+		activeRelease, err := c.Store.GetRingRelease(ring.ActiveReleaseID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to get ring active release details")
+			return http.StatusInternalServerError
+		}
+		if activeRelease != nil && model.IsNewerReleaseVersion(activeRelease.Version, version) {
+			c.Logger.Warnf("refusing to downgrade ring %s from version %s to %s", ring.ID, activeRelease.Version, version)
+			return http.StatusBadRequest
+		}
+	}
+
+	if !checkEarlierRings {
+		return 0
+	}
+
+	rings, err := c.Store.GetRings(&model.RingFilter{PerPage: model.AllPerPage})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query rings")
+		return http.StatusInternalServerError
+	}
+	dependencies, err := c.Store.GetRingDependencies()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get ring dependencies")
+		return http.StatusInternalServerError
+	}
+
+	for _, earlierRing := range model.EarlierRings(ring, rings, dependencies) {
+		earlierRelease, err := c.Store.GetRingRelease(earlierRing.ActiveReleaseID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to get ring active release details")
+			return http.StatusInternalServerError
+		}
+		if earlierRelease != nil && model.IsNewerReleaseVersion(version, earlierRelease.Version) {
+			c.Logger.Warnf("refusing to release version %s ahead of ring %s, which runs version %s", version, earlierRing.ID, earlierRelease.Version)
+			return http.StatusBadRequest
+		}
+	}
+
+	return 0
+}
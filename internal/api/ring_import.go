@@ -0,0 +1,256 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/elrond/internal/webhook"
+	"github.com/mattermost/elrond/model"
+)
+
+// handleImportRings responds to POST /api/rings/import, creating rings and installation
+// groups from the existing provisioner groups.
+func handleImportRings(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "import-rings")
+
+	importRingsRequest, err := model.NewImportRingsRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ringImport, status := planRingImport(c, importRingsRequest)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	if importRingsRequest.DryRun || len(ringImport.Rings) == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		outputJSON(c, w, ringImport)
+		return
+	}
+
+	ringIDs := ringImportRingIDs(ringImport)
+	if len(ringIDs) > 0 {
+		var unlockOnce func()
+		status, unlockOnce = lockRings(c, ringIDs)
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		defer unlockOnce()
+
+		// The rings may have changed before they were locked.
+		ringImport, status = planRingImport(c, importRingsRequest)
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		if strings.Join(ringImportRingIDs(ringImport), ",") != strings.Join(ringIDs, ",") {
+			c.Logger.Warn("rings changed while importing rings")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+	}
+
+	for _, ringID := range ringIDs {
+		ring, err := c.Store.GetRing(ringID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query ring")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if ring.APISecurityLock {
+			logSecurityLockConflict("ring", c.Logger)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	releases := map[string]*model.RingRelease{}
+	for _, importRing := range ringImport.Rings {
+		for _, importGroup := range importRing.InstallationGroups {
+			key := importGroup.Image + ":" + importGroup.Version
+			release, ok := releases[key]
+			if !ok {
+				release, err = c.Store.GetOrCreateRingRelease(&model.RingRelease{
+					Image:     importGroup.Image,
+					Version:   importGroup.Version,
+					CreateAt:  time.Now().UnixNano(),
+					CreatedBy: c.Caller(),
+				})
+				if err != nil {
+					c.Logger.WithError(err).Error("failed to get or create ring release")
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				releases[key] = release
+			}
+			importGroup.ReleaseID = release.ID
+		}
+	}
+
+	createdRings, err := c.Store.ImportRings(ringImport)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to import rings")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	ringImport.Applied = true
+
+	for _, ring := range createdRings {
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeRing,
+			ID:        ring.ID,
+			Name:      ring.Name,
+			NewState:  model.RingStateCreationRequested,
+			OldState:  "n/a",
+			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
+		}
+		if err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState)); err != nil {
+			c.Logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	c.Supervisor.Do() //nolint
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, ringImport)
+}
+
+// planRingImport maps the provisioner groups to rings, skipping the groups that cannot be
+// imported.
+func planRingImport(c *Context, request *model.ImportRingsRequest) (*model.RingImport, int) {
+	if c.Elrond == nil {
+		c.Logger.Error("no provisioner is configured to import rings from")
+		return nil, http.StatusInternalServerError
+	}
+
+	if !c.Elrond.ProvisionerTargetExists(request.ProvisionerTarget) {
+		c.Logger.Warnf("provisioner target %s is not configured", request.ProvisionerTarget)
+		return nil, http.StatusBadRequest
+	}
+
+	groups, err := c.Elrond.GetProvisionerGroups(request.ProvisionerTarget)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get provisioner groups")
+		return nil, http.StatusInternalServerError
+	}
+
+	ringImport := model.NewRingImport(groups, request.Rules)
+	names := map[string]bool{}
+
+	for _, importRing := range ringImport.Rings {
+		ring, err := c.Store.GetRingByName(importRing.Name)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query ring by name")
+			return nil, http.StatusInternalServerError
+		}
+		if ring == nil {
+			if importRing.Priority == 0 {
+				c.Logger.Warnf("a priority is required to create ring %s", importRing.Name)
+				return nil, http.StatusBadRequest
+			}
+			importRing.Create = true
+		} else {
+			importRing.ID = ring.ID
+			importRing.Priority = ring.Priority
+			importRing.SoakTime = ring.SoakTime
+		}
+
+		for _, importGroup := range append([]*model.RingImportInstallationGroup(nil), importRing.InstallationGroups...) {
+			reason, status := ringImportSkipReason(c, ring, importGroup, names)
+			if status != 0 {
+				return nil, status
+			}
+			if reason != "" {
+				ringImport.Skip(importRing, importGroup, reason)
+				continue
+			}
+			names[importGroup.Name] = true
+		}
+
+		if importRing.Create && len(importRing.InstallationGroups) > 0 {
+			importRing.Image = importRing.InstallationGroups[0].Image
+			importRing.Version = importRing.InstallationGroups[0].Version
+		}
+	}
+
+	rings := ringImport.Rings[:0]
+	for _, importRing := range ringImport.Rings {
+		if len(importRing.InstallationGroups) > 0 {
+			rings = append(rings, importRing)
+		}
+	}
+	ringImport.Rings = rings
+
+	return ringImport, 0
+}
+
+// ringImportSkipReason returns why the installation group cannot be imported to the
+// ring, or an empty reason when it can.
+func ringImportSkipReason(c *Context, ring *model.Ring, importGroup *model.RingImportInstallationGroup, names map[string]bool) (string, int) {
+	if ring != nil && ring.IsReleasing() {
+		return fmt.Sprintf("ring %s has a release in progress", ring.Name), 0
+	}
+	if ring != nil && ring.State == model.RingStateDeletionRequested {
+		return fmt.Sprintf("ring %s is being deleted", ring.Name), 0
+	}
+	if importGroup.Image == "" || importGroup.Version == "" {
+		return "the provisioner group has no image or version", 0
+	}
+	if names[importGroup.Name] {
+		return "another provisioner group is imported with the same name", 0
+	}
+
+	installationGroups, err := c.Store.GetInstallationGroups(&model.InstallationGroupFilter{
+		ProvisionerGroupID: importGroup.ProvisionerGroupID,
+		PerPage:            model.AllPerPage,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation groups of the provisioner group")
+		return "", http.StatusInternalServerError
+	}
+	for _, installationGroup := range installationGroups {
+		if installationGroup.RingID != "" && installationGroup.ProvisionerTarget == importGroup.ProvisionerTarget {
+			return fmt.Sprintf("the provisioner group is already registered to installation group %s", installationGroup.Name), 0
+		}
+	}
+
+	installationGroup, err := c.Store.GetInstallationGroupByName(importGroup.Name)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation group by name")
+		return "", http.StatusInternalServerError
+	}
+	if installationGroup != nil {
+		return fmt.Sprintf("installation group %s already exists", importGroup.Name), 0
+	}
+
+	return "", 0
+}
+
+// ringImportRingIDs returns the sorted IDs of the existing rings the import adds
+// installation groups to.
+func ringImportRingIDs(ringImport *model.RingImport) []string {
+	var ids []string
+	for _, importRing := range ringImport.Rings {
+		if importRing.ID != "" && !importRing.Create {
+			ids = append(ids, importRing.ID)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/api"
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportRings(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Elrond: &mockElrond{
+			ProvisionerGroups: map[string]bool{"pg1": true, "pg2": true, "pg3": true, "pg4": true},
+			Groups: []*model.ProvisionerGroup{
+				{ID: "pg1", Name: "prod-eu", Image: "mattermost/mattermost-enterprise-edition", Version: "1.0.0"},
+				{ID: "pg2", Name: "prod-us", Image: "mattermost/mattermost-enterprise-edition", Version: "1.1.0"},
+				{ID: "pg3", Name: "early-adopters", Image: "mattermost/mattermost-enterprise-edition", Version: "1.1.0", Annotations: []string{"canary"}},
+				{ID: "pg4", Name: "sandbox", Image: "mattermost/mattermost-enterprise-edition", Version: "1.1.0"},
+			},
+		},
+		Logger: logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	production, err := client.CreateRing(&model.CreateRingRequest{
+		Name:              "production",
+		Priority:          2,
+		InstallationGroup: &model.InstallationGroup{Name: "prod-us", ProvisionerGroupID: "pg2"},
+	})
+	require.NoError(t, err)
+
+	rules := []*model.RingImportRule{
+		{Ring: "canary", Priority: 1, SoakTime: 60, Annotation: "canary"},
+		{Ring: "production", NamePattern: "prod-*"},
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, err = client.ImportRings(&model.ImportRingsRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("created ring without priority", func(t *testing.T) {
+		_, err = client.ImportRings(&model.ImportRingsRequest{Rules: []*model.RingImportRule{
+			{Ring: "canary", Annotation: "canary"},
+		}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		ringImport, err := client.ImportRings(&model.ImportRingsRequest{Rules: rules, DryRun: true})
+		require.NoError(t, err)
+		require.False(t, ringImport.Applied)
+		require.Len(t, ringImport.Rings, 2)
+
+		require.Equal(t, "canary", ringImport.Rings[0].Name)
+		require.True(t, ringImport.Rings[0].Create)
+		require.Equal(t, "1.1.0", ringImport.Rings[0].Version)
+
+		require.Equal(t, "production", ringImport.Rings[1].Name)
+		require.Equal(t, production.ID, ringImport.Rings[1].ID)
+		require.False(t, ringImport.Rings[1].Create)
+		require.Len(t, ringImport.Rings[1].InstallationGroups, 1)
+		require.Equal(t, "pg1", ringImport.Rings[1].InstallationGroups[0].ProvisionerGroupID)
+
+		reasons := map[string]string{}
+		for _, skipped := range ringImport.Skipped {
+			reasons[skipped.ProvisionerGroupID] = skipped.Reason
+		}
+		require.Equal(t, map[string]string{
+			"pg2": "the provisioner group is already registered to installation group prod-us",
+			"pg4": "no rule matches the group",
+		}, reasons)
+
+		canary, err := client.GetRingByName("canary")
+		require.NoError(t, err)
+		require.Nil(t, canary)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		ringImport, err := client.ImportRings(&model.ImportRingsRequest{Rules: rules})
+		require.NoError(t, err)
+		require.True(t, ringImport.Applied)
+
+		canary, err := client.GetRingByName("canary")
+		require.NoError(t, err)
+		require.NotNil(t, canary)
+		require.Equal(t, model.RingStateCreationRequested, canary.State)
+		require.Equal(t, 1, canary.Priority)
+		require.Equal(t, 60, canary.SoakTime)
+		require.Len(t, canary.InstallationGroups, 1)
+		require.Equal(t, canary.ActiveReleaseID, canary.InstallationGroups[0].ActiveReleaseID)
+
+		release, err := client.GetRingRelease(canary.ActiveReleaseID)
+		require.NoError(t, err)
+		require.Equal(t, "1.1.0", release.Version)
+
+		production, err = client.GetRing(production.ID)
+		require.NoError(t, err)
+		require.Len(t, production.InstallationGroups, 2)
+		for _, installationGroup := range production.InstallationGroups {
+			if installationGroup.Name != "prod-eu" {
+				continue
+			}
+			require.Equal(t, "pg1", installationGroup.ProvisionerGroupID)
+			require.Equal(t, model.InstallationGroupStable, installationGroup.State)
+
+			release, err = client.GetRingRelease(installationGroup.ActiveReleaseID)
+			require.NoError(t, err)
+			require.Equal(t, "1.0.0", release.Version)
+		}
+	})
+
+	t.Run("nothing left to import", func(t *testing.T) {
+		ringImport, err := client.ImportRings(&model.ImportRingsRequest{Rules: rules})
+		require.NoError(t, err)
+		require.False(t, ringImport.Applied)
+		require.Empty(t, ringImport.Rings)
+		require.Len(t, ringImport.Skipped, 4)
+	})
+}
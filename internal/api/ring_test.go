@@ -84,6 +84,7 @@ func TestRings(t *testing.T) {
 	})
 	t.Run("rings", func(t *testing.T) {
 		ring1, createErr := client.CreateRing(&model.CreateRingRequest{
+			Name:     "ring-1",
 			Priority: 1,
 			SoakTime: 7200,
 		})
@@ -100,6 +101,7 @@ func TestRings(t *testing.T) {
 		time.Sleep(1 * time.Millisecond)
 
 		ring2, createErr := client.CreateRing(&model.CreateRingRequest{
+			Name:     "ring-2",
 			Priority: 2,
 			SoakTime: 3600,
 		})
@@ -116,6 +118,7 @@ func TestRings(t *testing.T) {
 		time.Sleep(1 * time.Millisecond)
 
 		ring3, createErr := client.CreateRing(&model.CreateRingRequest{
+			Name:     "ring-3",
 			Priority: 2,
 			SoakTime: 3600,
 		})
@@ -128,6 +131,30 @@ func TestRings(t *testing.T) {
 
 		time.Sleep(1 * time.Millisecond)
 
+		t.Run("get ring by name", func(t *testing.T) {
+			ring, getErr := client.GetRingByName("ring-2")
+			require.NoError(t, getErr)
+			require.Equal(t, actualRing2, ring)
+
+			ring, getErr = client.GetRingByName("unknown")
+			require.NoError(t, getErr)
+			require.Nil(t, ring)
+		})
+
+		t.Run("ring names are unique", func(t *testing.T) {
+			_, createErr := client.CreateRing(&model.CreateRingRequest{
+				Name:     "ring-1",
+				Priority: 3,
+			})
+			require.EqualError(t, createErr, "failed with status code 409")
+
+			_, updateErr := client.UpdateRing(ring3.ID, &model.UpdateRingRequest{Name: "ring-1"})
+			require.EqualError(t, updateErr, "failed with status code 409")
+
+			_, createErr = client.CreateRing(&model.CreateRingRequest{Priority: 3})
+			require.EqualError(t, createErr, "failed with status code 400")
+		})
+
 		t.Run("get rings, page 0, perPage 2, exclude deleted", func(t *testing.T) {
 			rings, getRingsErr := client.GetRings(&model.GetRingsRequest{
 				Page:           0,
@@ -253,3 +280,422 @@ func TestRings(t *testing.T) {
 		})
 	})
 }
+
+func TestRingDependencies(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	region1, err := client.CreateRing(&model.CreateRingRequest{Name: "region-1", Priority: 1})
+	require.NoError(t, err)
+	region2, err := client.CreateRing(&model.CreateRingRequest{Name: "region-2", Priority: 1})
+	require.NoError(t, err)
+
+	t.Run("priority graph", func(t *testing.T) {
+		graph, err := client.GetRingGraph()
+		require.NoError(t, err)
+		require.Equal(t, model.RingGraphOrderPriority, graph.OrderedBy)
+		require.Len(t, graph.Rings, 2)
+	})
+
+	t.Run("unknown dependency", func(t *testing.T) {
+		_, err := client.CreateRing(&model.CreateRingRequest{Name: "global", Priority: 2, DependsOn: []string{model.NewID()}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	global, err := client.CreateRing(&model.CreateRingRequest{Name: "global", Priority: 2, DependsOn: []string{region1.ID, region2.ID}})
+	require.NoError(t, err)
+
+	t.Run("get ring", func(t *testing.T) {
+		ring, err := client.GetRing(global.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{region1.ID, region2.ID}, ring.DependsOn)
+	})
+
+	t.Run("dependency graph", func(t *testing.T) {
+		graph, err := client.GetRingGraph()
+		require.NoError(t, err)
+		require.Equal(t, model.RingGraphOrderDependencies, graph.OrderedBy)
+		require.Len(t, graph.Rings, 3)
+		require.Equal(t, 0, graph.Rings[0].Stage)
+		require.Equal(t, 0, graph.Rings[1].Stage)
+		require.Equal(t, global.ID, graph.Rings[2].ID)
+		require.Equal(t, 1, graph.Rings[2].Stage)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := client.UpdateRing(region1.ID, &model.UpdateRingRequest{DependsOn: &[]string{global.ID}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("self dependency", func(t *testing.T) {
+		_, err := client.UpdateRing(region1.ID, &model.UpdateRingRequest{DependsOn: &[]string{region1.ID}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("remove dependencies", func(t *testing.T) {
+		ring, err := client.UpdateRing(global.ID, &model.UpdateRingRequest{DependsOn: &[]string{}})
+		require.NoError(t, err)
+		require.Empty(t, ring.DependsOn)
+
+		graph, err := client.GetRingGraph()
+		require.NoError(t, err)
+		require.Equal(t, model.RingGraphOrderPriority, graph.OrderedBy)
+	})
+}
+
+func TestPromoteRing(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	release1, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "1.0.0"})
+	require.NoError(t, err)
+	release2, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "2.0.0"})
+	require.NoError(t, err)
+
+	source := &model.Ring{
+		Name:             "canary",
+		Priority:         1,
+		SoakTime:         60,
+		State:            model.RingStateStable,
+		ActiveReleaseID:  release2.ID,
+		DesiredReleaseID: release2.ID,
+		ReleaseAt:        time.Now().UnixNano(),
+	}
+	err = sqlStore.CreateRing(source, nil)
+	require.NoError(t, err)
+	target := &model.Ring{
+		Name:             "production",
+		Priority:         2,
+		State:            model.RingStateStable,
+		ActiveReleaseID:  release1.ID,
+		DesiredReleaseID: release1.ID,
+	}
+	err = sqlStore.CreateRing(target, nil)
+	require.NoError(t, err)
+
+	t.Run("missing source ring", func(t *testing.T) {
+		resp, err := http.Post(fmt.Sprintf("%s/api/ring/%s/promote", ts.URL, target.ID), "application/json", nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unknown source ring", func(t *testing.T) {
+		_, err := client.PromoteRing(target.ID, model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("source ring soaking", func(t *testing.T) {
+		_, err := client.PromoteRing(target.ID, source.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	source.ReleaseAt = time.Now().Add(-time.Hour).UnixNano()
+	err = sqlStore.UpdateRing(source)
+	require.NoError(t, err)
+
+	t.Run("source ring releasing", func(t *testing.T) {
+		source.State = model.RingStateReleaseInProgress
+		err = sqlStore.UpdateRing(source)
+		require.NoError(t, err)
+		defer func() {
+			source.State = model.RingStateStable
+			require.NoError(t, sqlStore.UpdateRing(source))
+		}()
+
+		_, err = client.PromoteRing(target.ID, source.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("security lock", func(t *testing.T) {
+		require.NoError(t, sqlStore.LockRingAPI(target.ID))
+		defer sqlStore.UnlockRingAPI(target.ID) //nolint
+
+		_, err = client.PromoteRing(target.ID, source.ID)
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("forced source release downgrading the ring", func(t *testing.T) {
+		forcedRelease, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "0.9.0", Force: true})
+		require.NoError(t, err)
+		forced := &model.Ring{
+			Name:             "hotfix",
+			Priority:         3,
+			State:            model.RingStateStable,
+			ActiveReleaseID:  forcedRelease.ID,
+			DesiredReleaseID: forcedRelease.ID,
+		}
+		err = sqlStore.CreateRing(forced, nil)
+		require.NoError(t, err)
+
+		_, err = client.PromoteRing(target.ID, forced.ID)
+		require.EqualError(t, err, "failed with status code 400")
+
+		ring, err := sqlStore.GetRing(target.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateStable, ring.State)
+		require.Equal(t, release1.ID, ring.DesiredReleaseID)
+	})
+
+	t.Run("promote", func(t *testing.T) {
+		ring, err := client.PromoteRing(target.ID, source.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateReleasePending, ring.State)
+		require.Equal(t, release2.ID, ring.DesiredReleaseID)
+		require.Equal(t, release1.ID, ring.ActiveReleaseID)
+	})
+}
+
+func TestRetryReleaseRing(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "1.0.0"})
+	require.NoError(t, err)
+
+	ring := &model.Ring{
+		Name:             "ring",
+		Priority:         1,
+		State:            model.RingStateReleaseInProgress,
+		DesiredReleaseID: release.ID,
+	}
+	err = sqlStore.CreateRing(ring, nil)
+	require.NoError(t, err)
+
+	t.Run("unknown ring", func(t *testing.T) {
+		_, err := client.RetryReleaseRing(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("ring release in progress", func(t *testing.T) {
+		_, err := client.RetryReleaseRing(ring.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		ring.State = model.RingStateReleaseFailed
+		err = sqlStore.UpdateRing(ring)
+		require.NoError(t, err)
+
+		retried, err := client.RetryReleaseRing(ring.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateReleasePending, retried.State)
+		require.Equal(t, release.ID, retried.DesiredReleaseID)
+	})
+}
+
+func TestReleaseVersionGuardrails(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	canary, err := client.CreateRing(&model.CreateRingRequest{Name: "canary", Priority: 1, Image: "image", Version: "7.8.0"})
+	require.NoError(t, err)
+	production, err := client.CreateRing(&model.CreateRingRequest{Name: "production", Priority: 2, Image: "image", Version: "7.8.0"})
+	require.NoError(t, err)
+	for _, ring := range []*model.Ring{canary, production} {
+		ring.State = model.RingStateStable
+		require.NoError(t, sqlStore.UpdateRing(ring))
+	}
+
+	t.Run("newer than an earlier ring", func(t *testing.T) {
+		_, err := client.ReleaseRing(production.ID, &model.RingReleaseRequest{Image: "image", Version: "7.9.0"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("downgrade", func(t *testing.T) {
+		_, err := client.ReleaseRing(canary.ID, &model.RingReleaseRequest{Image: "image", Version: "7.7.0"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("downgrade of all rings", func(t *testing.T) {
+		_, err := client.ReleaseAllRings(&model.RingReleaseRequest{Image: "image", Version: "7.7.0"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("version that is not semantic", func(t *testing.T) {
+		ring, err := client.ReleaseRing(production.ID, &model.RingReleaseRequest{Image: "image", Version: "latest"})
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateReleasePending, ring.State)
+
+		ring.State = model.RingStateStable
+		ring.DesiredReleaseID = ring.ActiveReleaseID
+		require.NoError(t, sqlStore.UpdateRing(ring))
+	})
+
+	t.Run("allowed downgrade", func(t *testing.T) {
+		ring, err := client.ReleaseRing(canary.ID, &model.RingReleaseRequest{Image: "image", Version: "7.7.0", AllowDowngrade: true})
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateReleasePending, ring.State)
+	})
+
+	t.Run("upgrade of all rings", func(t *testing.T) {
+		canary.State = model.RingStateStable
+		canary.DesiredReleaseID = canary.ActiveReleaseID
+		require.NoError(t, sqlStore.UpdateRing(canary))
+
+		rings, err := client.ReleaseAllRings(&model.RingReleaseRequest{Image: "image", Version: "7.9.0"})
+		require.NoError(t, err)
+		require.Len(t, rings, 2)
+		require.Equal(t, model.RingStateReleasePending, rings[0].State)
+	})
+}
+
+func TestGetRingReleases(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	ring, err := client.CreateRing(&model.CreateRingRequest{
+		Name:              "ring",
+		Priority:          1,
+		Image:             "image",
+		Version:           "7.8.0",
+		InstallationGroup: &model.InstallationGroup{Name: "ig"},
+	})
+	require.NoError(t, err)
+	ring.State = model.RingStateStable
+	require.NoError(t, sqlStore.UpdateRing(ring))
+
+	releasedAfter := time.Now().UnixNano()
+	ring, err = client.ReleaseRing(ring.ID, &model.RingReleaseRequest{Image: "image", Version: "7.9.0", Force: true})
+	require.NoError(t, err)
+
+	t.Run("all", func(t *testing.T) {
+		ringReleases, err := client.GetRingReleases(&model.GetRingReleasesRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, ringReleases, 2)
+
+		require.Equal(t, ring.ActiveReleaseID, ringReleases[0].ID)
+		require.Equal(t, []string{ring.ID}, ringReleases[0].ActiveRings)
+		require.Len(t, ringReleases[0].ActiveInstallationGroups, 1)
+
+		require.Equal(t, ring.DesiredReleaseID, ringReleases[1].ID)
+		require.Equal(t, "7.9.0", ringReleases[1].Version)
+		require.Equal(t, []string{ring.ID}, ringReleases[1].DesiredRings)
+		require.Len(t, ringReleases[1].DesiredInstallationGroups, 1)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		forced := true
+		for _, request := range []*model.GetRingReleasesRequest{
+			{Version: "7.9.0", PerPage: 10},
+			{CreatedAfter: releasedAfter, PerPage: 10},
+			{Force: &forced, PerPage: 10},
+			{Image: "image", Page: 1, PerPage: 1},
+		} {
+			ringReleases, err := client.GetRingReleases(request)
+			require.NoError(t, err)
+			require.Len(t, ringReleases, 1)
+			require.Equal(t, ring.DesiredReleaseID, ringReleases[0].ID)
+		}
+
+		ringReleases, err := client.GetRingReleases(&model.GetRingReleasesRequest{Image: "other", PerPage: 10})
+		require.NoError(t, err)
+		require.Empty(t, ringReleases)
+	})
+}
+
+func TestGetRingMatrix(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	matrix, err := client.GetRingMatrix()
+	require.NoError(t, err)
+	require.Empty(t, matrix.Rings)
+
+	ring, err := client.CreateRing(&model.CreateRingRequest{
+		Name:              "ring",
+		Priority:          1,
+		Image:             "image",
+		Version:           "7.8.0",
+		InstallationGroup: &model.InstallationGroup{Name: "ig"},
+	})
+	require.NoError(t, err)
+	ring.State = model.RingStateStable
+	require.NoError(t, sqlStore.UpdateRing(ring))
+
+	_, err = client.ReleaseRing(ring.ID, &model.RingReleaseRequest{Image: "image", Version: "7.9.0"})
+	require.NoError(t, err)
+
+	matrix, err = client.GetRingMatrix()
+	require.NoError(t, err)
+	require.Len(t, matrix.Rings, 1)
+	require.Equal(t, ring.ID, matrix.Rings[0].ID)
+	require.Equal(t, model.RingStateReleasePending, matrix.Rings[0].State)
+	require.Equal(t, "image:7.8.0", matrix.Rings[0].ActiveRelease.String())
+	require.Equal(t, "image:7.9.0", matrix.Rings[0].DesiredRelease.String())
+	require.Equal(t, []string{model.DriftReleasePending}, matrix.Rings[0].Drift)
+	require.Len(t, matrix.Rings[0].InstallationGroups, 1)
+	require.Equal(t, "ig", matrix.Rings[0].InstallationGroups[0].Name)
+	require.Equal(t, "image:7.8.0", matrix.Rings[0].InstallationGroups[0].ActiveRelease.String())
+}
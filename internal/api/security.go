@@ -8,19 +8,20 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/model"
 )
 
 // initSecurity registers security endpoints on the given router.
 func initSecurity(apiRouter *mux.Router, context *Context) {
-	addContext := func(handler contextHandlerFunc) *contextHandler {
-		return newContextHandler(context, handler)
+	addContext := func(handler contextHandlerFunc, role string) *contextHandler {
+		return newContextHandler(context, handler, role)
 	}
 
 	securityRouter := apiRouter.PathPrefix("/security").Subrouter()
 
 	securityClusterRouter := securityRouter.PathPrefix("/ring/{ring:[A-Za-z0-9]{26}}").Subrouter()
-	securityClusterRouter.Handle("/api/lock", addContext(handleRingLockAPI)).Methods("POST")
-	securityClusterRouter.Handle("/api/unlock", addContext(handleRingUnlockAPI)).Methods("POST")
+	securityClusterRouter.Handle("/api/lock", addContext(handleRingLockAPI, model.RoleAdmin)).Methods("POST")
+	securityClusterRouter.Handle("/api/unlock", addContext(handleRingUnlockAPI, model.RoleAdmin)).Methods("POST")
 }
 
 // handleRingLockAPI responds to POST /api/security/ring/{ring}/api/lock,
@@ -0,0 +1,123 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/auth"
+	"github.com/mattermost/elrond/model"
+)
+
+// initToken registers API token endpoints on the given router.
+func initToken(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc, role string) *contextHandler {
+		return newContextHandler(context, handler, role)
+	}
+
+	tokensRouter := apiRouter.PathPrefix("/tokens").Subrouter()
+	tokensRouter.Handle("", addContext(handleGetAPITokens, model.RoleAdmin)).Methods("GET")
+	tokensRouter.Handle("", addContext(handleCreateAPIToken, model.RoleAdmin)).Methods("POST")
+
+	tokenRouter := apiRouter.PathPrefix("/token/{token:[A-Za-z0-9]{26}}").Subrouter()
+	tokenRouter.Handle("", addContext(handleDeleteAPIToken, model.RoleAdmin)).Methods("DELETE")
+}
+
+// handleCreateAPIToken responds to POST /api/tokens, creating a new API token.
+// The token itself is only ever returned in this response.
+func handleCreateAPIToken(c *Context, w http.ResponseWriter, r *http.Request) {
+	createAPITokenRequest, err := model.NewCreateAPITokenRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to generate API token")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	apiToken := model.APIToken{
+		Name:      createAPITokenRequest.Name,
+		Role:      createAPITokenRequest.Role,
+		TokenHash: auth.HashToken(token),
+		CreatedBy: c.Caller(),
+	}
+
+	if err = c.Store.CreateAPIToken(&apiToken); err != nil {
+		c.Logger.WithError(err).Error("failed to create API token")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Logger.WithField("token", apiToken.ID).Infof("API token %s created with role %s", apiToken.Name, apiToken.Role)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	outputJSON(c, w, &model.CreateAPITokenResponse{APIToken: &apiToken, Token: token})
+}
+
+// handleGetAPITokens responds to GET /api/tokens, returning the specified page of API tokens.
+func handleGetAPITokens(c *Context, w http.ResponseWriter, r *http.Request) {
+	page, perPage, includeDeleted, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	apiTokens, err := c.Store.GetAPITokens(&model.APITokenFilter{
+		Page:           page,
+		PerPage:        perPage,
+		IncludeDeleted: includeDeleted,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query API tokens")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if apiTokens == nil {
+		apiTokens = []*model.APIToken{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, apiTokens)
+}
+
+// handleDeleteAPIToken responds to DELETE /api/token/{token}, revoking the API token.
+func handleDeleteAPIToken(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	apiTokenID := vars["token"]
+	c.Logger = c.Logger.WithField("token", apiTokenID)
+
+	apiToken, err := c.Store.GetAPIToken(apiTokenID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query API token")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if apiToken == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if apiToken.IsDeleted() {
+		c.Logger.Warn("unable to delete API token that is already deleted")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err = c.Store.DeleteAPIToken(apiTokenID); err != nil {
+		c.Logger.WithError(err).Error("failed to mark API token as deleted")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
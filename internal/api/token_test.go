@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/api"
+	"github.com/mattermost/elrond/internal/auth"
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorization(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		Logger:        logger,
+		Authenticator: auth.NewTokenAuthenticator(sqlStore),
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	adminToken, err := auth.GenerateToken()
+	require.NoError(t, err)
+	err = sqlStore.CreateAPIToken(&model.APIToken{
+		Name:      "admin",
+		Role:      model.RoleAdmin,
+		TokenHash: auth.HashToken(adminToken),
+	})
+	require.NoError(t, err)
+
+	adminClient := model.NewClientWithToken(ts.URL, adminToken)
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := model.NewClient(ts.URL).GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.EqualError(t, err, "failed with status code 401")
+
+		_, err = model.NewClientWithToken(ts.URL, auth.TokenPrefix+"invalid").GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.EqualError(t, err, "failed with status code 401")
+	})
+
+	viewer, err := adminClient.CreateAPIToken(&model.CreateAPITokenRequest{Name: "dashboard", Role: model.RoleViewer})
+	require.NoError(t, err)
+	require.NotEmpty(t, viewer.Token)
+	require.Equal(t, "admin", viewer.CreatedBy)
+
+	releaser, err := adminClient.CreateAPIToken(&model.CreateAPITokenRequest{Name: "ci", Role: model.RoleReleaser})
+	require.NoError(t, err)
+
+	viewerClient := model.NewClientWithToken(ts.URL, viewer.Token)
+	releaserClient := model.NewClientWithToken(ts.URL, releaser.Token)
+
+	t.Run("invalid role", func(t *testing.T) {
+		_, err := adminClient.CreateAPIToken(&model.CreateAPITokenRequest{Name: "bad", Role: "superuser"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("viewer", func(t *testing.T) {
+		rings, err := viewerClient.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Empty(t, rings)
+
+		_, err = viewerClient.CreateRing(&model.CreateRingRequest{Name: "ring", Priority: 1})
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = viewerClient.GetAPITokens(&model.GetAPITokensRequest{PerPage: 10})
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("releaser", func(t *testing.T) {
+		ring, err := releaserClient.CreateRing(&model.CreateRingRequest{Name: "ring", Priority: 1, Image: "image", Version: "1.0.0"})
+		require.NoError(t, err)
+
+		release, err := releaserClient.GetRingRelease(ring.DesiredReleaseID)
+		require.NoError(t, err)
+		require.Equal(t, "ci", release.CreatedBy)
+
+		err = releaserClient.DeleteRing(ring.ID)
+		require.EqualError(t, err, "failed with status code 403")
+
+		err = releaserClient.LockAPIForRing(ring.ID)
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		tokens, err := adminClient.GetAPITokens(&model.GetAPITokensRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, tokens, 3)
+
+		err = adminClient.DeleteAPIToken(viewer.ID)
+		require.NoError(t, err)
+
+		err = adminClient.DeleteAPIToken(viewer.ID)
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = viewerClient.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.EqualError(t, err, "failed with status code 401")
+	})
+
+	t.Run("bearer challenge", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/rings")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+	})
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/webhook"
+	"github.com/mattermost/elrond/model"
+)
+
+// initTopology registers topology endpoints on the given router.
+func initTopology(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc, role string) *contextHandler {
+		return newContextHandler(context, handler, role)
+	}
+
+	topologyRouter := apiRouter.PathPrefix("/topology").Subrouter()
+	topologyRouter.Handle("/apply", addContext(idempotent(handleApplyTopology), model.RoleReleaser)).Methods("POST")
+	topologyRouter.Handle("/export", addContext(handleExportTopology, model.RoleAdmin)).Methods("GET")
+}
+
+// handleApplyTopology responds to POST /api/topology/apply, converging the rings and
+// installation groups to the given topology.
+func handleApplyTopology(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.WithField("action", "apply-topology")
+
+	applyTopologyRequest, err := model.NewApplyTopologyRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if applyTopologyRequest.Prune && c.Identity != nil && !model.RoleAllows(c.Identity.Role, model.RoleAdmin) {
+		c.Logger.Warn("pruning rings requires the admin role")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	plan, status := planTopology(c, applyTopologyRequest)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	if applyTopologyRequest.DryRun || plan.IsEmpty() {
+		if status = checkTopologyPlan(c, applyTopologyRequest, plan); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		outputJSON(c, w, plan)
+		return
+	}
+
+	ringIDs := topologyPlanRingIDs(plan)
+	if len(ringIDs) > 0 {
+		var unlockOnce func()
+		status, unlockOnce = lockRings(c, ringIDs)
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		defer unlockOnce()
+
+		// The rings may have changed before they were locked.
+		plan, status = planTopology(c, applyTopologyRequest)
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		if strings.Join(topologyPlanRingIDs(plan), ",") != strings.Join(ringIDs, ",") {
+			c.Logger.Warn("rings changed while applying topology")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+	}
+
+	if status = checkTopologyPlan(c, applyTopologyRequest, plan); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	var deletedRings []*model.Ring
+	for _, ringID := range ringIDs {
+		ring, err := c.Store.GetRing(ringID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query ring")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if ring.APISecurityLock {
+			logSecurityLockConflict("ring", c.Logger)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		for _, change := range plan.Changes {
+			if change.Kind != model.TopologyKindRing || change.Action != model.TopologyActionDelete || change.ID != ring.ID {
+				continue
+			}
+			if !ring.ValidTransitionState(model.RingStateDeletionRequested) {
+				c.Logger.Warnf("unable to delete ring %s while in state %s", ring.Name, ring.State)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			deletedRings = append(deletedRings, ring)
+		}
+	}
+
+	var releaseID string
+	if topologyPlanCreatesRings(plan) {
+		release, err := c.Store.GetOrCreateRingRelease(&model.RingRelease{
+			Version:   applyTopologyRequest.Topology.Version,
+			Image:     applyTopologyRequest.Topology.Image,
+			CreateAt:  time.Now().UnixNano(),
+			CreatedBy: c.Caller(),
+		})
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to get or create new ring release")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		releaseID = release.ID
+	}
+
+	createdRings, err := c.Store.ApplyTopology(plan, releaseID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to apply topology")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	plan.Applied = true
+
+	for _, ring := range createdRings {
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeRing,
+			ID:        ring.ID,
+			Name:      ring.Name,
+			NewState:  model.RingStateCreationRequested,
+			OldState:  "n/a",
+			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
+		}
+		if err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState)); err != nil {
+			c.Logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+	for _, ring := range deletedRings {
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeRing,
+			ID:        ring.ID,
+			Name:      ring.Name,
+			NewState:  model.RingStateDeletionRequested,
+			OldState:  ring.State,
+			Timestamp: time.Now().UnixNano(),
+			Actor:     c.Caller(),
+		}
+		if err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState)); err != nil {
+			c.Logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	c.Supervisor.Do() //nolint
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, plan)
+}
+
+// handleExportTopology responds to GET /api/topology/export, returning a snapshot of
+// the rings, installation groups, webhooks and releases.
+func handleExportTopology(c *Context, w http.ResponseWriter, _ *http.Request) {
+	export, err := c.Store.ExportTopology()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to export topology")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, export)
+}
+
+// planTopology computes the changes needed to converge the current rings to the
+// requested topology.
+func planTopology(c *Context, request *model.ApplyTopologyRequest) (*model.TopologyPlan, int) {
+	if c.Elrond != nil {
+		for _, ring := range request.Topology.Rings {
+			for _, installationGroup := range ring.InstallationGroups {
+				if !c.Elrond.ProvisionerTargetExists(installationGroup.ProvisionerTarget) {
+					c.Logger.Warnf("provisioner target %s of installation group %s is not configured", installationGroup.ProvisionerTarget, installationGroup.Name)
+					return nil, http.StatusBadRequest
+				}
+			}
+		}
+	}
+
+	filter := &model.RingFilter{PerPage: model.AllPerPage}
+
+	rings, err := c.Store.GetRings(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query rings")
+		return nil, http.StatusInternalServerError
+	}
+
+	installationGroups, err := c.Store.GetInstallationGroupsForRings(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get installation groups for rings")
+		return nil, http.StatusInternalServerError
+	}
+
+	for _, ring := range rings {
+		ring.InstallationGroups = installationGroups[ring.ID]
+	}
+
+	plan, err := model.PlanTopology(request.Topology, rings, request.Prune)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to plan topology")
+		return nil, http.StatusBadRequest
+	}
+
+	return plan, 0
+}
+
+// checkTopologyPlan returns the status code preventing the plan from being applied, or 0 if
+// it can be applied. Rings can only be created with the image and version of the topology,
+// the rings and installation groups changed by the plan cannot be releasing, and the
+// provisioner groups of the installation groups it registers or updates must exist and
+// belong to no other installation group.
+func checkTopologyPlan(c *Context, request *model.ApplyTopologyRequest, plan *model.TopologyPlan) int {
+	if topologyPlanCreatesRings(plan) && (request.Topology.Image == "" || request.Topology.Version == "") {
+		c.Logger.Warn("the topology needs an image and a version to create rings")
+		return http.StatusBadRequest
+	}
+
+	for _, ringID := range topologyPlanRingIDs(plan) {
+		ring, err := c.Store.GetRing(ringID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query ring")
+			return http.StatusInternalServerError
+		}
+		if ring != nil && ring.IsReleasing() {
+			c.Logger.Warnf("unable to apply topology while ring %s is in state %s", ring.Name, ring.State)
+			return http.StatusBadRequest
+		}
+	}
+
+	for _, change := range plan.Changes {
+		if change.Kind != model.TopologyKindInstallationGroup || change.ID == "" {
+			continue
+		}
+		installationGroup, err := c.Store.GetInstallationGroupByID(change.ID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query installation group")
+			return http.StatusInternalServerError
+		}
+		if installationGroup != nil && installationGroup.IsReleasing() {
+			c.Logger.Warnf("unable to apply topology while installation group %s is in state %s", installationGroup.Name, installationGroup.State)
+			return http.StatusBadRequest
+		}
+	}
+
+	for _, change := range plan.Changes {
+		if change.Kind != model.TopologyKindInstallationGroup || (change.Action != model.TopologyActionRegister && change.Action != model.TopologyActionUpdate) {
+			continue
+		}
+		if status := checkProvisionerGroup(c, change.ID, change.ProvisionerTarget, change.ProvisionerGroupID); status != 0 {
+			return status
+		}
+	}
+
+	return 0
+}
+
+// topologyPlanCreatesRings returns whether the plan creates rings.
+func topologyPlanCreatesRings(plan *model.TopologyPlan) bool {
+	for _, change := range plan.Changes {
+		if change.Kind == model.TopologyKindRing && change.Action == model.TopologyActionCreate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// topologyPlanRingIDs returns the sorted IDs of the existing rings changed by the plan.
+func topologyPlanRingIDs(plan *model.TopologyPlan) []string {
+	ringIDs := map[string]bool{}
+	for _, change := range plan.Changes {
+		if change.Kind == model.TopologyKindRing && change.ID != "" {
+			ringIDs[change.ID] = true
+		}
+		if change.Kind == model.TopologyKindInstallationGroup && change.RingID != "" {
+			ringIDs[change.RingID] = true
+		}
+	}
+
+	var ids []string
+	for id := range ringIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
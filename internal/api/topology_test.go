@@ -0,0 +1,275 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/elrond/internal/api"
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTopology(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	topology := &model.Topology{
+		Image:   "mattermost/mattermost-enterprise-edition",
+		Version: "1.0.0",
+		Rings: []*model.TopologyRing{
+			{Name: "canary", Priority: 1, SoakTime: 60, InstallationGroups: []*model.TopologyInstallationGroup{
+				{Name: "ig-1", SoakTime: 30, ProvisionerGroupID: "pg1"},
+			}},
+			{Name: "production", Priority: 2, InstallationGroups: []*model.TopologyInstallationGroup{
+				{Name: "ig-2", SoakTime: 30, ProvisionerGroupID: "pg2"},
+				{Name: "ig-3", SoakTime: 30, ProvisionerGroupID: "pg3"},
+			}},
+		},
+	}
+
+	t.Run("invalid topology", func(t *testing.T) {
+		_, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: &model.Topology{
+			Rings: []*model.TopologyRing{{Name: "ring"}},
+		}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		plan, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology, DryRun: true})
+		require.NoError(t, err)
+		require.False(t, plan.Applied)
+		require.Len(t, plan.Changes, 5)
+
+		rings, err := client.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Empty(t, rings)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		plan, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology})
+		require.NoError(t, err)
+		require.True(t, plan.Applied)
+		require.Len(t, plan.Changes, 5)
+
+		rings, err := client.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, rings, 2)
+		require.Equal(t, "production", rings[1].Name)
+		require.Equal(t, 7200, rings[1].SoakTime)
+		require.Len(t, rings[1].InstallationGroups, 2)
+
+		release, err := client.GetRingRelease(rings[0].DesiredReleaseID)
+		require.NoError(t, err)
+		require.Equal(t, "1.0.0", release.Version)
+	})
+
+	t.Run("converged", func(t *testing.T) {
+		plan, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology})
+		require.NoError(t, err)
+		require.True(t, plan.IsEmpty())
+		require.False(t, plan.Applied)
+	})
+
+	t.Run("converge changes", func(t *testing.T) {
+		topology.Rings[0].InstallationGroups = append(topology.Rings[0].InstallationGroups, topology.Rings[1].InstallationGroups[1])
+		topology.Rings[1].InstallationGroups = topology.Rings[1].InstallationGroups[:1]
+		topology.Rings[1].Priority = 3
+
+		plan, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology})
+		require.NoError(t, err)
+		require.True(t, plan.Applied)
+		require.Len(t, plan.Changes, 3)
+
+		rings, err := client.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, rings, 2)
+		require.Len(t, rings[0].InstallationGroups, 2)
+		require.Len(t, rings[1].InstallationGroups, 1)
+		require.Equal(t, 3, rings[1].Priority)
+	})
+
+	t.Run("security lock", func(t *testing.T) {
+		rings, err := client.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.NoError(t, sqlStore.LockRingAPI(rings[1].ID))
+		defer sqlStore.UnlockRingAPI(rings[1].ID) //nolint
+
+		topology.Rings[1].Priority = 4
+		_, err = client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology})
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("releasing ring", func(t *testing.T) {
+		rings, err := client.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		ring := rings[1]
+		ring.InstallationGroups = nil
+		ring.State = model.RingStateReleasePending
+		require.NoError(t, sqlStore.UpdateRing(ring))
+		defer func() {
+			ring.State = model.RingStateStable
+			require.NoError(t, sqlStore.UpdateRing(ring))
+		}()
+
+		topology.Rings[1].Priority = 4
+		_, err = client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology, DryRun: true})
+		require.EqualError(t, err, "failed with status code 400")
+		_, err = client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("releasing installation group", func(t *testing.T) {
+		rings, err := client.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		installationGroup := rings[0].InstallationGroups[0]
+		installationGroup.State = model.InstallationGroupReleaseRequested
+		require.NoError(t, sqlStore.UpdateInstallationGroup(installationGroup))
+		defer func() {
+			installationGroup.State = model.InstallationGroupStable
+			require.NoError(t, sqlStore.UpdateInstallationGroup(installationGroup))
+		}()
+
+		topology.Rings[1].Priority = 3
+		for _, desired := range topology.Rings[0].InstallationGroups {
+			if desired.Name == installationGroup.Name {
+				desired.ProvisionerGroupID = "pg4"
+				defer func() { desired.ProvisionerGroupID = installationGroup.ProvisionerGroupID }()
+			}
+		}
+		_, err = client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology})
+		require.EqualError(t, err, "failed with status code 400")
+
+		stored, err := sqlStore.GetInstallationGroupByID(installationGroup.ID)
+		require.NoError(t, err)
+		require.Equal(t, installationGroup.ProvisionerGroupID, stored.ProvisionerGroupID)
+	})
+
+	t.Run("new ring without release", func(t *testing.T) {
+		_, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: &model.Topology{
+			Rings: append([]*model.TopologyRing{{Name: "new", Priority: 5}}, topology.Rings...),
+		}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("no new ring without release", func(t *testing.T) {
+		plan, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: &model.Topology{
+			Rings: []*model.TopologyRing{
+				{Name: "canary", Priority: 1, SoakTime: 120, InstallationGroups: topology.Rings[0].InstallationGroups},
+				topology.Rings[1],
+			},
+		}})
+		require.NoError(t, err)
+		require.True(t, plan.Applied)
+
+		releases, err := sqlStore.GetRingReleases(&model.RingReleaseFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, releases, 1)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		export, err := client.ExportTopology()
+		require.NoError(t, err)
+		require.Len(t, export.Rings, 2)
+		require.Len(t, export.Releases, 1)
+		require.Empty(t, export.Webhooks)
+	})
+}
+
+func TestApplyTopologyProvisionerGroups(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Elrond:     &mockElrond{ProvisionerGroups: map[string]bool{"pg1": true, "pg2": true}},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	plan, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: &model.Topology{
+		Image:   "mattermost/mattermost-enterprise-edition",
+		Version: "1.0.0",
+		Rings: []*model.TopologyRing{
+			{Name: "canary", Priority: 1, InstallationGroups: []*model.TopologyInstallationGroup{
+				{Name: "ig-1", ProvisionerGroupID: "pg1"},
+			}},
+		},
+	}})
+	require.NoError(t, err)
+	require.True(t, plan.Applied)
+
+	t.Run("unknown provisioner group", func(t *testing.T) {
+		topology := &model.Topology{Rings: []*model.TopologyRing{
+			{Name: "canary", Priority: 1, InstallationGroups: []*model.TopologyInstallationGroup{
+				{Name: "ig-1", ProvisionerGroupID: "pg3"},
+			}},
+		}}
+		_, err = client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology, DryRun: true})
+		require.EqualError(t, err, "failed with status code 400")
+		_, err = client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("provisioner group registered to another installation group", func(t *testing.T) {
+		topology := &model.Topology{
+			Image:   "mattermost/mattermost-enterprise-edition",
+			Version: "1.0.0",
+			Rings: []*model.TopologyRing{
+				{Name: "production", Priority: 2, InstallationGroups: []*model.TopologyInstallationGroup{
+					{Name: "ig-2", ProvisionerGroupID: "pg1"},
+				}},
+			},
+		}
+		_, err = client.ApplyTopology(&model.ApplyTopologyRequest{Topology: topology})
+		require.EqualError(t, err, "failed with status code 409")
+
+		rings, err := client.GetRings(&model.GetRingsRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Len(t, rings, 1)
+	})
+
+	t.Run("provisioner group registered twice", func(t *testing.T) {
+		_, err = client.ApplyTopology(&model.ApplyTopologyRequest{Topology: &model.Topology{Rings: []*model.TopologyRing{
+			{Name: "canary", Priority: 1, InstallationGroups: []*model.TopologyInstallationGroup{
+				{Name: "ig-1", ProvisionerGroupID: "pg2"},
+				{Name: "ig-2", ProvisionerGroupID: "pg2"},
+			}},
+		}}})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("provisioner group moved", func(t *testing.T) {
+		plan, err := client.ApplyTopology(&model.ApplyTopologyRequest{Topology: &model.Topology{Rings: []*model.TopologyRing{
+			{Name: "canary", Priority: 1, InstallationGroups: []*model.TopologyInstallationGroup{
+				{Name: "ig-1", ProvisionerGroupID: "pg2"},
+			}},
+		}}})
+		require.NoError(t, err)
+		require.True(t, plan.Applied)
+	})
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+// Package auth handles authentication of API callers for the elrond server.
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MethodToken identifies callers authenticated with a static API token.
+	MethodToken = "token"
+	// MethodJWT identifies callers authenticated with a JWT bearer token.
+	MethodJWT = "jwt"
+)
+
+var (
+	// ErrNoCredentials is returned when a request carries no bearer token.
+	ErrNoCredentials = errors.New("no credentials provided")
+	// ErrInvalidCredentials is returned when a bearer token is not accepted.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Identity describes an authenticated API caller.
+type Identity struct {
+	Subject string
+	Role    string
+	Method  string
+}
+
+// Authenticator resolves the identity of the caller making an API request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+// Chain is an Authenticator trying each of its authenticators in turn, returning
+// the first identity successfully resolved.
+type Chain []Authenticator
+
+// Authenticate implements Authenticator.
+func (c Chain) Authenticate(r *http.Request) (*Identity, error) {
+	var lastErr error = ErrNoCredentials
+	for _, authenticator := range c {
+		identity, err := authenticator.Authenticate(r)
+		if err == nil {
+			return identity, nil
+		}
+		if err != ErrNoCredentials {
+			lastErr = err
+		}
+	}
+
+	return nil, lastErr
+}
+
+// bearerToken extracts the bearer token from the Authorization header of the request.
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrNoCredentials
+	}
+
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errors.Wrap(ErrInvalidCredentials, "authorization header is not a bearer token")
+	}
+
+	return strings.TrimSpace(token), nil
+}
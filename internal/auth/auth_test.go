@@ -0,0 +1,248 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTokenStore struct {
+	tokens map[string]*model.APIToken
+}
+
+func (s *mockTokenStore) GetAPITokenByHash(tokenHash string) (*model.APIToken, error) {
+	return s.tokens[tokenHash], nil
+}
+
+func requestWithToken(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/api/rings", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	token, err := GenerateToken()
+	require.NoError(t, err)
+	require.Contains(t, token, TokenPrefix)
+
+	revoked, err := GenerateToken()
+	require.NoError(t, err)
+
+	store := &mockTokenStore{tokens: map[string]*model.APIToken{
+		HashToken(token):   {Name: "ci", Role: model.RoleReleaser},
+		HashToken(revoked): {Name: "old", Role: model.RoleAdmin, DeleteAt: 1},
+	}}
+	authenticator := NewTokenAuthenticator(store)
+
+	t.Run("valid token", func(t *testing.T) {
+		identity, err := authenticator.Authenticate(requestWithToken(token))
+		require.NoError(t, err)
+		require.Equal(t, &Identity{Subject: "ci", Role: model.RoleReleaser, Method: MethodToken}, identity)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		_, err := authenticator.Authenticate(requestWithToken(""))
+		require.Equal(t, ErrNoCredentials, err)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := authenticator.Authenticate(requestWithToken(TokenPrefix + "unknown"))
+		require.Error(t, err)
+	})
+
+	t.Run("revoked token", func(t *testing.T) {
+		_, err := authenticator.Authenticate(requestWithToken(revoked))
+		require.Error(t, err)
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		r := requestWithToken("")
+		r.SetBasicAuth("user", "password")
+		_, err := authenticator.Authenticate(r)
+		require.Error(t, err)
+		require.NotEqual(t, ErrNoCredentials, err)
+	})
+}
+
+func signJWT(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJWKS(t *testing.T, key *rsa.PublicKey, kid string) string {
+	jwks := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	data, err := json.Marshal(jwks)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+
+	return path
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authenticator, err := NewJWTAuthenticator(JWTConfig{
+		JWKSFile:  writeJWKS(t, &key.PublicKey, "key1"),
+		Issuer:    "https://issuer.example.com",
+		Audience:  "elrond",
+		RoleClaim: "elrond_role",
+	})
+	require.NoError(t, err)
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"sub":         "jane@example.com",
+			"iss":         "https://issuer.example.com",
+			"aud":         []string{"elrond", "other"},
+			"exp":         time.Now().Add(time.Hour).Unix(),
+			"elrond_role": model.RoleAdmin,
+		}
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		identity, err := authenticator.Authenticate(requestWithToken(signJWT(t, key, "key1", validClaims())))
+		require.NoError(t, err)
+		require.Equal(t, &Identity{Subject: "jane@example.com", Role: model.RoleAdmin, Method: MethodJWT}, identity)
+	})
+
+	t.Run("role list picks most privileged", func(t *testing.T) {
+		claims := validClaims()
+		claims["elrond_role"] = []string{"unknown", model.RoleViewer, model.RoleReleaser}
+		identity, err := authenticator.Authenticate(requestWithToken(signJWT(t, key, "key1", claims)))
+		require.NoError(t, err)
+		require.Equal(t, model.RoleReleaser, identity.Role)
+	})
+
+	invalid := map[string]func(claims map[string]interface{}) (*rsa.PrivateKey, string){
+		"wrong key": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			return otherKey, "key1"
+		},
+		"unknown kid": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			return key, "key2"
+		},
+		"expired": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			return key, "key1"
+		},
+		"not yet valid": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			claims["nbf"] = time.Now().Add(time.Hour).Unix()
+			return key, "key1"
+		},
+		"wrong issuer": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			claims["iss"] = "https://evil.example.com"
+			return key, "key1"
+		},
+		"wrong audience": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			claims["aud"] = "other"
+			return key, "key1"
+		},
+		"no role": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			delete(claims, "elrond_role")
+			return key, "key1"
+		},
+		"unknown role": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			claims["elrond_role"] = "superuser"
+			return key, "key1"
+		},
+		"no subject": func(claims map[string]interface{}) (*rsa.PrivateKey, string) {
+			delete(claims, "sub")
+			return key, "key1"
+		},
+	}
+
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			claims := validClaims()
+			signingKey, kid := mutate(claims)
+			_, err := authenticator.Authenticate(requestWithToken(signJWT(t, signingKey, kid, claims)))
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := authenticator.Authenticate(requestWithToken("not.a-jwt"))
+		require.Error(t, err)
+	})
+}
+
+func TestChain(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	token, err := GenerateToken()
+	require.NoError(t, err)
+
+	jwtAuthenticator, err := NewJWTAuthenticator(JWTConfig{
+		JWKSFile:  writeJWKS(t, &key.PublicKey, "key1"),
+		RoleClaim: "role",
+	})
+	require.NoError(t, err)
+
+	chain := Chain{
+		NewTokenAuthenticator(&mockTokenStore{tokens: map[string]*model.APIToken{
+			HashToken(token): {Name: "ci", Role: model.RoleViewer},
+		}}),
+		jwtAuthenticator,
+	}
+
+	identity, err := chain.Authenticate(requestWithToken(token))
+	require.NoError(t, err)
+	require.Equal(t, MethodToken, identity.Method)
+
+	jwt := signJWT(t, key, "key1", map[string]interface{}{
+		"sub":  "jane",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"role": model.RoleReleaser,
+	})
+	identity, err = chain.Authenticate(requestWithToken(jwt))
+	require.NoError(t, err)
+	require.Equal(t, MethodJWT, identity.Method)
+
+	_, err = chain.Authenticate(requestWithToken(""))
+	require.Equal(t, ErrNoCredentials, err)
+
+	_, err = chain.Authenticate(requestWithToken("garbage"))
+	require.Error(t, err)
+	require.NotEqual(t, ErrNoCredentials, err)
+}
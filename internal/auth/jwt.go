@@ -0,0 +1,359 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+)
+
+// jwtLeeway is the allowed clock skew when validating time based claims.
+const jwtLeeway = time.Minute
+
+// JWTConfig describes how JWT bearer tokens are validated.
+type JWTConfig struct {
+	// JWKSFile is the path to a JSON Web Key Set holding the signing keys of the issuer.
+	JWKSFile string
+	// Issuer, if set, must match the iss claim.
+	Issuer string
+	// Audience, if set, must be present in the aud claim.
+	Audience string
+	// RoleClaim is the name of the claim holding the elrond role of the caller.
+	RoleClaim string
+}
+
+// JWTAuthenticator authenticates callers using JWTs issued by an OIDC provider,
+// verifying their signature against a JWKS file.
+type JWTAuthenticator struct {
+	keys   map[string]crypto.PublicKey
+	config JWTConfig
+	now    func() time.Time
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// NewJWTAuthenticator creates a new JWTAuthenticator loading its keys from the configured JWKS file.
+func NewJWTAuthenticator(config JWTConfig) (*JWTAuthenticator, error) {
+	if config.RoleClaim == "" {
+		return nil, errors.New("role claim must be set")
+	}
+
+	data, err := os.ReadFile(config.JWKSFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read JWKS file")
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse JWKS file")
+	}
+
+	return &JWTAuthenticator{
+		keys:   keys,
+		config: config,
+		now:    time.Now,
+	}, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	token, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := a.verify(token)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCredentials, err.Error())
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.Wrap(ErrInvalidCredentials, "token has no subject")
+	}
+
+	role := roleFromClaim(claims[a.config.RoleClaim])
+	if role == "" {
+		return nil, errors.Wrapf(ErrInvalidCredentials, "token has no valid %s claim", a.config.RoleClaim)
+	}
+
+	return &Identity{
+		Subject: subject,
+		Role:    role,
+		Method:  MethodJWT,
+	}, nil
+}
+
+// verify checks the signature and registered claims of the given token, returning its claims.
+func (a *JWTAuthenticator) verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "failed to decode token header")
+	}
+
+	key, err := a.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode token signature")
+	}
+	if err = verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "failed to decode token claims")
+	}
+	if err = a.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (a *JWTAuthenticator) key(kid string) (crypto.PublicKey, error) {
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, nil
+		}
+	}
+
+	return nil, errors.Errorf("no key found for kid %q", kid)
+}
+
+func (a *JWTAuthenticator) validateClaims(claims map[string]interface{}) error {
+	now := a.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(jwtLeeway)) {
+		return errors.New("token has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(jwtLeeway).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token is not valid yet")
+	}
+
+	if a.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.config.Issuer {
+			return errors.Errorf("unexpected issuer %q", iss)
+		}
+	}
+
+	if a.config.Audience != "" && !containsString(claims["aud"], a.config.Audience) {
+		return errors.Errorf("token is not intended for audience %q", a.config.Audience)
+	}
+
+	return nil
+}
+
+// roleFromClaim returns the most privileged known role found in the given claim,
+// which may either be a single string or a list of strings.
+func roleFromClaim(claim interface{}) string {
+	var candidates []string
+	switch value := claim.(type) {
+	case string:
+		candidates = []string{value}
+	case []interface{}:
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				candidates = append(candidates, s)
+			}
+		}
+	}
+
+	role := ""
+	for _, candidate := range candidates {
+		if model.IsValidRole(candidate) && (role == "" || model.RoleAllows(candidate, role)) {
+			role = candidate
+		}
+	}
+
+	return role
+}
+
+func containsString(claim interface{}, expected string) bool {
+	switch value := claim.(type) {
+	case string:
+		return value == expected
+	case []interface{}:
+		for _, v := range value {
+			if s, ok := v.(string); ok && s == expected {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return errors.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	hasher := hash.New()
+	hasher.Write(signed)
+	digest := hasher.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return errors.Errorf("algorithm %q does not match RSA key", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(k, hash, digest, signature); err != nil {
+			return errors.Wrap(err, "invalid token signature")
+		}
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return errors.Errorf("algorithm %q does not match EC key", alg)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+	default:
+		return errors.New("unsupported key type")
+	}
+
+	return nil
+}
+
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse key %q", k.Kid)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys found")
+	}
+
+	return keys, nil
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid modulus")
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid x coordinate")
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid y coordinate")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, errors.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package elrond
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// BackendDriverCloud deploys releases by patching Mattermost Cloud provisioner groups.
+	BackendDriverCloud = "cloud"
+	// BackendDriverHTTP deploys releases by posting them to a generic HTTP service.
+	BackendDriverHTTP = "http"
+)
+
+// Backend deploys releases to the provisioner groups of installation groups.
+type Backend interface {
+	// ApplyRelease starts deploying the release to the provisioner group of the installation
+	// group, returning false when the group already runs the release.
+	ApplyRelease(installationGroup *model.InstallationGroup, release *model.RingRelease) (bool, error)
+	// GetStatus returns the progress of the last release applied to the provisioner group of
+	// the installation group.
+	GetStatus(installationGroup *model.InstallationGroup) (*model.BackendStatus, error)
+	// Rollback starts deploying the release the provisioner group of the installation group
+	// ran before.
+	Rollback(installationGroup *model.InstallationGroup, release *model.RingRelease) error
+}
+
+// NewBackend creates the backend deploying releases to the installation groups of the named
+// provisioner target, or of the default provisioner when the target is empty.
+func (elp *ElProvisioner) NewBackend(target string) (Backend, error) {
+	if target != "" {
+		provisionerTarget, ok := elp.getProvisionerTarget(target)
+		if !ok {
+			return nil, errors.Errorf("provisioner target %s is not configured", target)
+		}
+		if provisionerTarget.Driver == BackendDriverHTTP {
+			return &httpBackend{
+				target: provisionerTarget,
+				client: &http.Client{Timeout: 30 * time.Second},
+				logger: elp.logger,
+			}, nil
+		}
+	}
+
+	client, err := elp.NewProvisionerClient(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cloudBackend{
+		client:            client,
+		logger:            elp.logger,
+		unstableThreshold: elp.params.ProvisionerGroupUnstableThreshold,
+	}, nil
+}
+
+// waitForBackendRelease waits for the backend to complete the release of the installation
+// group, failing when the backend reports the release failed or the timeout expires. A status
+// about another release, such as the previous one, counts as the release being in progress.
+func waitForBackendRelease(backend Backend, installationGroup *model.InstallationGroup, release *model.RingRelease, timeout int, pollInterval time.Duration, logger log.FieldLogger) error {
+	if pollInterval == 0 {
+		pollInterval = 60 * time.Second
+	}
+
+	timer := time.NewTimer(time.Duration(timeout) * time.Second)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			return errors.New("timed out waiting for group release to complete")
+		default:
+			status, err := backend.GetStatus(installationGroup)
+			if err != nil {
+				return err
+			}
+			if status.ReleaseID != "" && status.ReleaseID != release.ID {
+				status.State = model.BackendReleaseInProgress
+			}
+			switch status.State {
+			case model.BackendReleaseComplete:
+				if status.Message != "" {
+					logger.Warn(status.Message)
+				}
+				return nil
+			case model.BackendReleaseFailed:
+				if status.Message == "" {
+					return errors.Errorf("release of provisioner group %s failed", installationGroup.ProvisionerGroupID)
+				}
+				return errors.New(status.Message)
+			}
+			logger.Infof("Provisioner group %s release in progress...", installationGroup.ProvisionerGroupID)
+			time.Sleep(pollInterval)
+		}
+	}
+}
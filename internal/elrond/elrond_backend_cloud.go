@@ -0,0 +1,113 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package elrond
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/elrond/model"
+	cmodel "github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// cloudBackend deploys releases by patching the image, version and environment variables of
+// Mattermost Cloud provisioner groups.
+type cloudBackend struct {
+	client *cmodel.Client
+	logger log.FieldLogger
+	// unstableThreshold is the number of installations a group release tolerates in an
+	// unstable state before failing.
+	unstableThreshold int
+}
+
+// ApplyRelease patches the provisioner group when its image, version or environment
+// variables differ from the release. A release without environment variables keeps those of
+// the group.
+func (b *cloudBackend) ApplyRelease(installationGroup *model.InstallationGroup, release *model.RingRelease) (bool, error) {
+	logger := b.logger.WithField("installationgroup", installationGroup.ID)
+
+	group, err := b.client.GetGroup(installationGroup.ProvisionerGroupID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get group %s", installationGroup.ProvisionerGroupID)
+	}
+	if group == nil {
+		return false, errors.Errorf("group %s does not exist", installationGroup.ProvisionerGroupID)
+	}
+
+	newEnvVars, err := release.EnvVariables.ToJSON()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create newEnvVars JSON")
+	}
+
+	if string(newEnvVars) == "{}" {
+		release.EnvVariables = group.MattermostEnv
+	}
+
+	if group.Image == release.Image && group.Version == release.Version && !checkChangeGroupEnvVariables(group.MattermostEnv, release.EnvVariables) {
+		logger.Infof("Provisioner group image and version are already up to date with image %s:%s", group.Image, group.Version)
+		return false, nil
+	}
+
+	logger.Infof("Image or group env variable changes were detected. Current provisioner group image is %s:%s and new image is %s:%s", group.Image, group.Version, release.Image, release.Version)
+	request := &cmodel.PatchGroupRequest{
+		ID:            installationGroup.ProvisionerGroupID,
+		Version:       &release.Version,
+		Image:         &release.Image,
+		MattermostEnv: release.EnvVariables,
+	}
+
+	logger.Infof("Updating provisioner group %s", installationGroup.ProvisionerGroupID)
+	if _, err = b.client.UpdateGroup(request); err != nil {
+		return false, errors.Wrap(err, "failed to patch provisioner group")
+	}
+
+	return true, nil
+}
+
+// GetStatus reports the release failed as soon as more installations than the unstable
+// threshold are unstable, and complete once the only installations left updating are
+// unstable ones within the threshold.
+func (b *cloudBackend) GetStatus(installationGroup *model.InstallationGroup) (*model.BackendStatus, error) {
+	groupID := installationGroup.ProvisionerGroupID
+	status, err := b.client.GetGroupStatus(groupID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get provisioner group status")
+	}
+	if status == nil {
+		return nil, errors.Errorf("group %s does not exist", groupID)
+	}
+	if status.InstallationsAwaitingUpdate == 0 && status.InstallationsUpdating == 0 {
+		return &model.BackendStatus{State: model.BackendReleaseComplete}, nil
+	}
+
+	if status.InstallationsUpdating > 0 {
+		unstable, err := getUnstableInstallationIDs(b.client, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if len(unstable) > b.unstableThreshold {
+			return &model.BackendStatus{
+				State:   model.BackendReleaseFailed,
+				Message: fmt.Sprintf("%d installations of provisioner group %s are unstable, above the threshold of %d: %s", len(unstable), groupID, b.unstableThreshold, strings.Join(unstable, ", ")),
+			}, nil
+		}
+		if status.InstallationsAwaitingUpdate == 0 && int64(len(unstable)) == status.InstallationsUpdating {
+			return &model.BackendStatus{
+				State:   model.BackendReleaseComplete,
+				Message: fmt.Sprintf("Provisioner group %s release completed with %d unstable installations within the threshold of %d: %s", groupID, len(unstable), b.unstableThreshold, strings.Join(unstable, ", ")),
+			}, nil
+		}
+	}
+
+	return &model.BackendStatus{State: model.BackendReleaseInProgress}, nil
+}
+
+// Rollback patches the provisioner group back to the given release.
+func (b *cloudBackend) Rollback(installationGroup *model.InstallationGroup, release *model.RingRelease) error {
+	_, err := b.ApplyRelease(installationGroup, release)
+	return err
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package elrond
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mattermost/elrond/model"
+	cmodel "github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudBackendUnstableInstallations(t *testing.T) {
+	status := &cmodel.GroupStatus{}
+	installations := map[string][]string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/group/group1/status", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(status)
+	})
+	mux.HandleFunc("/api/installations", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "group1", r.URL.Query().Get("group"))
+		dtos := []*cmodel.InstallationDTO{}
+		for _, id := range installations[r.URL.Query().Get("state")] {
+			dtos = append(dtos, &cmodel.InstallationDTO{Installation: &cmodel.Installation{ID: id}})
+		}
+		_ = json.NewEncoder(w).Encode(dtos)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	installationGroup := &model.InstallationGroup{ProvisionerGroupID: "group1"}
+
+	t.Run("unstable installation ids", func(t *testing.T) {
+		installations = map[string][]string{
+			cmodel.InstallationStateUpdateFailed:        {"inst-c", "inst-a"},
+			cmodel.InstallationStateDBMigrationFailed:   {"inst-b"},
+			cmodel.InstallationStateUpdateInProgress:    {"inst-d"},
+			cmodel.InstallationStateCreationFailed:      {},
+			cmodel.InstallationStateDBRestorationFailed: nil,
+		}
+
+		unstable, err := getUnstableInstallationIDs(cmodel.NewClient(server.URL), "group1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"inst-a", "inst-b", "inst-c"}, unstable)
+	})
+
+	for _, tc := range []struct {
+		name            string
+		threshold       int
+		status          cmodel.GroupStatus
+		expectedState   string
+		expectedMessage string
+	}{
+		{
+			name:          "updated",
+			status:        cmodel.GroupStatus{InstallationsTotal: 4, InstallationsUpdated: 4},
+			expectedState: model.BackendReleaseComplete,
+		},
+		{
+			name:            "above the threshold",
+			threshold:       2,
+			status:          cmodel.GroupStatus{InstallationsTotal: 4, InstallationsUpdated: 1, InstallationsUpdating: 3},
+			expectedState:   model.BackendReleaseFailed,
+			expectedMessage: "3 installations of provisioner group group1 are unstable, above the threshold of 2: inst-a, inst-b, inst-c",
+		},
+		{
+			name:            "at the threshold",
+			threshold:       3,
+			status:          cmodel.GroupStatus{InstallationsTotal: 4, InstallationsUpdated: 1, InstallationsUpdating: 3},
+			expectedState:   model.BackendReleaseComplete,
+			expectedMessage: "Provisioner group group1 release completed with 3 unstable installations within the threshold of 3: inst-a, inst-b, inst-c",
+		},
+		{
+			name:          "within the threshold while installations are updating",
+			threshold:     3,
+			status:        cmodel.GroupStatus{InstallationsTotal: 5, InstallationsUpdated: 1, InstallationsUpdating: 4},
+			expectedState: model.BackendReleaseInProgress,
+		},
+		{
+			name:          "within the threshold while installations await their update",
+			threshold:     3,
+			status:        cmodel.GroupStatus{InstallationsTotal: 5, InstallationsUpdating: 3, InstallationsAwaitingUpdate: 2},
+			expectedState: model.BackendReleaseInProgress,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			installations = map[string][]string{
+				cmodel.InstallationStateUpdateFailed:      {"inst-c", "inst-a"},
+				cmodel.InstallationStateDBMigrationFailed: {"inst-b"},
+			}
+			*status = tc.status
+
+			backend := &cloudBackend{client: cmodel.NewClient(server.URL), logger: logger, unstableThreshold: tc.threshold}
+			backendStatus, err := backend.GetStatus(installationGroup)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedState, backendStatus.State)
+			require.Equal(t, tc.expectedMessage, backendStatus.Message)
+		})
+	}
+}
+
+func TestCloudBackendApplyRelease(t *testing.T) {
+	var patches []*cmodel.PatchGroupRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/group/group1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			patch := &cmodel.PatchGroupRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(patch))
+			patches = append(patches, patch)
+		}
+		_ = json.NewEncoder(w).Encode(&cmodel.GroupDTO{Group: &cmodel.Group{ID: "group1", Image: "image", Version: "v1"}})
+	})
+	mux.HandleFunc("/api/group/group2", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	backend := &cloudBackend{client: cmodel.NewClient(server.URL), logger: logger}
+	release := &model.RingRelease{ID: "release1", Image: "image", Version: "v2"}
+
+	t.Run("release", func(t *testing.T) {
+		applied, err := backend.ApplyRelease(&model.InstallationGroup{ID: "ig1", ProvisionerGroupID: "group1"}, release)
+		require.NoError(t, err)
+		require.True(t, applied)
+		require.Len(t, patches, 1)
+		require.Equal(t, "v2", *patches[0].Version)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		applied, err := backend.ApplyRelease(&model.InstallationGroup{ID: "ig1", ProvisionerGroupID: "group1"}, &model.RingRelease{Image: "image", Version: "v1"})
+		require.NoError(t, err)
+		require.False(t, applied)
+		require.Len(t, patches, 1)
+	})
+
+	t.Run("missing group", func(t *testing.T) {
+		applied, err := backend.ApplyRelease(&model.InstallationGroup{ID: "ig1", ProvisionerGroupID: "missing"}, release)
+		require.EqualError(t, err, "group missing does not exist")
+		require.False(t, applied)
+	})
+
+	t.Run("provisioner error", func(t *testing.T) {
+		_, err := backend.ApplyRelease(&model.InstallationGroup{ID: "ig1", ProvisionerGroupID: "group2"}, release)
+		require.EqualError(t, err, "failed to get group group2: failed with status code 500")
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package elrond
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// httpBackend deploys releases by posting them to the release URL of a provisioner target,
+// and follows their progress by polling its status URL.
+type httpBackend struct {
+	target ProvisionerTarget
+	client *http.Client
+	logger log.FieldLogger
+}
+
+// ApplyRelease posts the release to the release URL. The service is in charge of skipping
+// releases its group already runs.
+func (b *httpBackend) ApplyRelease(installationGroup *model.InstallationGroup, release *model.RingRelease) (bool, error) {
+	logger := b.logger.WithField("installationgroup", installationGroup.ID)
+	logger.Infof("Posting release %s:%s of provisioner group %s to provisioner target %s", release.Image, release.Version, installationGroup.ProvisionerGroupID, b.target.Name)
+	if err := b.post(b.target.ReleaseURL, installationGroup, model.NewBackendRelease(installationGroup, release)); err != nil {
+		return false, errors.Wrap(err, "failed to post release")
+	}
+
+	return true, nil
+}
+
+// GetStatus gets the status of the last release from the status URL, which must report the
+// ID of the release the status is about.
+func (b *httpBackend) GetStatus(installationGroup *model.InstallationGroup) (*model.BackendStatus, error) {
+	request, err := b.newRequest(http.MethodGet, b.target.StatusURL, installationGroup, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.Do(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get release status")
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get release status: unexpected status code %d", resp.StatusCode)
+	}
+
+	status, err := model.BackendStatusFromReader(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode release status")
+	}
+	if status.ReleaseID == "" {
+		return nil, errors.New("release status has no release ID")
+	}
+
+	return status, nil
+}
+
+// Rollback posts the release flagged as a rollback to the rollback URL, or to the release URL
+// when the target has no rollback URL.
+func (b *httpBackend) Rollback(installationGroup *model.InstallationGroup, release *model.RingRelease) error {
+	rollbackURL := b.target.RollbackURL
+	if rollbackURL == "" {
+		rollbackURL = b.target.ReleaseURL
+	}
+
+	backendRelease := model.NewBackendRelease(installationGroup, release)
+	backendRelease.Rollback = true
+
+	logger := b.logger.WithField("installationgroup", installationGroup.ID)
+	logger.Infof("Posting rollback to %s:%s of provisioner group %s to provisioner target %s", release.Image, release.Version, installationGroup.ProvisionerGroupID, b.target.Name)
+	if err := b.post(rollbackURL, installationGroup, backendRelease); err != nil {
+		return errors.Wrap(err, "failed to post rollback")
+	}
+
+	return nil
+}
+
+func (b *httpBackend) post(rawURL string, installationGroup *model.InstallationGroup, backendRelease *model.BackendRelease) error {
+	body, err := json.Marshal(backendRelease)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode release")
+	}
+
+	request, err := b.newRequest(http.MethodPost, rawURL, installationGroup, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// newRequest creates a request to the URL, replacing its {provisionerGroupID} placeholder
+// with the provisioner group of the installation group.
+func (b *httpBackend) newRequest(method, rawURL string, installationGroup *model.InstallationGroup, body io.Reader) (*http.Request, error) {
+	rawURL = strings.ReplaceAll(rawURL, "{provisionerGroupID}", url.PathEscape(installationGroup.ProvisionerGroupID))
+	request, err := http.NewRequest(method, rawURL, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	if b.target.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+b.target.Token)
+	}
+
+	return request, nil
+}
+
+func closeBody(r *http.Response) {
+	if r.Body != nil {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_ = r.Body.Close()
+	}
+}
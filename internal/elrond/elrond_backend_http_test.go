@@ -0,0 +1,124 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package elrond
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPBackend(t *testing.T) {
+	var releases []*model.BackendRelease
+	// statuses are served in order, the last one repeatedly.
+	statuses := []string{`{"releaseID":"release1","state":"in-progress","message":"3 of 4 updated"}`}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/groups/group1/release", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		release, err := model.BackendReleaseFromReader(r.Body)
+		require.NoError(t, err)
+		releases = append(releases, release)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/groups/group1/status", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		_, _ = w.Write([]byte(statuses[0]))
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provisioner := &ElProvisioner{params: ProvisioningParams{ProvisionerTargets: []ProvisionerTarget{{
+		Name:       "service",
+		Driver:     BackendDriverHTTP,
+		ReleaseURL: server.URL + "/groups/{provisionerGroupID}/release",
+		StatusURL:  server.URL + "/groups/{provisionerGroupID}/status",
+		Token:      "secret",
+	}}}, logger: logger}
+	backend, err := provisioner.NewBackend("service")
+	require.NoError(t, err)
+
+	installationGroup := &model.InstallationGroup{ID: "ig1", Name: "ig", ProvisionerGroupID: "group1", ProvisionerTarget: "service"}
+	release := &model.RingRelease{ID: "release1", Image: "image", Version: "v2"}
+
+	t.Run("apply release", func(t *testing.T) {
+		applied, err := backend.ApplyRelease(installationGroup, release)
+		require.NoError(t, err)
+		require.True(t, applied)
+		require.Len(t, releases, 1)
+		require.Equal(t, &model.BackendRelease{
+			InstallationGroupID:   "ig1",
+			InstallationGroupName: "ig",
+			ProvisionerGroupID:    "group1",
+			ReleaseID:             "release1",
+			Image:                 "image",
+			Version:               "v2",
+		}, releases[0])
+	})
+
+	t.Run("get status", func(t *testing.T) {
+		status, err := backend.GetStatus(installationGroup)
+		require.NoError(t, err)
+		require.Equal(t, &model.BackendStatus{ReleaseID: "release1", State: model.BackendReleaseInProgress, Message: "3 of 4 updated"}, status)
+
+		statuses = []string{`{"releaseID":"release1","state":"unknown"}`}
+		_, err = backend.GetStatus(installationGroup)
+		require.Error(t, err)
+
+		statuses = []string{`{"state":"complete"}`}
+		_, err = backend.GetStatus(installationGroup)
+		require.EqualError(t, err, "release status has no release ID")
+	})
+
+	t.Run("rollback falls back to the release URL", func(t *testing.T) {
+		err := backend.Rollback(installationGroup, release)
+		require.NoError(t, err)
+		require.Len(t, releases, 2)
+		require.True(t, releases[1].Rollback)
+	})
+
+	t.Run("wait for failed release", func(t *testing.T) {
+		statuses = []string{`{"releaseID":"release1","state":"failed","message":"3 of 4 updated"}`}
+		err := waitForBackendRelease(backend, installationGroup, release, 10, time.Millisecond, logger)
+		require.EqualError(t, err, "3 of 4 updated")
+	})
+
+	t.Run("wait ignores the status of the previous release", func(t *testing.T) {
+		statuses = []string{
+			`{"releaseID":"release0","state":"complete"}`,
+			`{"releaseID":"release0","state":"failed"}`,
+			`{"releaseID":"release1","state":"in-progress"}`,
+			`{"releaseID":"release1","state":"failed","message":"rollout failed"}`,
+		}
+		err := waitForBackendRelease(backend, installationGroup, release, 10, time.Millisecond, logger)
+		require.EqualError(t, err, "rollout failed")
+		require.Len(t, statuses, 1)
+
+		statuses = []string{
+			`{"releaseID":"release0","state":"complete"}`,
+			`{"releaseID":"release1","state":"complete"}`,
+		}
+		err = waitForBackendRelease(backend, installationGroup, release, 10, time.Millisecond, logger)
+		require.NoError(t, err)
+	})
+
+	t.Run("release error", func(t *testing.T) {
+		_, err := backend.ApplyRelease(&model.InstallationGroup{ProvisionerGroupID: "missing"}, release)
+		require.EqualError(t, err, "failed to post release: unexpected status code 404")
+	})
+
+	t.Run("installation group release is not reported", func(t *testing.T) {
+		deployed, err := provisioner.GetInstallationGroupRelease(installationGroup)
+		require.NoError(t, err)
+		require.Nil(t, deployed)
+	})
+}
@@ -5,16 +5,107 @@
 package elrond
 
 import (
+	"net/url"
+	"os"
+	"time"
+
 	cmodel "github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
 )
 
 // ProvisioningParams represent configuration used during various provisioning operations.
 type ProvisioningParams struct {
 	ProvisionerGroupReleaseTimeout int
-	GrafanaURL                     string
-	GrafanaTokens                  []string
-	ThanosURL                      string
+	// ProvisionerGroupUnstableThreshold is the number of installations a group release
+	// tolerates in an unstable state before failing.
+	ProvisionerGroupUnstableThreshold int
+	// ProvisionerGroupPollInterval is how often the progress of a group release is checked,
+	// every minute when zero.
+	ProvisionerGroupPollInterval time.Duration
+	GrafanaURL                   string
+	GrafanaTokens                []string
+	ThanosURL                    string
+	// ProvisionerTargets are the named provisioners installation groups can reference, in
+	// addition to the default provisioner.
+	ProvisionerTargets []ProvisionerTarget
+}
+
+// ProvisionerTarget is a named provisioner server, with the OAuth client credentials used to
+// authenticate against it.
+type ProvisionerTarget struct {
+	Name string `json:"name"`
+	// Driver is the backend deploying releases to the target, the Mattermost Cloud
+	// provisioner when empty.
+	Driver        string `json:"driver,omitempty"`
+	Server        string `json:"server,omitempty"`
+	ClientID      string `json:"clientID,omitempty"`
+	ClientSecret  string `json:"clientSecret,omitempty"`
+	TokenEndpoint string `json:"tokenEndpoint,omitempty"`
+	// ReleaseURL, StatusURL and RollbackURL are the endpoints of the http driver, in which
+	// {provisionerGroupID} is replaced with the provisioner group being released.
+	ReleaseURL  string `json:"releaseURL,omitempty"`
+	StatusURL   string `json:"statusURL,omitempty"`
+	RollbackURL string `json:"rollbackURL,omitempty"`
+	// Token is sent as a bearer token to the endpoints of the http driver.
+	Token string `json:"token,omitempty"`
+}
+
+// LoadProvisionerTargets reads the provisioner targets from the given YAML or JSON file.
+func LoadProvisionerTargets(file string) ([]ProvisionerTarget, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read provisioner targets file %s", file)
+	}
+
+	var config struct {
+		Targets []ProvisionerTarget `json:"targets"`
+	}
+	if err = yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse provisioner targets file %s", file)
+	}
+
+	names := make(map[string]bool)
+	for _, target := range config.Targets {
+		if target.Name == "" {
+			return nil, errors.New("provisioner target name cannot be empty")
+		}
+		if names[target.Name] {
+			return nil, errors.Errorf("provisioner target %s is defined more than once", target.Name)
+		}
+		names[target.Name] = true
+		if err = target.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid provisioner target %s", target.Name)
+		}
+	}
+
+	return config.Targets, nil
+}
+
+func (target ProvisionerTarget) validate() error {
+	switch target.Driver {
+	case "", BackendDriverCloud:
+		if _, err := url.ParseRequestURI(target.Server); err != nil {
+			return errors.Wrap(err, "invalid server")
+		}
+	case BackendDriverHTTP:
+		if _, err := url.ParseRequestURI(target.ReleaseURL); err != nil {
+			return errors.Wrap(err, "invalid release URL")
+		}
+		if _, err := url.ParseRequestURI(target.StatusURL); err != nil {
+			return errors.Wrap(err, "invalid status URL")
+		}
+		if target.RollbackURL != "" {
+			if _, err := url.ParseRequestURI(target.RollbackURL); err != nil {
+				return errors.Wrap(err, "invalid rollback URL")
+			}
+		}
+	default:
+		return errors.Errorf("unknown driver %q", target.Driver)
+	}
+
+	return nil
 }
 
 // ElProvisioner provisions release rings.
@@ -27,13 +118,47 @@ type ElProvisioner struct {
 	ProvisionerTokenEndpoint string
 }
 
-// NewProvisionerClient creates a new provisioner client with OAuth if credentials are configured.
-func (elp *ElProvisioner) NewProvisionerClient() *cmodel.Client {
-	if elp.ProvisionerClientID == "" || elp.ProvisionerClientSecret == "" || elp.ProvisionerTokenEndpoint == "" {
-		return cmodel.NewClient(elp.ProvisionerServer)
+// NewProvisionerClient creates a new client of the named provisioner target, or of the default
+// provisioner when the target is empty, with OAuth if credentials are configured.
+func (elp *ElProvisioner) NewProvisionerClient(target string) (*cmodel.Client, error) {
+	server, clientID, clientSecret, tokenEndpoint := elp.ProvisionerServer, elp.ProvisionerClientID, elp.ProvisionerClientSecret, elp.ProvisionerTokenEndpoint
+	if target != "" {
+		provisionerTarget, ok := elp.getProvisionerTarget(target)
+		if !ok {
+			return nil, errors.Errorf("provisioner target %s is not configured", target)
+		}
+		if provisionerTarget.Driver == BackendDriverHTTP {
+			return nil, errors.Errorf("provisioner target %s is not a Mattermost Cloud provisioner", target)
+		}
+		server, clientID, clientSecret, tokenEndpoint = provisionerTarget.Server, provisionerTarget.ClientID, provisionerTarget.ClientSecret, provisionerTarget.TokenEndpoint
+	}
+
+	if clientID == "" || clientSecret == "" || tokenEndpoint == "" {
+		return cmodel.NewClient(server), nil
+	}
+
+	return cmodel.NewClientWithOAuth(server, nil, clientID, clientSecret, tokenEndpoint), nil
+}
+
+// ProvisionerTargetExists returns whether the named provisioner target is configured. The
+// empty target is the default provisioner, which always exists.
+func (elp *ElProvisioner) ProvisionerTargetExists(target string) bool {
+	if target == "" {
+		return true
+	}
+	_, ok := elp.getProvisionerTarget(target)
+
+	return ok
+}
+
+func (elp *ElProvisioner) getProvisionerTarget(target string) (ProvisionerTarget, bool) {
+	for _, provisionerTarget := range elp.params.ProvisionerTargets {
+		if provisionerTarget.Name == target {
+			return provisionerTarget, true
+		}
 	}
 
-	return cmodel.NewClientWithOAuth(elp.ProvisionerServer, nil, elp.ProvisionerClientID, elp.ProvisionerClientSecret, elp.ProvisionerTokenEndpoint)
+	return ProvisionerTarget{}, false
 }
 
 // NewElrondProvisioner creates a new ElrondProvisioner.
@@ -5,7 +5,7 @@
 package elrond
 
 import (
-	"time"
+	"sort"
 
 	"github.com/mattermost/elrond/model"
 	cmodel "github.com/mattermost/mattermost-cloud/model"
@@ -17,72 +17,161 @@ func (provisioner *ElProvisioner) ReleaseInstallationGroup(installationGroup *mo
 	logger := provisioner.logger.WithField("installationgroup", installationGroup.ID)
 	logger.Infof("Releasing installation group %s", installationGroup.ID)
 
-	client := provisioner.NewProvisionerClient()
+	backend, err := provisioner.NewBackend(installationGroup.ProvisionerTarget)
+	if err != nil {
+		return err
+	}
+
+	applied, err := backend.ApplyRelease(installationGroup, release)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return nil
+	}
+
+	logger.Infof("Update provisioner group %s successful. Waiting up to %d seconds for the group release to complete...", installationGroup.ProvisionerGroupID, provisioner.params.ProvisionerGroupReleaseTimeout)
+	return waitForBackendRelease(backend, installationGroup, release, provisioner.params.ProvisionerGroupReleaseTimeout, provisioner.params.ProvisionerGroupPollInterval, logger)
+}
+
+// RollBackInstallationGroup rolls the provisioner group of an installation group back to the
+// given release.
+func (provisioner *ElProvisioner) RollBackInstallationGroup(installationGroup *model.InstallationGroup, release *model.RingRelease) error {
+	logger := provisioner.logger.WithField("installationgroup", installationGroup.ID)
+	logger.Infof("Rolling back installation group %s to %s:%s", installationGroup.ID, release.Image, release.Version)
+
+	backend, err := provisioner.NewBackend(installationGroup.ProvisionerTarget)
+	if err != nil {
+		return err
+	}
+
+	if err = backend.Rollback(installationGroup, release); err != nil {
+		return err
+	}
+
+	logger.Infof("Waiting up to %d seconds for the group rollback to complete...", provisioner.params.ProvisionerGroupReleaseTimeout)
+	return waitForBackendRelease(backend, installationGroup, release, provisioner.params.ProvisionerGroupReleaseTimeout, provisioner.params.ProvisionerGroupPollInterval, logger)
+}
+
+// GetInstallationGroupRelease returns the image, version and environment variables the
+// provisioner group of an installation group runs. Targets using the http driver do not
+// report what their groups run, so nil is returned for them.
+func (provisioner *ElProvisioner) GetInstallationGroupRelease(installationGroup *model.InstallationGroup) (*model.RingRelease, error) {
+	if provisionerTarget, ok := provisioner.getProvisionerTarget(installationGroup.ProvisionerTarget); ok && provisionerTarget.Driver == BackendDriverHTTP {
+		return nil, nil
+	}
 
-	logger.Info("Getting provisioner installation groups")
+	client, err := provisioner.NewProvisionerClient(installationGroup.ProvisionerTarget)
+	if err != nil {
+		return nil, err
+	}
 
 	group, err := client.GetGroup(installationGroup.ProvisionerGroupID)
-	if group == nil || err != nil {
-		return errors.Wrapf(err, "failed to get group %s, make sure it exists", installationGroup.ProvisionerGroupID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get group %s", installationGroup.ProvisionerGroupID)
+	}
+	if group == nil || group.Group == nil {
+		return nil, errors.Errorf("group %s does not exist", installationGroup.ProvisionerGroupID)
 	}
 
-	newEnvVars, err := release.EnvVariables.ToJSON()
+	return &model.RingRelease{
+		Image:        group.Image,
+		Version:      group.Version,
+		EnvVariables: group.MattermostEnv,
+	}, nil
+}
+
+// ProvisionerGroupExists returns whether the provisioner target has a group with the given ID.
+// The groups of targets using the http driver are owned by their service and cannot be
+// checked, so they are assumed to exist.
+func (provisioner *ElProvisioner) ProvisionerGroupExists(target, provisionerGroupID string) (bool, error) {
+	if provisionerTarget, ok := provisioner.getProvisionerTarget(target); ok && provisionerTarget.Driver == BackendDriverHTTP {
+		return true, nil
+	}
+
+	client, err := provisioner.NewProvisionerClient(target)
 	if err != nil {
-		return errors.Wrap(err, "failed to create newEnvVars JSON")
+		return false, err
 	}
 
-	if string(newEnvVars) == "{}" {
-		release.EnvVariables = group.MattermostEnv
+	group, err := client.GetGroup(provisionerGroupID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get group %s", provisionerGroupID)
 	}
 
-	if group.Image != release.Image || group.Version != release.Version || checkChangeGroupEnvVariables(group.MattermostEnv, release.EnvVariables) {
-		logger.Infof("Image or group env variable changes were detected. Current provisioner group image is %s:%s and new image is %s:%s", group.Image, group.Version, release.Image, release.Version)
-		request := &cmodel.PatchGroupRequest{
-			ID:            installationGroup.ProvisionerGroupID,
-			Version:       &release.Version,
-			Image:         &release.Image,
-			MattermostEnv: release.EnvVariables,
-		}
+	return group != nil && group.Group != nil, nil
+}
 
-		logger.Infof("Updating provisioner group %s", installationGroup.ProvisionerGroupID)
-		_, err := client.UpdateGroup(request)
+// GetProvisionerGroups returns all the groups of the provisioner target that are not deleted.
+func (provisioner *ElProvisioner) GetProvisionerGroups(target string) ([]*model.ProvisionerGroup, error) {
+	client, err := provisioner.NewProvisionerClient(target)
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			return errors.Wrap(err, "failed to patch provisioner group")
-		}
+	groups, err := client.GetGroups(&cmodel.GetGroupsRequest{
+		Paging: cmodel.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get groups")
+	}
 
-		logger.Infof("Update provisioner group %s successful. Waiting up to %d seconds for the group release to complete...", installationGroup.ProvisionerGroupID, provisioner.params.ProvisionerGroupReleaseTimeout)
-		err = waitForGroupRelease(client, provisioner.params.ProvisionerGroupReleaseTimeout, installationGroup.ProvisionerGroupID)
-		if err != nil {
-			return err
+	var provisionerGroups []*model.ProvisionerGroup
+	for _, group := range groups {
+		if group == nil || group.Group == nil {
+			continue
+		}
+		provisionerGroup := &model.ProvisionerGroup{
+			ID:                group.ID,
+			Name:              group.Name,
+			Image:             group.Image,
+			Version:           group.Version,
+			ProvisionerTarget: target,
+		}
+		for _, annotation := range group.Annotations {
+			if annotation != nil {
+				provisionerGroup.Annotations = append(provisionerGroup.Annotations, annotation.Name)
+			}
 		}
-	} else {
-		logger.Infof("Provisioner group image and version are already up to date with image %s:%s", group.Image, group.Version)
+		provisionerGroups = append(provisionerGroups, provisionerGroup)
 	}
 
-	return nil
+	return provisionerGroups, nil
 }
 
-func waitForGroupRelease(client *cmodel.Client, timeout int, groupID string) error {
-	timer := time.NewTimer(time.Duration(timeout) * time.Second)
-	defer timer.Stop()
-
-	for {
-		select {
-		case <-timer.C:
-			return errors.New("timed out waiting for group release to complete")
-		default:
-			status, err := client.GetGroupStatus(groupID)
-			if err != nil {
-				return errors.Wrap(err, "failed to get provisioner group status")
-			}
-			if status.InstallationsAwaitingUpdate == 0 && status.InstallationsUpdating == 0 {
-				return nil
+// unstableInstallationStates are the installation states the provisioner does not recover
+// from on its own, which the provisioner still counts as updating.
+var unstableInstallationStates = []string{
+	cmodel.InstallationStateCreationFailed,
+	cmodel.InstallationStateCreationNoCompatibleClusters,
+	cmodel.InstallationStateUpdateFailed,
+	cmodel.InstallationStateDeletionFailed,
+	cmodel.InstallationStateDBRestorationFailed,
+	cmodel.InstallationStateDBMigrationFailed,
+}
+
+// getUnstableInstallationIDs returns the sorted IDs of the installations of the group in
+// an unstable state.
+func getUnstableInstallationIDs(client *cmodel.Client, groupID string) ([]string, error) {
+	var unstable []string
+	for _, state := range unstableInstallationStates {
+		installations, err := client.GetInstallations(&cmodel.GetInstallationsRequest{
+			Paging:  cmodel.AllPagesNotDeleted(),
+			GroupID: groupID,
+			State:   state,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get %s installations of provisioner group %s", state, groupID)
+		}
+		for _, installation := range installations {
+			if installation != nil && installation.Installation != nil {
+				unstable = append(unstable, installation.ID)
 			}
-			logger.Infof("Provisioner group %s release in progress...", groupID)
-			time.Sleep(60 * time.Second)
 		}
 	}
+	sort.Strings(unstable)
+
+	return unstable, nil
 }
 
 // SoakInstallationGroup soaks an installation group
@@ -0,0 +1,317 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+// Package fakeprovisioner serves the subset of the Mattermost Cloud provisioner API used by
+// Elrond from memory, for local development and end-to-end tests.
+package fakeprovisioner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	cmodel "github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Group is a provisioner group of the fake provisioner, with the failures to inject into its
+// releases.
+type Group struct {
+	ID            string
+	Name          string
+	Image         string
+	Version       string
+	Annotations   []string
+	Installations int
+	// UnstableInstallations is the number of installations each release of the group leaves
+	// in the update-failed state.
+	UnstableInstallations int
+	// FailUpdates makes the provisioner refuse to patch the group.
+	FailUpdates bool
+}
+
+// Validate validates the group.
+func (g *Group) Validate() error {
+	if g.ID == "" {
+		return errors.New("group ID cannot be empty")
+	}
+	if g.Installations < 0 {
+		return errors.Errorf("installations of group %s cannot be negative", g.ID)
+	}
+	if g.UnstableInstallations < 0 || g.UnstableInstallations > g.Installations {
+		return errors.Errorf("unstable installations of group %s must be between 0 and its %d installations", g.ID, g.Installations)
+	}
+
+	return nil
+}
+
+type group struct {
+	config *Group
+	group  *cmodel.Group
+	// releasedAt is when the group was last patched, zero if it never was.
+	releasedAt time.Time
+	updates    int
+}
+
+// Provisioner is an in-memory provisioner. Patched groups take the release delay to update
+// their installations.
+type Provisioner struct {
+	mu           sync.Mutex
+	groups       map[string]*group
+	releaseDelay time.Duration
+	router       *mux.Router
+	logger       log.FieldLogger
+}
+
+// New creates a new fake provisioner.
+func New(releaseDelay time.Duration, logger log.FieldLogger) *Provisioner {
+	provisioner := &Provisioner{
+		groups:       make(map[string]*group),
+		releaseDelay: releaseDelay,
+		router:       mux.NewRouter(),
+		logger:       logger.WithField("provisioner", "fake"),
+	}
+
+	provisioner.router.HandleFunc("/api/groups", provisioner.handleGetGroups).Methods("GET")
+	provisioner.router.HandleFunc("/api/group/{group}", provisioner.handleGetGroup).Methods("GET")
+	provisioner.router.HandleFunc("/api/group/{group}", provisioner.handleUpdateGroup).Methods("PUT")
+	provisioner.router.HandleFunc("/api/group/{group}/status", provisioner.handleGetGroupStatus).Methods("GET")
+	provisioner.router.HandleFunc("/api/installations", provisioner.handleGetInstallations).Methods("GET")
+
+	return provisioner
+}
+
+// AddGroup adds the group to the provisioner, replacing any group with the same ID.
+func (p *Provisioner) AddGroup(config Group) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.groups[config.ID] = &group{
+		config: &config,
+		group: &cmodel.Group{
+			ID:            config.ID,
+			Name:          config.Name,
+			Image:         config.Image,
+			Version:       config.Version,
+			MattermostEnv: cmodel.EnvVarMap{},
+			CreateAt:      time.Now().UnixNano(),
+		},
+	}
+
+	return nil
+}
+
+// GetGroup returns the group with the given ID, running its current image and version, or
+// nil if it does not exist.
+func (p *Provisioner) GetGroup(groupID string) *Group {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	group, ok := p.groups[groupID]
+	if !ok {
+		return nil
+	}
+
+	config := *group.config
+	config.Image = group.group.Image
+	config.Version = group.group.Version
+
+	return &config
+}
+
+// Updates returns the number of times the group with the given ID was patched.
+func (p *Provisioner) Updates(groupID string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	group, ok := p.groups[groupID]
+	if !ok {
+		return 0
+	}
+
+	return group.updates
+}
+
+// ServeHTTP serves the provisioner API.
+func (p *Provisioner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.router.ServeHTTP(w, r)
+}
+
+func (p *Provisioner) handleGetGroups(w http.ResponseWriter, _ *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	groups := []*cmodel.GroupDTO{}
+	for _, group := range p.groups {
+		groups = append(groups, group.toDTO())
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].ID < groups[j].ID
+	})
+
+	p.outputJSON(w, http.StatusOK, groups)
+}
+
+func (p *Provisioner) handleGetGroup(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	group, ok := p.groups[mux.Vars(r)["group"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	p.outputJSON(w, http.StatusOK, group.toDTO())
+}
+
+func (p *Provisioner) handleUpdateGroup(w http.ResponseWriter, r *http.Request) {
+	groupID := mux.Vars(r)["group"]
+
+	patchGroupRequest, err := cmodel.NewPatchGroupRequestFromReader(r.Body)
+	if err != nil {
+		p.logger.WithError(err).Warn("Failed to decode patch group request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	group, ok := p.groups[groupID]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if group.config.FailUpdates {
+		p.logger.Warnf("Failing update of group %s", groupID)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if patchGroupRequest.Apply(group.group) {
+		group.group.Sequence++
+	}
+	group.releasedAt = time.Now()
+	group.updates++
+	p.logger.Infof("Group %s updated to %s:%s", groupID, group.group.Image, group.group.Version)
+
+	p.outputJSON(w, http.StatusOK, group.toDTO())
+}
+
+func (p *Provisioner) handleGetGroupStatus(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	group, ok := p.groups[mux.Vars(r)["group"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	status := &cmodel.GroupStatus{InstallationsTotal: int64(group.config.Installations)}
+	for _, installation := range p.installations(group) {
+		switch installation.State {
+		case cmodel.InstallationStateStable:
+			status.InstallationsUpdated++
+		case cmodel.InstallationStateUpdateRequested:
+			status.InstallationsAwaitingUpdate++
+		default:
+			// The provisioner counts failed installations as updating.
+			status.InstallationsUpdating++
+		}
+	}
+
+	p.outputJSON(w, http.StatusOK, status)
+}
+
+func (p *Provisioner) handleGetInstallations(w http.ResponseWriter, r *http.Request) {
+	groupID := r.URL.Query().Get("group")
+	state := r.URL.Query().Get("state")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	groupIDs := make([]string, 0, len(p.groups))
+	for id := range p.groups {
+		if groupID == "" || id == groupID {
+			groupIDs = append(groupIDs, id)
+		}
+	}
+	sort.Strings(groupIDs)
+
+	installations := []*cmodel.InstallationDTO{}
+	for _, id := range groupIDs {
+		for _, installation := range p.installations(p.groups[id]) {
+			if state == "" || installation.State == state {
+				installations = append(installations, installation)
+			}
+		}
+	}
+
+	p.outputJSON(w, http.StatusOK, installations)
+}
+
+// installations returns the installations of the group. Installations await their update
+// until the release delay elapsed since the group was patched, after which the unstable
+// installations of the group fail to update.
+func (p *Provisioner) installations(group *group) []*cmodel.InstallationDTO {
+	releasing := !group.releasedAt.IsZero() && time.Since(group.releasedAt) < p.releaseDelay
+
+	installations := make([]*cmodel.InstallationDTO, 0, group.config.Installations)
+	for i := 0; i < group.config.Installations; i++ {
+		state := cmodel.InstallationStateStable
+		if releasing {
+			state = cmodel.InstallationStateUpdateRequested
+		} else if !group.releasedAt.IsZero() && i < group.config.UnstableInstallations {
+			state = cmodel.InstallationStateUpdateFailed
+		}
+
+		groupID := group.group.ID
+		installations = append(installations, &cmodel.InstallationDTO{
+			Installation: &cmodel.Installation{
+				ID:      fmt.Sprintf("%s-installation-%d", groupID, i+1),
+				GroupID: &groupID,
+				Name:    fmt.Sprintf("%s-%d", group.group.Name, i+1),
+				Image:   group.group.Image,
+				Version: group.group.Version,
+				State:   state,
+			},
+		})
+	}
+
+	return installations
+}
+
+func (g *group) toDTO() *cmodel.GroupDTO {
+	groupCopy := *g.group
+	annotations := make([]*cmodel.Annotation, 0, len(g.config.Annotations))
+	for _, name := range g.config.Annotations {
+		annotations = append(annotations, &cmodel.Annotation{ID: name, Name: name})
+	}
+	installationCount := int64(g.config.Installations)
+
+	return &cmodel.GroupDTO{
+		Group:             &groupCopy,
+		Annotations:       annotations,
+		InstallationCount: &installationCount,
+	}
+}
+
+func (p *Provisioner) outputJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		p.logger.WithError(err).Error("Failed to encode result")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package fakeprovisioner_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/elrond/internal/fakeprovisioner"
+	"github.com/mattermost/elrond/internal/testlib"
+	cmodel "github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvisioner(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	provisioner := fakeprovisioner.New(100*time.Millisecond, logger)
+	server := httptest.NewServer(provisioner)
+	defer server.Close()
+	client := cmodel.NewClient(server.URL)
+
+	err := provisioner.AddGroup(fakeprovisioner.Group{ID: "group1", Name: "prod", Image: "image", Version: "v1", Installations: 3, UnstableInstallations: 1, Annotations: []string{"production"}})
+	require.NoError(t, err)
+	err = provisioner.AddGroup(fakeprovisioner.Group{ID: "group2", Name: "test", Image: "image", Version: "v1", Installations: 1, FailUpdates: true})
+	require.NoError(t, err)
+
+	t.Run("invalid group", func(t *testing.T) {
+		err := provisioner.AddGroup(fakeprovisioner.Group{ID: "group3", Installations: 1, UnstableInstallations: 2})
+		require.Error(t, err)
+	})
+
+	t.Run("get groups", func(t *testing.T) {
+		group, err := client.GetGroup("group1")
+		require.NoError(t, err)
+		require.Equal(t, "prod", group.Name)
+		require.Equal(t, "v1", group.Version)
+		require.Len(t, group.Annotations, 1)
+		require.Equal(t, "production", group.Annotations[0].Name)
+
+		group, err = client.GetGroup("unknown")
+		require.NoError(t, err)
+		require.Nil(t, group)
+
+		groups, err := client.GetGroups(&cmodel.GetGroupsRequest{Paging: cmodel.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+	})
+
+	t.Run("status before any release", func(t *testing.T) {
+		status, err := client.GetGroupStatus("group1")
+		require.NoError(t, err)
+		require.Equal(t, &cmodel.GroupStatus{InstallationsTotal: 3, InstallationsUpdated: 3}, status)
+	})
+
+	t.Run("release with unstable installations", func(t *testing.T) {
+		version := "v2"
+		group, err := client.UpdateGroup(&cmodel.PatchGroupRequest{ID: "group1", Version: &version})
+		require.NoError(t, err)
+		require.Equal(t, "v2", group.Version)
+		require.Equal(t, 1, provisioner.Updates("group1"))
+		require.Equal(t, "v2", provisioner.GetGroup("group1").Version)
+
+		status, err := client.GetGroupStatus("group1")
+		require.NoError(t, err)
+		require.Equal(t, &cmodel.GroupStatus{InstallationsTotal: 3, InstallationsAwaitingUpdate: 3}, status)
+
+		time.Sleep(100 * time.Millisecond)
+
+		status, err = client.GetGroupStatus("group1")
+		require.NoError(t, err)
+		require.Equal(t, &cmodel.GroupStatus{InstallationsTotal: 3, InstallationsUpdated: 2, InstallationsUpdating: 1}, status)
+
+		installations, err := client.GetInstallations(&cmodel.GetInstallationsRequest{
+			Paging:  cmodel.AllPagesNotDeleted(),
+			GroupID: "group1",
+			State:   cmodel.InstallationStateUpdateFailed,
+		})
+		require.NoError(t, err)
+		require.Len(t, installations, 1)
+		require.Equal(t, "group1-installation-1", installations[0].ID)
+	})
+
+	t.Run("failed update", func(t *testing.T) {
+		version := "v2"
+		_, err := client.UpdateGroup(&cmodel.PatchGroupRequest{ID: "group2", Version: &version})
+		require.Error(t, err)
+		require.Equal(t, "v1", provisioner.GetGroup("group2").Version)
+	})
+}
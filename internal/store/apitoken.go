@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+)
+
+var apiTokenSelect sq.SelectBuilder
+
+func init() {
+	apiTokenSelect = sq.
+		Select("ID", "Name", "Role", "TokenHash", "CreatedBy", "CreateAt", "DeleteAt").From("APIToken")
+}
+
+// GetAPIToken fetches the given API token by id.
+func (sqlStore *SQLStore) GetAPIToken(id string) (*model.APIToken, error) {
+	var apiToken model.APIToken
+	err := sqlStore.getBuilder(sqlStore.db, &apiToken,
+		apiTokenSelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get API token by id")
+	}
+
+	return &apiToken, nil
+}
+
+// GetAPITokenByHash fetches the non-deleted API token matching the given token hash.
+func (sqlStore *SQLStore) GetAPITokenByHash(tokenHash string) (*model.APIToken, error) {
+	var apiToken model.APIToken
+	err := sqlStore.getBuilder(sqlStore.db, &apiToken,
+		apiTokenSelect.
+			Where("TokenHash = ?", tokenHash).
+			Where("DeleteAt = 0"),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get API token by hash")
+	}
+
+	return &apiToken, nil
+}
+
+// GetAPITokens fetches the given page of created API tokens. The first page is 0.
+func (sqlStore *SQLStore) GetAPITokens(filter *model.APITokenFilter) ([]*model.APIToken, error) {
+	builder := apiTokenSelect.
+		OrderBy("CreateAt ASC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if !filter.IncludeDeleted {
+		builder = builder.Where("DeleteAt = 0")
+	}
+
+	var apiTokens []*model.APIToken
+	err := sqlStore.selectBuilder(sqlStore.db, &apiTokens, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for API tokens")
+	}
+
+	return apiTokens, nil
+}
+
+// CreateAPIToken records the given API token to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateAPIToken(apiToken *model.APIToken) error {
+	apiToken.ID = model.NewID()
+	apiToken.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("APIToken").
+		SetMap(map[string]interface{}{
+			"ID":        apiToken.ID,
+			"Name":      apiToken.Name,
+			"Role":      apiToken.Role,
+			"TokenHash": apiToken.TokenHash,
+			"CreatedBy": apiToken.CreatedBy,
+			"CreateAt":  apiToken.CreateAt,
+			"DeleteAt":  0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create API token")
+	}
+
+	return nil
+}
+
+// DeleteAPIToken marks the given API token as deleted, revoking it without removing
+// the record from the database.
+func (sqlStore *SQLStore) DeleteAPIToken(id string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update("APIToken").
+		Set("DeleteAt", GetMillis()).
+		Where("ID = ?", id).
+		Where("DeleteAt = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark API token as deleted")
+	}
+
+	return nil
+}
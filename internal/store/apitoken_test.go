@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokens(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	apiToken, err := sqlStore.GetAPIToken("unknown")
+	require.NoError(t, err)
+	require.Nil(t, apiToken)
+
+	token1 := &model.APIToken{
+		Name:      "ci",
+		Role:      model.RoleReleaser,
+		TokenHash: "hash1",
+		CreatedBy: "bootstrap",
+	}
+	token2 := &model.APIToken{
+		Name:      "dashboard",
+		Role:      model.RoleViewer,
+		TokenHash: "hash2",
+	}
+
+	err = sqlStore.CreateAPIToken(token1)
+	require.NoError(t, err)
+	time.Sleep(1 * time.Millisecond)
+	err = sqlStore.CreateAPIToken(token2)
+	require.NoError(t, err)
+
+	err = sqlStore.CreateAPIToken(&model.APIToken{Name: "duplicate", Role: model.RoleAdmin, TokenHash: "hash1"})
+	require.Error(t, err)
+
+	actualToken1, err := sqlStore.GetAPIToken(token1.ID)
+	require.NoError(t, err)
+	require.Equal(t, token1, actualToken1)
+
+	actualToken2, err := sqlStore.GetAPITokenByHash("hash2")
+	require.NoError(t, err)
+	require.Equal(t, token2, actualToken2)
+
+	tokens, err := sqlStore.GetAPITokens(&model.APITokenFilter{PerPage: model.AllPerPage})
+	require.NoError(t, err)
+	require.Equal(t, []*model.APIToken{token1, token2}, tokens)
+
+	err = sqlStore.DeleteAPIToken(token2.ID)
+	require.NoError(t, err)
+
+	actualToken2, err = sqlStore.GetAPITokenByHash("hash2")
+	require.NoError(t, err)
+	require.Nil(t, actualToken2)
+
+	tokens, err = sqlStore.GetAPITokens(&model.APITokenFilter{PerPage: model.AllPerPage})
+	require.NoError(t, err)
+	require.Equal(t, []*model.APIToken{token1}, tokens)
+
+	tokens, err = sqlStore.GetAPITokens(&model.APITokenFilter{PerPage: model.AllPerPage, IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	require.True(t, tokens[1].IsDeleted())
+}
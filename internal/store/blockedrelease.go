@@ -0,0 +1,107 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+)
+
+var blockedReleaseSelect sq.SelectBuilder
+
+func init() {
+	blockedReleaseSelect = sq.
+		Select("ID", "Image", "Version", "Reason", "CreatedBy", "CreateAt").
+		From("BlockedRelease")
+}
+
+// GetBlockedRelease fetches the blocked release of the given image and version, if any.
+func (sqlStore *SQLStore) GetBlockedRelease(image, version string) (*model.BlockedRelease, error) {
+	var blockedRelease model.BlockedRelease
+	err := sqlStore.getBuilder(sqlStore.db, &blockedRelease, blockedReleaseSelect.
+		Where("Image = ?", image).
+		Where("Version = ?", version),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get blocked release")
+	}
+
+	return &blockedRelease, nil
+}
+
+// GetBlockedReleases fetches all blocked releases.
+func (sqlStore *SQLStore) GetBlockedReleases() ([]*model.BlockedRelease, error) {
+	var blockedReleases []*model.BlockedRelease
+	err := sqlStore.selectBuilder(sqlStore.db, &blockedReleases, blockedReleaseSelect.
+		OrderBy("CreateAt ASC"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for blocked releases")
+	}
+
+	return blockedReleases, nil
+}
+
+// IsReleaseBlocked returns whether the image and version of the given ring release are
+// blocked.
+func (sqlStore *SQLStore) IsReleaseBlocked(releaseID string) (bool, error) {
+	release, err := sqlStore.GetRingRelease(releaseID)
+	if err != nil {
+		return false, err
+	}
+	if release == nil {
+		return false, nil
+	}
+
+	blockedRelease, err := sqlStore.GetBlockedRelease(release.Image, release.Version)
+	if err != nil {
+		return false, err
+	}
+
+	return blockedRelease != nil, nil
+}
+
+// CreateBlockedRelease records the given blocked release to the database, assigning it a
+// unique ID.
+func (sqlStore *SQLStore) CreateBlockedRelease(blockedRelease *model.BlockedRelease) error {
+	blockedRelease.ID = model.NewID()
+	blockedRelease.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("BlockedRelease").
+		SetMap(map[string]interface{}{
+			"ID":        blockedRelease.ID,
+			"Image":     blockedRelease.Image,
+			"Version":   blockedRelease.Version,
+			"Reason":    blockedRelease.Reason,
+			"CreatedBy": blockedRelease.CreatedBy,
+			"CreateAt":  blockedRelease.CreateAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create blocked release")
+	}
+
+	return nil
+}
+
+// DeleteBlockedRelease removes the given image and version from the blocked releases.
+func (sqlStore *SQLStore) DeleteBlockedRelease(image, version string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete("BlockedRelease").
+		Where("Image = ?", image).
+		Where("Version = ?", version),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete blocked release")
+	}
+
+	return nil
+}
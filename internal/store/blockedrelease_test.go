@@ -0,0 +1,86 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockedReleases(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	blockedRelease, err := sqlStore.GetBlockedRelease("image", "1.0.0")
+	require.NoError(t, err)
+	require.Nil(t, blockedRelease)
+
+	blockedReleases, err := sqlStore.GetBlockedReleases()
+	require.NoError(t, err)
+	require.Empty(t, blockedReleases)
+
+	blockedRelease1 := &model.BlockedRelease{
+		Image:     "image",
+		Version:   "1.0.0",
+		Reason:    "crashes on startup",
+		CreatedBy: "ci",
+	}
+	blockedRelease2 := &model.BlockedRelease{
+		Image:   "image",
+		Version: "1.0.1",
+	}
+
+	err = sqlStore.CreateBlockedRelease(blockedRelease1)
+	require.NoError(t, err)
+	time.Sleep(1 * time.Millisecond)
+	err = sqlStore.CreateBlockedRelease(blockedRelease2)
+	require.NoError(t, err)
+
+	err = sqlStore.CreateBlockedRelease(&model.BlockedRelease{Image: "image", Version: "1.0.0"})
+	require.Error(t, err)
+
+	actualBlockedRelease1, err := sqlStore.GetBlockedRelease("image", "1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, blockedRelease1, actualBlockedRelease1)
+
+	blockedReleases, err = sqlStore.GetBlockedReleases()
+	require.NoError(t, err)
+	require.Equal(t, []*model.BlockedRelease{blockedRelease1, blockedRelease2}, blockedReleases)
+
+	t.Run("is release blocked", func(t *testing.T) {
+		blockedRingRelease, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "1.0.0"})
+		require.NoError(t, err)
+		ringRelease, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "1.1.0"})
+		require.NoError(t, err)
+
+		blocked, err := sqlStore.IsReleaseBlocked(blockedRingRelease.ID)
+		require.NoError(t, err)
+		require.True(t, blocked)
+
+		blocked, err = sqlStore.IsReleaseBlocked(ringRelease.ID)
+		require.NoError(t, err)
+		require.False(t, blocked)
+
+		blocked, err = sqlStore.IsReleaseBlocked("unknown")
+		require.NoError(t, err)
+		require.False(t, blocked)
+	})
+
+	err = sqlStore.DeleteBlockedRelease("image", "1.0.0")
+	require.NoError(t, err)
+
+	blockedRelease, err = sqlStore.GetBlockedRelease("image", "1.0.0")
+	require.NoError(t, err)
+	require.Nil(t, blockedRelease)
+
+	blockedReleases, err = sqlStore.GetBlockedReleases()
+	require.NoError(t, err)
+	require.Equal(t, []*model.BlockedRelease{blockedRelease2}, blockedReleases)
+}
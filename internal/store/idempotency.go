@@ -0,0 +1,127 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+)
+
+var idempotencyRecordSelect sq.SelectBuilder
+
+func init() {
+	idempotencyRecordSelect = sq.
+		Select("Key", "Fingerprint", "StatusCode", "Body", "CreateAt").From("IdempotencyRecord")
+}
+
+// GetIdempotencyRecord fetches the idempotency record for the given key.
+func (sqlStore *SQLStore) GetIdempotencyRecord(key string) (*model.IdempotencyRecord, error) {
+	return sqlStore.getIdempotencyRecord(sqlStore.db, key)
+}
+
+func (sqlStore *SQLStore) getIdempotencyRecord(db queryer, key string) (*model.IdempotencyRecord, error) {
+	var record model.IdempotencyRecord
+	err := sqlStore.getBuilder(db, &record,
+		idempotencyRecordSelect.Where("Key = ?", key),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get idempotency record by key")
+	}
+
+	return &record, nil
+}
+
+// ReserveIdempotencyKey records the given in-progress idempotency record unless a
+// record with the same key already exists, in which case the existing record is
+// returned instead.
+func (sqlStore *SQLStore) ReserveIdempotencyKey(record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	existing, err := sqlStore.getIdempotencyRecord(sqlStore.db, record.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	record.StatusCode = 0
+	record.Body = nil
+	record.CreateAt = GetMillis()
+
+	_, err = sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("IdempotencyRecord").
+		SetMap(map[string]interface{}{
+			"Key":         record.Key,
+			"Fingerprint": record.Fingerprint,
+			"StatusCode":  record.StatusCode,
+			"Body":        record.Body,
+			"CreateAt":    record.CreateAt,
+		}),
+	)
+	if err != nil {
+		// The key is the primary key, so a concurrent request may have reserved it
+		// between the lookup and the insert.
+		existing, getErr := sqlStore.getIdempotencyRecord(sqlStore.db, record.Key)
+		if getErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, errors.Wrap(err, "failed to create idempotency record")
+	}
+
+	return nil, nil
+}
+
+// CompleteIdempotencyRecord stores the response of the request holding the given key.
+func (sqlStore *SQLStore) CompleteIdempotencyRecord(key string, statusCode int, body []byte) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update("IdempotencyRecord").
+		SetMap(map[string]interface{}{
+			"StatusCode": statusCode,
+			"Body":       body,
+		}).
+		Where("Key = ?", key),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to complete idempotency record")
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyRecord removes the record for the given key, allowing it to be reused.
+func (sqlStore *SQLStore) DeleteIdempotencyRecord(key string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete("IdempotencyRecord").
+		Where("Key = ?", key),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete idempotency record")
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyRecordsCreatedBefore removes all records created before the given
+// time in milliseconds, returning the number of records removed.
+func (sqlStore *SQLStore) DeleteIdempotencyRecordsCreatedBefore(createAt int64) (int64, error) {
+	result, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete("IdempotencyRecord").
+		Where("CreateAt < ?", createAt),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete expired idempotency records")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count deleted idempotency records")
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRecords(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	record, err := sqlStore.GetIdempotencyRecord("unknown")
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	existing, err := sqlStore.ReserveIdempotencyKey(&model.IdempotencyRecord{Key: "key1", Fingerprint: "fingerprint1"})
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	existing, err = sqlStore.ReserveIdempotencyKey(&model.IdempotencyRecord{Key: "key1", Fingerprint: "fingerprint2"})
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	require.Equal(t, "fingerprint1", existing.Fingerprint)
+	require.False(t, existing.IsComplete())
+
+	err = sqlStore.CompleteIdempotencyRecord("key1", 202, []byte(`{"ID":"ring"}`))
+	require.NoError(t, err)
+
+	record, err = sqlStore.GetIdempotencyRecord("key1")
+	require.NoError(t, err)
+	require.True(t, record.IsComplete())
+	require.Equal(t, 202, record.StatusCode)
+	require.Equal(t, []byte(`{"ID":"ring"}`), record.Body)
+
+	err = sqlStore.DeleteIdempotencyRecord("key1")
+	require.NoError(t, err)
+
+	record, err = sqlStore.GetIdempotencyRecord("key1")
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	_, err = sqlStore.ReserveIdempotencyKey(&model.IdempotencyRecord{Key: "key2", Fingerprint: "fingerprint"})
+	require.NoError(t, err)
+
+	deleted, err := sqlStore.DeleteIdempotencyRecordsCreatedBefore(0)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), deleted)
+
+	deleted, err = sqlStore.DeleteIdempotencyRecordsCreatedBefore(GetMillis() + 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+}
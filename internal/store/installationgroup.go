@@ -25,6 +25,15 @@ var installationGroupColumns = []string{
 	"InstallationGroup.SoakTime",
 	"InstallationGroup.ReleaseAt",
 	"InstallationGroup.ProvisionerGroupID",
+	"InstallationGroup.ProvisionerTarget",
+	"InstallationGroup.Priority",
+	"InstallationGroup.Paused",
+	"InstallationGroup.SkippedReleaseID",
+	"InstallationGroup.ActiveReleaseID",
+	"InstallationGroup.DesiredReleaseID",
+	"InstallationGroup.Drift",
+	"InstallationGroup.DriftCheckedAt",
+	"InstallationGroup.ReleaseFailure",
 	"InstallationGroup.LockAcquiredBy",
 	"InstallationGroup.LockAcquiredAt",
 }
@@ -37,6 +46,15 @@ type ringInstallationGroup struct {
 	InstallationGroupReleaseAt          int64
 	InstallationGroupSoakTime           int
 	InstallationGroupProvisionerGroupID string
+	InstallationGroupProvisionerTarget  string
+	InstallationGroupPriority           int
+	InstallationGroupPaused             bool
+	InstallationGroupSkippedReleaseID   string
+	InstallationGroupActiveReleaseID    string
+	InstallationGroupDesiredReleaseID   string
+	InstallationGroupDrift              string
+	InstallationGroupDriftCheckedAt     int64
+	InstallationGroupReleaseFailure     string
 }
 
 func init() {
@@ -49,7 +67,8 @@ func (sqlStore *SQLStore) GetInstallationGroupByName(name string) (*model.Instal
 	return sqlStore.getInstallationGroupByName(sqlStore.db, name)
 }
 
-// GetInstallationGroupByID fetches the given installation group by ID.
+// GetInstallationGroupByID fetches the given installation group by ID, including the ID of the
+// ring it is registered to.
 func (sqlStore *SQLStore) GetInstallationGroupByID(id string) (*model.InstallationGroup, error) {
 	return sqlStore.getInstallationGroupByID(sqlStore.db, id)
 }
@@ -85,9 +104,55 @@ func (sqlStore *SQLStore) getInstallationGroupByID(db queryer, id string) (*mode
 		return nil, errors.Wrap(err, "failed to get installation group by id")
 	}
 
+	var ringIDs []string
+	err = sqlStore.selectBuilder(db, &ringIDs, sq.Select("RingID").
+		From(ringInstallationGroupTable).
+		Where("InstallationGroupID = ?", id).
+		Limit(1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get ring of installation group")
+	}
+	if len(ringIDs) > 0 {
+		installationGroup.RingID = ringIDs[0]
+	}
+
 	return &installationGroup, nil
 }
 
+// GetInstallationGroups fetches the given page of installation groups, together with the ID
+// of the ring each one is registered to. The first page is 0.
+func (sqlStore *SQLStore) GetInstallationGroups(filter *model.InstallationGroupFilter) ([]*model.InstallationGroup, error) {
+	builder := sq.Select(installationGroupColumns...).
+		Column(fmt.Sprintf("COALESCE(%s.RingID, '') as RingID", ringInstallationGroupTable)).
+		From("InstallationGroup").
+		LeftJoin(fmt.Sprintf("%s ON %s.InstallationGroupID = InstallationGroup.ID", ringInstallationGroupTable, ringInstallationGroupTable)).
+		OrderBy("InstallationGroup.Name ASC", "InstallationGroup.ID ASC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if filter.RingID != "" {
+		builder = builder.Where(fmt.Sprintf("%s.RingID = ?", ringInstallationGroupTable), filter.RingID)
+	}
+	if filter.State != "" {
+		builder = builder.Where("InstallationGroup.State = ?", filter.State)
+	}
+	if filter.ProvisionerGroupID != "" {
+		builder = builder.Where("InstallationGroup.ProvisionerGroupID = ?", filter.ProvisionerGroupID)
+	}
+
+	var installationGroups []*model.InstallationGroup
+	err := sqlStore.selectBuilder(sqlStore.db, &installationGroups, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for installation groups")
+	}
+
+	return installationGroups, nil
+}
+
 // CreateInstallationGroup creates the given installation group to the database, assigning it a unique ID.
 func (sqlStore *SQLStore) CreateInstallationGroup(installationGroup *model.InstallationGroup) error {
 	return sqlStore.createInstallationGroup(sqlStore.db, installationGroup)
@@ -104,6 +169,15 @@ func (sqlStore *SQLStore) createInstallationGroup(db execer, installationGroup *
 			"ReleaseAt":          installationGroup.ReleaseAt,
 			"SoakTime":           installationGroup.SoakTime,
 			"ProvisionerGroupID": installationGroup.ProvisionerGroupID,
+			"ProvisionerTarget":  installationGroup.ProvisionerTarget,
+			"Priority":           installationGroup.Priority,
+			"Paused":             installationGroup.Paused,
+			"SkippedReleaseID":   installationGroup.SkippedReleaseID,
+			"ActiveReleaseID":    installationGroup.ActiveReleaseID,
+			"DesiredReleaseID":   installationGroup.DesiredReleaseID,
+			"Drift":              installationGroup.Drift,
+			"DriftCheckedAt":     installationGroup.DriftCheckedAt,
+			"ReleaseFailure":     installationGroup.ReleaseFailure,
 			"LockAcquiredBy":     nil,
 			"LockAcquiredAt":     0,
 		}))
@@ -253,7 +327,16 @@ func (sqlStore *SQLStore) GetInstallationGroupsForRings(filter *model.RingFilter
 		"InstallationGroup.State as InstallationGroupState",
 		"InstallationGroup.ReleaseAt as InstallationGroupReleaseAt",
 		"InstallationGroup.SoakTime as InstallationGroupSoakTime",
-		"InstallationGroup.ProvisionerGroupID as InstallationGroupProvisionerGroupID").
+		"InstallationGroup.ProvisionerGroupID as InstallationGroupProvisionerGroupID",
+		"InstallationGroup.ProvisionerTarget as InstallationGroupProvisionerTarget",
+		"InstallationGroup.Priority as InstallationGroupPriority",
+		"InstallationGroup.Paused as InstallationGroupPaused",
+		"InstallationGroup.SkippedReleaseID as InstallationGroupSkippedReleaseID",
+		"InstallationGroup.ActiveReleaseID as InstallationGroupActiveReleaseID",
+		"InstallationGroup.DesiredReleaseID as InstallationGroupDesiredReleaseID",
+		"InstallationGroup.Drift as InstallationGroupDrift",
+		"InstallationGroup.DriftCheckedAt as InstallationGroupDriftCheckedAt",
+		"InstallationGroup.ReleaseFailure as InstallationGroupReleaseFailure").
 		From("Ring").
 		LeftJoin(fmt.Sprintf("%s ON %s.RingID = Ring.ID", ringInstallationGroupTable, ringInstallationGroupTable)).
 		Join("InstallationGroup ON InstallationGroup.ID=InstallationGroupID")
@@ -275,6 +358,15 @@ func (sqlStore *SQLStore) GetInstallationGroupsForRings(filter *model.RingFilter
 				ReleaseAt:          rig.InstallationGroupReleaseAt,
 				SoakTime:           rig.InstallationGroupSoakTime,
 				ProvisionerGroupID: rig.InstallationGroupProvisionerGroupID,
+				ProvisionerTarget:  rig.InstallationGroupProvisionerTarget,
+				Priority:           rig.InstallationGroupPriority,
+				Paused:             rig.InstallationGroupPaused,
+				SkippedReleaseID:   rig.InstallationGroupSkippedReleaseID,
+				ActiveReleaseID:    rig.InstallationGroupActiveReleaseID,
+				DesiredReleaseID:   rig.InstallationGroupDesiredReleaseID,
+				Drift:              rig.InstallationGroupDrift,
+				DriftCheckedAt:     rig.InstallationGroupDriftCheckedAt,
+				ReleaseFailure:     rig.InstallationGroupReleaseFailure,
 			},
 		)
 	}
@@ -304,7 +396,32 @@ func (sqlStore *SQLStore) DeleteRingInstallationGroup(ringID string, installatio
 	return nil
 }
 
-// GetInstallationGroupsPendingWork returns all installation groups in a pending state.
+// MoveInstallationGroup registers the installation group to another ring, keeping its identity and
+// settings. It fails when the installation group is not registered to the source ring.
+func (sqlStore *SQLStore) MoveInstallationGroup(installationGroupID, fromRingID, toRingID string) error {
+	result, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(ringInstallationGroupTable).
+		Set("RingID", toRingID).
+		Where("RingID = ?", fromRingID).
+		Where("InstallationGroupID = ?", installationGroupID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to move installation group")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to count rows affected")
+	}
+	if count != 1 {
+		return errors.Errorf("installation group %s is not registered to ring %s", installationGroupID, fromRingID)
+	}
+
+	return nil
+}
+
+// GetInstallationGroupsPendingWork returns all installation groups in a pending state, lowest
+// priority first.
 func (sqlStore *SQLStore) GetInstallationGroupsPendingWork() ([]*model.InstallationGroup, error) {
 	var installationGroups []*model.InstallationGroup
 
@@ -312,7 +429,8 @@ func (sqlStore *SQLStore) GetInstallationGroupsPendingWork() ([]*model.Installat
 		Where(sq.Eq{
 			"State": model.AllInstallationGroupStatesPendingWork,
 		}).
-		Where("LockAcquiredAt = 0")
+		Where("LockAcquiredAt = 0").
+		OrderBy("Priority ASC", "Name ASC")
 
 	err := sqlStore.selectBuilder(sqlStore.db, &installationGroups, builder)
 	if err != nil {
@@ -385,6 +503,15 @@ func (sqlStore *SQLStore) UpdateInstallationGroup(installationGroup *model.Insta
 			"ReleaseAt":          installationGroup.ReleaseAt,
 			"SoakTime":           installationGroup.SoakTime,
 			"ProvisionerGroupID": installationGroup.ProvisionerGroupID,
+			"ProvisionerTarget":  installationGroup.ProvisionerTarget,
+			"Priority":           installationGroup.Priority,
+			"Paused":             installationGroup.Paused,
+			"SkippedReleaseID":   installationGroup.SkippedReleaseID,
+			"ActiveReleaseID":    installationGroup.ActiveReleaseID,
+			"DesiredReleaseID":   installationGroup.DesiredReleaseID,
+			"Drift":              installationGroup.Drift,
+			"DriftCheckedAt":     installationGroup.DriftCheckedAt,
+			"ReleaseFailure":     installationGroup.ReleaseFailure,
 		}).
 		Where("ID = ?", installationGroup.ID),
 	); err != nil {
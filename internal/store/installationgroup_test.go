@@ -40,7 +40,7 @@ func TestInstallationGroups_Ring(t *testing.T) {
 		assert.Nil(t, installationGroup)
 	})
 
-	ring1 := model.Ring{}
+	ring1 := model.Ring{Name: "ring1"}
 	createRingErr := sqlStore.createRing(sqlStore.db, &ring1)
 	require.NoError(t, createRingErr)
 
@@ -60,7 +60,7 @@ func TestInstallationGroups_Ring(t *testing.T) {
 		assert.Contains(t, strings.ToLower(createRingInstallationGroupErr.Error()), "unique constraint") // Make sure error comes from DB
 	})
 
-	ring2 := model.Ring{}
+	ring2 := model.Ring{Name: "ring2"}
 	createRingErr = sqlStore.CreateRing(&ring2, &installationGroup2)
 	require.NoError(t, createRingErr)
 
@@ -88,4 +88,102 @@ func TestInstallationGroups_Ring(t *testing.T) {
 		deleteErr := sqlStore.DeleteRingInstallationGroup(ring1.ID, "unknown-installation-group")
 		require.NoError(t, deleteErr)
 	})
+
+	t.Run("update installation group releases", func(t *testing.T) {
+		installationGroup2.ActiveReleaseID = "active-release"
+		installationGroup2.DesiredReleaseID = "desired-release"
+		updateErr := sqlStore.UpdateInstallationGroup(&installationGroup2)
+		require.NoError(t, updateErr)
+
+		installationGroupsForRing, getInstallationGroupsErr := sqlStore.GetInstallationGroupsForRing(ring2.ID)
+		require.NoError(t, getInstallationGroupsErr)
+		require.Len(t, installationGroupsForRing, 1)
+		assert.Equal(t, "active-release", installationGroupsForRing[0].ActiveReleaseID)
+		assert.Equal(t, "desired-release", installationGroupsForRing[0].DesiredReleaseID)
+	})
+}
+
+func TestGetInstallationGroups(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	ring1 := &model.Ring{Name: "ring1"}
+	err := sqlStore.CreateRing(ring1, &model.InstallationGroup{Name: "ig-1", State: model.InstallationGroupStable, ProvisionerGroupID: "pg1"})
+	require.NoError(t, err)
+	ring2 := &model.Ring{Name: "ring2"}
+	err = sqlStore.CreateRing(ring2, &model.InstallationGroup{Name: "ig-2", State: model.InstallationGroupReleaseRequested, ProvisionerGroupID: "pg2"})
+	require.NoError(t, err)
+	err = sqlStore.CreateInstallationGroup(&model.InstallationGroup{Name: "ig-3", State: model.InstallationGroupStable, ProvisionerGroupID: "pg3"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description string
+		filter      *model.InstallationGroupFilter
+		expected    []string
+	}{
+		{"all", &model.InstallationGroupFilter{PerPage: model.AllPerPage}, []string{"ig-1", "ig-2", "ig-3"}},
+		{"paged", &model.InstallationGroupFilter{Page: 1, PerPage: 2}, []string{"ig-3"}},
+		{"by ring", &model.InstallationGroupFilter{RingID: ring2.ID, PerPage: model.AllPerPage}, []string{"ig-2"}},
+		{"by state", &model.InstallationGroupFilter{State: model.InstallationGroupStable, PerPage: model.AllPerPage}, []string{"ig-1", "ig-3"}},
+		{"by provisioner group", &model.InstallationGroupFilter{ProvisionerGroupID: "pg1", PerPage: model.AllPerPage}, []string{"ig-1"}},
+		{"no match", &model.InstallationGroupFilter{RingID: "unknown", PerPage: model.AllPerPage}, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			installationGroups, err := sqlStore.GetInstallationGroups(testCase.filter)
+			require.NoError(t, err)
+
+			var names []string
+			for _, installationGroup := range installationGroups {
+				names = append(names, installationGroup.Name)
+			}
+			assert.Equal(t, testCase.expected, names)
+		})
+	}
+
+	t.Run("ring id", func(t *testing.T) {
+		installationGroups, err := sqlStore.GetInstallationGroups(&model.InstallationGroupFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, installationGroups, 3)
+		assert.Equal(t, ring1.ID, installationGroups[0].RingID)
+		assert.Equal(t, ring2.ID, installationGroups[1].RingID)
+		assert.Empty(t, installationGroups[2].RingID)
+
+		installationGroup, err := sqlStore.GetInstallationGroupByID(installationGroups[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, ring2.ID, installationGroup.RingID)
+	})
+}
+
+func TestMoveInstallationGroup(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installationGroup := &model.InstallationGroup{Name: "ig-1", SoakTime: 30, ProvisionerGroupID: "pg1"}
+	ring1 := &model.Ring{Name: "ring1"}
+	err := sqlStore.CreateRing(ring1, installationGroup)
+	require.NoError(t, err)
+	ring2 := &model.Ring{Name: "ring2"}
+	err = sqlStore.CreateRing(ring2, nil)
+	require.NoError(t, err)
+
+	err = sqlStore.MoveInstallationGroup(installationGroup.ID, ring1.ID, ring2.ID)
+	require.NoError(t, err)
+
+	moved, err := sqlStore.GetInstallationGroupByID(installationGroup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ring2.ID, moved.RingID)
+	assert.Equal(t, 30, moved.SoakTime)
+
+	installationGroups, err := sqlStore.GetInstallationGroupsForRing(ring1.ID)
+	require.NoError(t, err)
+	assert.Empty(t, installationGroups)
+
+	t.Run("not registered to the source ring", func(t *testing.T) {
+		err = sqlStore.MoveInstallationGroup(installationGroup.ID, ring1.ID, ring2.ID)
+		require.Error(t, err)
+	})
 }
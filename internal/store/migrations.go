@@ -209,4 +209,199 @@ var migrations = []migration{
 		}
 		return nil
 	}},
+	{semver.MustParse("0.3.0"), semver.MustParse("0.4.0"), func(e execer) error {
+		if _, apiTokenErr := e.Exec(`
+			CREATE TABLE APIToken (
+				ID TEXT PRIMARY KEY,
+				Name TEXT NOT NULL,
+				Role TEXT NOT NULL,
+				TokenHash TEXT NOT NULL,
+				CreatedBy TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL
+			);
+		`); apiTokenErr != nil {
+			return errors.Wrap(apiTokenErr, "failed to create APIToken table")
+		}
+
+		if _, apiTokenIndexErr := e.Exec(`
+			CREATE UNIQUE INDEX APIToken_TokenHash ON APIToken (TokenHash);
+		`); apiTokenIndexErr != nil {
+			return errors.Wrap(apiTokenIndexErr, "failed to create unique API token index")
+		}
+
+		if _, ringReleaseErr := e.Exec(`ALTER TABLE RingRelease ADD COLUMN CreatedBy TEXT NOT NULL DEFAULT '';`); ringReleaseErr != nil {
+			return errors.Wrap(ringReleaseErr, "failed to add CreatedBy column to RingRelease table")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.4.0"), semver.MustParse("0.5.0"), func(e execer) error {
+		if _, idempotencyRecordErr := e.Exec(`
+			CREATE TABLE IdempotencyRecord (
+				Key VARCHAR(255) PRIMARY KEY,
+				Fingerprint TEXT NOT NULL,
+				StatusCode INT NOT NULL,
+				Body BYTEA NULL,
+				CreateAt BIGINT NOT NULL
+			);
+		`); idempotencyRecordErr != nil {
+			return errors.Wrap(idempotencyRecordErr, "failed to create IdempotencyRecord table")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.5.0"), semver.MustParse("0.6.0"), func(e execer) error {
+		// Ring names were never required to be unique, so unnamed and duplicate
+		// rings are renamed after their ID before enforcing unique names.
+		if _, unnamedRingErr := e.Exec(`
+			UPDATE Ring SET Name = ID WHERE Name = '' AND DeleteAt = 0;
+		`); unnamedRingErr != nil {
+			return errors.Wrap(unnamedRingErr, "failed to name unnamed rings")
+		}
+
+		if _, duplicateRingErr := e.Exec(`
+			UPDATE Ring SET Name = Name || '-' || ID
+			WHERE DeleteAt = 0 AND EXISTS (
+				SELECT 1 FROM Ring Other
+				WHERE Other.Name = Ring.Name AND Other.DeleteAt = 0
+				AND (Other.CreateAt < Ring.CreateAt OR (Other.CreateAt = Ring.CreateAt AND Other.ID < Ring.ID))
+			);
+		`); duplicateRingErr != nil {
+			return errors.Wrap(duplicateRingErr, "failed to rename rings with duplicate names")
+		}
+
+		if _, ringNameIndexErr := e.Exec(`
+			CREATE UNIQUE INDEX Ring_Name_DeleteAt ON Ring (Name, DeleteAt);
+		`); ringNameIndexErr != nil {
+			return errors.Wrap(ringNameIndexErr, "failed to create unique ring name index")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.6.0"), semver.MustParse("0.7.0"), func(e execer) error {
+		if _, installationGroupErr := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN Priority INT NOT NULL DEFAULT 0;`); installationGroupErr != nil {
+			return errors.Wrap(installationGroupErr, "failed to add Priority column to InstallationGroup table")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.7.0"), semver.MustParse("0.8.0"), func(e execer) error {
+		if _, pausedErr := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN Paused BOOLEAN NOT NULL DEFAULT FALSE;`); pausedErr != nil {
+			return errors.Wrap(pausedErr, "failed to add Paused column to InstallationGroup table")
+		}
+
+		if _, skippedErr := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN SkippedReleaseID TEXT NOT NULL DEFAULT '';`); skippedErr != nil {
+			return errors.Wrap(skippedErr, "failed to add SkippedReleaseID column to InstallationGroup table")
+		}
+
+		if _, excludedErr := e.Exec(`ALTER TABLE RingRelease ADD COLUMN ExcludedInstallationGroups TEXT NOT NULL DEFAULT '';`); excludedErr != nil {
+			return errors.Wrap(excludedErr, "failed to add ExcludedInstallationGroups column to RingRelease table")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.8.0"), semver.MustParse("0.9.0"), func(e execer) error {
+		if _, ringDependencyErr := e.Exec(`
+			CREATE TABLE RingDependency (
+				ID TEXT PRIMARY KEY,
+				RingID TEXT NOT NULL,
+				DependsOnRingID TEXT NOT NULL
+			);
+		`); ringDependencyErr != nil {
+			return errors.Wrap(ringDependencyErr, "failed to create RingDependency table")
+		}
+
+		if _, uniqueIndexErr := e.Exec(`
+			CREATE UNIQUE INDEX RingDependency_RingID_DependsOnRingID ON RingDependency (RingID, DependsOnRingID);
+		`); uniqueIndexErr != nil {
+			return errors.Wrap(uniqueIndexErr, "failed to create unique ring dependency index")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.9.0"), semver.MustParse("0.10.0"), func(e execer) error {
+		if _, blockedReleaseErr := e.Exec(`
+			CREATE TABLE BlockedRelease (
+				ID TEXT PRIMARY KEY,
+				Image TEXT NOT NULL,
+				Version TEXT NOT NULL,
+				Reason TEXT NOT NULL,
+				CreatedBy TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL
+			);
+		`); blockedReleaseErr != nil {
+			return errors.Wrap(blockedReleaseErr, "failed to create BlockedRelease table")
+		}
+
+		if _, uniqueIndexErr := e.Exec(`
+			CREATE UNIQUE INDEX BlockedRelease_Image_Version ON BlockedRelease (Image, Version);
+		`); uniqueIndexErr != nil {
+			return errors.Wrap(uniqueIndexErr, "failed to create unique blocked release index")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.10.0"), semver.MustParse("0.11.0"), func(e execer) error {
+		if _, activeErr := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN ActiveReleaseID TEXT NOT NULL DEFAULT '';`); activeErr != nil {
+			return errors.Wrap(activeErr, "failed to add ActiveReleaseID column to InstallationGroup table")
+		}
+
+		if _, desiredErr := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN DesiredReleaseID TEXT NOT NULL DEFAULT '';`); desiredErr != nil {
+			return errors.Wrap(desiredErr, "failed to add DesiredReleaseID column to InstallationGroup table")
+		}
+
+		// Installation groups are assumed to run the active release of their ring, unless
+		// they were left out of it.
+		if _, backfillErr := e.Exec(`
+			UPDATE InstallationGroup SET ActiveReleaseID = COALESCE((
+				SELECT Ring.ActiveReleaseID FROM Ring
+				INNER JOIN RingInstallationGroup ON RingInstallationGroup.RingID = Ring.ID
+				WHERE RingInstallationGroup.InstallationGroupID = InstallationGroup.ID
+				AND InstallationGroup.SkippedReleaseID <> Ring.ActiveReleaseID
+				LIMIT 1
+			), '');
+		`); backfillErr != nil {
+			return errors.Wrap(backfillErr, "failed to backfill installation group active releases")
+		}
+
+		// Installation groups being released are released the desired release of their ring.
+		if _, backfillErr := e.Exec(`
+			UPDATE InstallationGroup SET DesiredReleaseID = CASE WHEN State = 'stable' THEN ActiveReleaseID ELSE COALESCE((
+				SELECT Ring.DesiredReleaseID FROM Ring
+				INNER JOIN RingInstallationGroup ON RingInstallationGroup.RingID = Ring.ID
+				WHERE RingInstallationGroup.InstallationGroupID = InstallationGroup.ID
+				LIMIT 1
+			), '') END;
+		`); backfillErr != nil {
+			return errors.Wrap(backfillErr, "failed to backfill installation group desired releases")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.11.0"), semver.MustParse("0.12.0"), func(e execer) error {
+		if _, driftErr := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN Drift TEXT NOT NULL DEFAULT '';`); driftErr != nil {
+			return errors.Wrap(driftErr, "failed to add Drift column to InstallationGroup table")
+		}
+
+		if _, checkedErr := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN DriftCheckedAt BIGINT NOT NULL DEFAULT 0;`); checkedErr != nil {
+			return errors.Wrap(checkedErr, "failed to add DriftCheckedAt column to InstallationGroup table")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.12.0"), semver.MustParse("0.13.0"), func(e execer) error {
+		if _, err := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN ReleaseFailure TEXT NOT NULL DEFAULT '';`); err != nil {
+			return errors.Wrap(err, "failed to add ReleaseFailure column to InstallationGroup table")
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.13.0"), semver.MustParse("0.14.0"), func(e execer) error {
+		if _, err := e.Exec(`ALTER TABLE InstallationGroup ADD COLUMN ProvisionerTarget TEXT NOT NULL DEFAULT '';`); err != nil {
+			return errors.Wrap(err, "failed to add ProvisionerTarget column to InstallationGroup table")
+		}
+
+		return nil
+	}},
 }
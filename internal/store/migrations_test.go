@@ -0,0 +1,51 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDuplicateRingNames(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := makeUnmigratedTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	for _, migration := range migrations {
+		if migration.toVersion.GT(semver.MustParse("0.5.0")) {
+			break
+		}
+		require.NoError(t, migration.migrationFunc(sqlStore.db))
+	}
+	require.NoError(t, sqlStore.setCurrentVersion(sqlStore.db, "0.5.0"))
+
+	rings := []*model.Ring{
+		{ID: model.NewID(), Name: "ring", CreateAt: 1},
+		{ID: model.NewID(), Name: "ring", CreateAt: 2},
+		{ID: model.NewID(), Name: "", CreateAt: 3},
+		{ID: model.NewID(), Name: "ring", CreateAt: 4, DeleteAt: 5},
+	}
+	for _, ring := range rings {
+		_, err := sqlStore.db.Exec(sqlStore.db.Rebind(
+			"INSERT INTO Ring (ID, Name, Priority, SoakTime, ActiveReleaseID, DesiredReleaseID, Provisioner, State, CreateAt, DeleteAt, ReleaseAt, APISecurityLock, LockAcquiredAt) VALUES (?, ?, 0, 0, '', '', '', '', ?, ?, 0, false, 0)"),
+			ring.ID, ring.Name, ring.CreateAt, ring.DeleteAt,
+		)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, sqlStore.Migrate())
+
+	expectedNames := []string{"ring", "ring-" + rings[1].ID, rings[2].ID, "ring"}
+	for i, ring := range rings {
+		migrated, err := sqlStore.GetRing(ring.ID)
+		require.NoError(t, err)
+		require.Equal(t, expectedNames[i], migrated.Name)
+	}
+}
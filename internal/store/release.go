@@ -6,6 +6,8 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
+	"sort"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattermost/elrond/model"
@@ -25,11 +27,14 @@ var ringReleaseColumns = []string{
 	"RingRelease.CreateAt",
 	"RingRelease.Force",
 	"RingRelease.EnvVariables",
+	"RingRelease.CreatedBy",
+	"RingRelease.ExcludedInstallationGroups",
 }
 
 type rawRingRelease struct {
 	*model.RingRelease
-	EnvVariables []byte
+	EnvVariables               []byte
+	ExcludedInstallationGroups string
 }
 
 func init() {
@@ -49,9 +54,35 @@ func (r *rawRingRelease) toRingRelease() (*model.RingRelease, error) {
 	}
 
 	r.RingRelease.EnvVariables = *mattermostEnv
+
+	if r.ExcludedInstallationGroups != "" {
+		err = json.Unmarshal([]byte(r.ExcludedInstallationGroups), &r.RingRelease.ExcludedInstallationGroups)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return r.RingRelease, nil
 }
 
+// encodeExcludedInstallationGroups serializes the installation groups excluded from a release
+// in a stable form, so that releases excluding the same installation groups compare equal.
+func encodeExcludedInstallationGroups(excludedInstallationGroups []string) (string, error) {
+	if len(excludedInstallationGroups) == 0 {
+		return "", nil
+	}
+
+	sorted := append([]string(nil), excludedInstallationGroups...)
+	sort.Strings(sorted)
+
+	data, err := json.Marshal(sorted)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
 // GetRingRelease fetches the given ring release by ID.
 func (sqlStore *SQLStore) GetRingRelease(releaseID string) (*model.RingRelease, error) {
 	return sqlStore.getRingRelease(sqlStore.db, releaseID)
@@ -88,11 +119,17 @@ func (sqlStore *SQLStore) getOrCreateRingRelease(db execer, ringRelease *model.R
 		return nil, errors.Wrap(err, "failed to create new EnvVarMap JSON")
 	}
 
+	excludedInstallationGroups, err := encodeExcludedInstallationGroups(ringRelease.ExcludedInstallationGroups)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode excluded installation groups")
+	}
+
 	builder := ringReleaseSelect.
 		Where("Image = ?", ringRelease.Image).
 		Where("Version = ?", ringRelease.Version).
 		Where("Force = ?", ringRelease.Force).
 		Where("EnvVariables = ?", envVarMap).
+		Where("ExcludedInstallationGroups = ?", excludedInstallationGroups).
 		Limit(1)
 
 	err = sqlStore.getBuilder(sqlStore.db, &rawRingReleaseOutput, builder)
@@ -101,12 +138,14 @@ func (sqlStore *SQLStore) getOrCreateRingRelease(db execer, ringRelease *model.R
 			sqlStore.logger.Debug("Entry does not exist in the db. Inserting...")
 			_, err = sqlStore.execBuilder(db, sq.Insert("RingRelease").
 				SetMap(map[string]interface{}{
-					"ID":           ringRelease.ID,
-					"Image":        ringRelease.Image,
-					"Version":      ringRelease.Version,
-					"EnvVariables": envVarMap,
-					"CreateAt":     ringRelease.CreateAt,
-					"Force":        ringRelease.Force,
+					"ID":                         ringRelease.ID,
+					"Image":                      ringRelease.Image,
+					"Version":                    ringRelease.Version,
+					"EnvVariables":               envVarMap,
+					"CreateAt":                   ringRelease.CreateAt,
+					"Force":                      ringRelease.Force,
+					"CreatedBy":                  ringRelease.CreatedBy,
+					"ExcludedInstallationGroups": excludedInstallationGroups,
 				}))
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to create ring release")
@@ -118,3 +157,46 @@ func (sqlStore *SQLStore) getOrCreateRingRelease(db execer, ringRelease *model.R
 
 	return rawRingReleaseOutput.toRingRelease()
 }
+
+// GetRingReleases fetches the given page of ring releases, oldest first. The first page is 0.
+func (sqlStore *SQLStore) GetRingReleases(filter *model.RingReleaseFilter) ([]*model.RingRelease, error) {
+	var rawRingReleases []*rawRingRelease
+
+	builder := ringReleaseSelect.
+		OrderBy("CreateAt ASC", "ID ASC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if filter.Image != "" {
+		builder = builder.Where("Image = ?", filter.Image)
+	}
+	if filter.Version != "" {
+		builder = builder.Where("Version = ?", filter.Version)
+	}
+	if filter.CreatedAfter != 0 {
+		builder = builder.Where("CreateAt > ?", filter.CreatedAfter)
+	}
+	if filter.Force != nil {
+		builder = builder.Where("Force = ?", *filter.Force)
+	}
+
+	err := sqlStore.selectBuilder(sqlStore.db, &rawRingReleases, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for ring releases")
+	}
+
+	ringReleases := make([]*model.RingRelease, 0, len(rawRingReleases))
+	for _, rawRingRelease := range rawRingReleases {
+		ringRelease, err := rawRingRelease.toRingRelease()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode environment variables of ring release %s", rawRingRelease.ID)
+		}
+		ringReleases = append(ringReleases, ringRelease)
+	}
+
+	return ringReleases, nil
+}
@@ -37,4 +37,76 @@ func TestRingRelease(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, ringRelease1, actualRingRelease1)
 	})
+
+	t.Run("excluded installation groups", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := MakeTestSQLStore(t, logger)
+		defer CloseConnection(t, sqlStore)
+
+		ringRelease1, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{
+			Image:                      "test",
+			Version:                    "test",
+			ExcludedInstallationGroups: []string{"ig-2", "ig-1"},
+		})
+		require.NoError(t, err)
+
+		ringRelease2, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{
+			Image:                      "test",
+			Version:                    "test",
+			ExcludedInstallationGroups: []string{"ig-1", "ig-2"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, ringRelease1.ID, ringRelease2.ID)
+
+		ringRelease3, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{
+			Image:   "test",
+			Version: "test",
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, ringRelease1.ID, ringRelease3.ID)
+
+		actualRingRelease1, err := sqlStore.GetRingRelease(ringRelease1.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"ig-1", "ig-2"}, actualRingRelease1.ExcludedInstallationGroups)
+		require.True(t, actualRingRelease1.ExcludesInstallationGroup(&model.InstallationGroup{Name: "ig-1"}))
+		require.False(t, actualRingRelease1.ExcludesInstallationGroup(&model.InstallationGroup{Name: "ig-3"}))
+	})
+
+	t.Run("get ring releases", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := MakeTestSQLStore(t, logger)
+		defer CloseConnection(t, sqlStore)
+
+		ringRelease1, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "1.0.0", CreateAt: 1})
+		require.NoError(t, err)
+		ringRelease2, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "1.1.0", CreateAt: 2, Force: true})
+		require.NoError(t, err)
+		ringRelease3, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "other", Version: "1.1.0", CreateAt: 3})
+		require.NoError(t, err)
+
+		forced := true
+		unforced := false
+		for _, tc := range []struct {
+			name     string
+			filter   *model.RingReleaseFilter
+			expected []*model.RingRelease
+		}{
+			{"all", &model.RingReleaseFilter{PerPage: model.AllPerPage}, []*model.RingRelease{ringRelease1, ringRelease2, ringRelease3}},
+			{"page", &model.RingReleaseFilter{Page: 1, PerPage: 2}, []*model.RingRelease{ringRelease3}},
+			{"image", &model.RingReleaseFilter{Image: "image", PerPage: model.AllPerPage}, []*model.RingRelease{ringRelease1, ringRelease2}},
+			{"version", &model.RingReleaseFilter{Version: "1.1.0", PerPage: model.AllPerPage}, []*model.RingRelease{ringRelease2, ringRelease3}},
+			{"created after", &model.RingReleaseFilter{CreatedAfter: 1, PerPage: model.AllPerPage}, []*model.RingRelease{ringRelease2, ringRelease3}},
+			{"forced", &model.RingReleaseFilter{Force: &forced, PerPage: model.AllPerPage}, []*model.RingRelease{ringRelease2}},
+			{"unforced", &model.RingReleaseFilter{Force: &unforced, PerPage: model.AllPerPage}, []*model.RingRelease{ringRelease1, ringRelease3}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				ringReleases, err := sqlStore.GetRingReleases(tc.filter)
+				require.NoError(t, err)
+				require.Len(t, ringReleases, len(tc.expected))
+				for i, ringRelease := range ringReleases {
+					require.Equal(t, tc.expected[i].ID, ringRelease.ID)
+				}
+			})
+		}
+	})
 }
@@ -33,6 +33,22 @@ func (sqlStore *SQLStore) GetRing(id string) (*model.Ring, error) {
 	return &ring, nil
 }
 
+// GetRingByName fetches the non-deleted ring with the given name.
+func (sqlStore *SQLStore) GetRingByName(name string) (*model.Ring, error) {
+	var ring model.Ring
+	err := sqlStore.getBuilder(sqlStore.db, &ring, ringSelect.
+		Where("Name = ?", name).
+		Where("DeleteAt = 0"),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get ring by name")
+	}
+
+	return &ring, nil
+}
+
 // GetRings fetches the given page of created rings. The first page is 0.
 func (sqlStore *SQLStore) GetRings(filter *model.RingFilter) ([]*model.Ring, error) {
 	builder := ringSelect.
@@ -206,6 +222,12 @@ func (sqlStore *SQLStore) createRing(execer execer, ring *model.Ring) error {
 		return errors.Wrap(err, "failed to create ring")
 	}
 
+	if len(ring.DependsOn) > 0 {
+		if err := sqlStore.setRingDependencies(execer, ring.ID, ring.DependsOn); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -288,6 +310,10 @@ func (sqlStore *SQLStore) DeleteRing(id string) error {
 		return errors.Wrap(err, "failed to mark ring as deleted")
 	}
 
+	if err = sqlStore.deleteRingDependencies(sqlStore.db, id); err != nil {
+		return err
+	}
+
 	_, err = sqlStore.DeleteInstallationGroupsFromRing(id)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete installation groups from deleted ring")
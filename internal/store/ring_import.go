@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+)
+
+// ImportRings creates the rings and installation groups of the given import in a single
+// transaction. Created rings start on the release of their first installation group, and
+// every installation group is recorded as running the release of its provisioner group.
+func (sqlStore *SQLStore) ImportRings(ringImport *model.RingImport) ([]*model.Ring, error) {
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	var createdRings []*model.Ring
+
+	for _, importRing := range ringImport.Rings {
+		if len(importRing.InstallationGroups) == 0 {
+			continue
+		}
+
+		if importRing.Create {
+			releaseID := importRing.InstallationGroups[0].ReleaseID
+			ring := &model.Ring{
+				Name:             importRing.Name,
+				Priority:         importRing.Priority,
+				SoakTime:         importRing.SoakTime,
+				ActiveReleaseID:  releaseID,
+				DesiredReleaseID: releaseID,
+				Provisioner:      "elrond",
+				State:            model.RingStateCreationRequested,
+			}
+			if err = sqlStore.createRing(tx, ring); err != nil {
+				return nil, errors.Wrapf(err, "failed to create ring %s", importRing.Name)
+			}
+			importRing.ID = ring.ID
+			createdRings = append(createdRings, ring)
+		}
+		if importRing.ID == "" {
+			return nil, errors.Errorf("ring %s not found", importRing.Name)
+		}
+
+		for _, importGroup := range importRing.InstallationGroups {
+			installationGroup := &model.InstallationGroup{
+				Name:               importGroup.Name,
+				State:              model.InstallationGroupStable,
+				ProvisionerGroupID: importGroup.ProvisionerGroupID,
+				ProvisionerTarget:  importGroup.ProvisionerTarget,
+				ActiveReleaseID:    importGroup.ReleaseID,
+				DesiredReleaseID:   importGroup.ReleaseID,
+			}
+			if err = sqlStore.createInstallationGroup(tx, installationGroup); err != nil {
+				return nil, errors.Wrapf(err, "failed to create installation group %s", importGroup.Name)
+			}
+			if _, err = sqlStore.createRingInstallationGroup(tx, importRing.ID, installationGroup); err != nil {
+				return nil, errors.Wrapf(err, "failed to register installation group %s", importGroup.Name)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return createdRings, nil
+}
@@ -153,11 +153,98 @@ func TestRings(t *testing.T) {
 	})
 }
 
+func TestGetRingByName(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	ring1 := &model.Ring{Name: "ring-1", Priority: 1}
+	err := sqlStore.CreateRing(ring1, nil)
+	require.NoError(t, err)
+
+	ring, err := sqlStore.GetRingByName("ring-1")
+	require.NoError(t, err)
+	require.Equal(t, ring1, ring)
+
+	ring, err = sqlStore.GetRingByName("unknown")
+	require.NoError(t, err)
+	require.Nil(t, ring)
+
+	t.Run("names of non-deleted rings are unique", func(t *testing.T) {
+		err = sqlStore.CreateRing(&model.Ring{Name: "ring-1", Priority: 2}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("names of deleted rings can be reused", func(t *testing.T) {
+		err = sqlStore.DeleteRing(ring1.ID)
+		require.NoError(t, err)
+
+		ring, err = sqlStore.GetRingByName("ring-1")
+		require.NoError(t, err)
+		require.Nil(t, ring)
+
+		ring2 := &model.Ring{Name: "ring-1", Priority: 2}
+		err = sqlStore.CreateRing(ring2, nil)
+		require.NoError(t, err)
+
+		ring, err = sqlStore.GetRingByName("ring-1")
+		require.NoError(t, err)
+		require.Equal(t, ring2.ID, ring.ID)
+	})
+}
+
+func TestRingDependencies(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	ring1 := &model.Ring{Name: "ring-1", Priority: 1}
+	err := sqlStore.CreateRing(ring1, nil)
+	require.NoError(t, err)
+	ring2 := &model.Ring{Name: "ring-2", Priority: 1}
+	err = sqlStore.CreateRing(ring2, nil)
+	require.NoError(t, err)
+	ring3 := &model.Ring{Name: "ring-3", Priority: 2, DependsOn: []string{ring1.ID}}
+	err = sqlStore.CreateRing(ring3, nil)
+	require.NoError(t, err)
+
+	dependencies, err := sqlStore.GetRingDependencies()
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{ring3.ID: {ring1.ID}}, dependencies)
+
+	t.Run("replace dependencies", func(t *testing.T) {
+		err = sqlStore.SetRingDependencies(ring3.ID, []string{ring2.ID, ring1.ID, ring2.ID})
+		require.NoError(t, err)
+
+		dependencies, err = sqlStore.GetRingDependencies()
+		require.NoError(t, err)
+		require.Len(t, dependencies[ring3.ID], 2)
+		require.ElementsMatch(t, []string{ring1.ID, ring2.ID}, dependencies[ring3.ID])
+	})
+
+	t.Run("deleting a ring removes its dependencies", func(t *testing.T) {
+		err = sqlStore.DeleteRing(ring1.ID)
+		require.NoError(t, err)
+
+		dependencies, err = sqlStore.GetRingDependencies()
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{ring3.ID: {ring2.ID}}, dependencies)
+
+		err = sqlStore.SetRingDependencies(ring3.ID, nil)
+		require.NoError(t, err)
+
+		dependencies, err = sqlStore.GetRingDependencies()
+		require.NoError(t, err)
+		require.Empty(t, dependencies)
+	})
+}
+
 func TestGetUnlockedRingsPendingWork(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := MakeTestSQLStore(t, logger)
 
 	creationRequestedRing := &model.Ring{
+		Name:  "creation-requested",
 		State: model.RingStateCreationRequested,
 	}
 
@@ -167,6 +254,7 @@ func TestGetUnlockedRingsPendingWork(t *testing.T) {
 	time.Sleep(1 * time.Millisecond)
 
 	releaseRequestedRing := &model.Ring{
+		Name:  "release-pending",
 		State: model.RingStateReleasePending,
 	}
 	err = sqlStore.CreateRing(releaseRequestedRing, nil)
@@ -175,6 +263,7 @@ func TestGetUnlockedRingsPendingWork(t *testing.T) {
 	time.Sleep(1 * time.Millisecond)
 
 	deletionRequestedRing := &model.Ring{
+		Name:  "deletion-requested",
 		State: model.RingStateDeletionRequested,
 	}
 	err = sqlStore.CreateRing(deletionRequestedRing, nil)
@@ -189,7 +278,7 @@ func TestGetUnlockedRingsPendingWork(t *testing.T) {
 		model.RingStateStable,
 	}
 	for _, otherState := range otherStates {
-		err = sqlStore.CreateRing(&model.Ring{State: otherState}, nil)
+		err = sqlStore.CreateRing(&model.Ring{Name: otherState, State: otherState}, nil)
 		require.NoError(t, err)
 	}
 
@@ -231,11 +320,11 @@ func TestLockRing(t *testing.T) {
 	lockerID1 := model.NewID()
 	lockerID2 := model.NewID()
 
-	ring1 := &model.Ring{}
+	ring1 := &model.Ring{Name: "ring-1"}
 	err := sqlStore.CreateRing(ring1, nil)
 	require.NoError(t, err)
 
-	ring2 := &model.Ring{}
+	ring2 := &model.Ring{Name: "ring-2"}
 	err = sqlStore.CreateRing(ring2, nil)
 	require.NoError(t, err)
 
@@ -0,0 +1,106 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"sort"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+)
+
+const ringDependencyTable = "RingDependency"
+
+type ringDependency struct {
+	RingID          string
+	DependsOnRingID string
+}
+
+// GetRingDependencies fetches the IDs of the rings each ring depends on, keyed by the ID
+// of the dependent ring. Rings without dependencies are left out.
+func (sqlStore *SQLStore) GetRingDependencies() (map[string][]string, error) {
+	return sqlStore.getRingDependencies(sqlStore.db)
+}
+
+func (sqlStore *SQLStore) getRingDependencies(db queryer) (map[string][]string, error) {
+	var ringDependencies []*ringDependency
+	builder := sq.Select("RingID", "DependsOnRingID").
+		From(ringDependencyTable).
+		OrderBy("RingID ASC", "DependsOnRingID ASC")
+	if err := sqlStore.selectBuilder(db, &ringDependencies, builder); err != nil {
+		return nil, errors.Wrap(err, "failed to query for ring dependencies")
+	}
+
+	dependencies := make(map[string][]string)
+	for _, dependency := range ringDependencies {
+		dependencies[dependency.RingID] = append(dependencies[dependency.RingID], dependency.DependsOnRingID)
+	}
+
+	return dependencies, nil
+}
+
+// SetRingDependencies replaces the rings the given ring depends on.
+func (sqlStore *SQLStore) SetRingDependencies(ringID string, dependsOn []string) error {
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if err = sqlStore.setRingDependencies(tx, ringID, dependsOn); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit the transaction")
+	}
+
+	return nil
+}
+
+func (sqlStore *SQLStore) setRingDependencies(db execer, ringID string, dependsOn []string) error {
+	if _, err := sqlStore.execBuilder(db, sq.
+		Delete(ringDependencyTable).
+		Where("RingID = ?", ringID),
+	); err != nil {
+		return errors.Wrap(err, "failed to delete ring dependencies")
+	}
+
+	dependencies := make(map[string]bool)
+	for _, dependencyID := range dependsOn {
+		dependencies[dependencyID] = true
+	}
+	dependencyIDs := make([]string, 0, len(dependencies))
+	for dependencyID := range dependencies {
+		dependencyIDs = append(dependencyIDs, dependencyID)
+	}
+	sort.Strings(dependencyIDs)
+
+	for _, dependencyID := range dependencyIDs {
+		if _, err := sqlStore.execBuilder(db, sq.
+			Insert(ringDependencyTable).
+			Columns("ID", "RingID", "DependsOnRingID").
+			Values(model.NewID(), ringID, dependencyID),
+		); err != nil {
+			return errors.Wrap(err, "failed to create ring dependency")
+		}
+	}
+
+	return nil
+}
+
+// deleteRingDependencies removes the dependencies of the given ring and the dependencies
+// of other rings on it.
+func (sqlStore *SQLStore) deleteRingDependencies(db execer, ringID string) error {
+	if _, err := sqlStore.execBuilder(db, sq.
+		Delete(ringDependencyTable).
+		Where(sq.Or{sq.Eq{"RingID": ringID}, sq.Eq{"DependsOnRingID": ringID}}),
+	); err != nil {
+		return errors.Wrap(err, "failed to delete ring dependencies")
+	}
+
+	return nil
+}
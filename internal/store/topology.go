@@ -0,0 +1,323 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+)
+
+// ApplyTopology applies the changes of the given topology plan in a single transaction.
+// Rings created by the plan are installed with the given release and returned, while
+// rings deleted by the plan are only marked for deletion by the ring supervisor.
+func (sqlStore *SQLStore) ApplyTopology(plan *model.TopologyPlan, releaseID string) ([]*model.Ring, error) {
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	var createdRings []*model.Ring
+	ringIDs := map[string]string{}
+
+	for _, change := range plan.Changes {
+		switch {
+		case change.Kind == model.TopologyKindRing && change.Action == model.TopologyActionCreate:
+			ring := &model.Ring{
+				Name:             change.Name,
+				Priority:         change.Priority,
+				SoakTime:         change.SoakTime,
+				ActiveReleaseID:  releaseID,
+				DesiredReleaseID: releaseID,
+				Provisioner:      "elrond",
+				State:            model.RingStateCreationRequested,
+			}
+			if err = sqlStore.createRing(tx, ring); err != nil {
+				return nil, errors.Wrapf(err, "failed to create ring %s", change.Name)
+			}
+			ringIDs[ring.Name] = ring.ID
+			createdRings = append(createdRings, ring)
+
+		case change.Kind == model.TopologyKindRing && change.Action == model.TopologyActionUpdate:
+			if _, err = sqlStore.execBuilder(tx, sq.
+				Update("Ring").
+				SetMap(map[string]interface{}{
+					"Priority": change.Priority,
+					"SoakTime": change.SoakTime,
+				}).
+				Where("ID = ?", change.ID),
+			); err != nil {
+				return nil, errors.Wrapf(err, "failed to update ring %s", change.Name)
+			}
+
+		case change.Kind == model.TopologyKindRing && change.Action == model.TopologyActionDelete:
+			if _, err = sqlStore.execBuilder(tx, sq.
+				Update("Ring").
+				Set("State", model.RingStateDeletionRequested).
+				Where("ID = ?", change.ID),
+			); err != nil {
+				return nil, errors.Wrapf(err, "failed to request deletion of ring %s", change.Name)
+			}
+
+		case change.Kind == model.TopologyKindInstallationGroup && change.Action == model.TopologyActionDeregister:
+			if _, err = sqlStore.execBuilder(tx, sq.
+				Delete(ringInstallationGroupTable).
+				Where("RingID = ?", change.RingID).
+				Where("InstallationGroupID = ?", change.ID),
+			); err != nil {
+				return nil, errors.Wrapf(err, "failed to deregister installation group %s", change.Name)
+			}
+
+		case change.Kind == model.TopologyKindInstallationGroup && change.Action == model.TopologyActionRegister:
+			ringID := change.RingID
+			if ringID == "" {
+				ringID = ringIDs[change.Ring]
+			}
+			if ringID == "" {
+				return nil, errors.Errorf("ring %s of installation group %s not found", change.Ring, change.Name)
+			}
+
+			installationGroup, err := sqlStore.getOrCreateInstallationGroup(tx, &model.InstallationGroup{
+				Name:               change.Name,
+				State:              model.InstallationGroupStable,
+				SoakTime:           change.SoakTime,
+				ProvisionerGroupID: change.ProvisionerGroupID,
+				ProvisionerTarget:  change.ProvisionerTarget,
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get or create installation group %s", change.Name)
+			}
+			if err = sqlStore.updateInstallationGroupSettings(tx, installationGroup.ID, change); err != nil {
+				return nil, err
+			}
+			if _, err = sqlStore.createRingInstallationGroup(tx, ringID, installationGroup); err != nil {
+				return nil, errors.Wrapf(err, "failed to register installation group %s", change.Name)
+			}
+
+		case change.Kind == model.TopologyKindInstallationGroup && change.Action == model.TopologyActionUpdate:
+			if err = sqlStore.updateInstallationGroupSettings(tx, change.ID, change); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, errors.Errorf("unsupported topology change %s of %s %s", change.Action, change.Kind, change.Name)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return createdRings, nil
+}
+
+func (sqlStore *SQLStore) updateInstallationGroupSettings(db execer, installationGroupID string, change *model.TopologyChange) error {
+	if _, err := sqlStore.execBuilder(db, sq.
+		Update("InstallationGroup").
+		SetMap(map[string]interface{}{
+			"SoakTime":           change.SoakTime,
+			"ProvisionerGroupID": change.ProvisionerGroupID,
+			"ProvisionerTarget":  change.ProvisionerTarget,
+			"Priority":           change.Priority,
+		}).
+		Where("ID = ?", installationGroupID),
+	); err != nil {
+		return errors.Wrapf(err, "failed to update installation group %s", change.Name)
+	}
+
+	return nil
+}
+
+// ExportTopology returns a snapshot of all rings, including deleted ones, with their
+// installation groups, webhooks, ring releases and blocked releases.
+func (sqlStore *SQLStore) ExportTopology() (*model.TopologyExport, error) {
+	filter := &model.RingFilter{PerPage: model.AllPerPage, IncludeDeleted: true}
+
+	rings, err := sqlStore.GetRings(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	installationGroups, err := sqlStore.GetInstallationGroupsForRings(filter)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := sqlStore.GetRingDependencies()
+	if err != nil {
+		return nil, err
+	}
+	for _, ring := range rings {
+		ring.InstallationGroups = installationGroups[ring.ID]
+		ring.DependsOn = dependencies[ring.ID]
+	}
+
+	webhooks, err := sqlStore.GetWebhooks(&model.WebhookFilter{PerPage: model.AllPerPage, IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := sqlStore.GetRingReleases(&model.RingReleaseFilter{PerPage: model.AllPerPage})
+	if err != nil {
+		return nil, err
+	}
+
+	blockedReleases, err := sqlStore.GetBlockedReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	export := &model.TopologyExport{
+		ExportAt:        GetMillis(),
+		Rings:           rings,
+		Webhooks:        webhooks,
+		Releases:        releases,
+		BlockedReleases: blockedReleases,
+	}
+	if export.Rings == nil {
+		export.Rings = []*model.Ring{}
+	}
+	if export.Webhooks == nil {
+		export.Webhooks = []*model.Webhook{}
+	}
+
+	return export, nil
+}
+
+// RestoreTopology loads the given export into the database, keeping the IDs of all
+// records. It refuses to restore into a database that already has rings, installation
+// groups, webhooks, ring releases or blocked releases. Locks held at the time of the
+// export are dropped.
+func (sqlStore *SQLStore) RestoreTopology(export *model.TopologyExport) error {
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	for _, table := range []string{"Ring", "InstallationGroup", "Webhooks", "RingRelease", "BlockedRelease"} {
+		var count int64
+		if err = sqlStore.getBuilder(tx, &count, sq.Select("COUNT(*)").From(table)); err != nil {
+			return errors.Wrapf(err, "failed to count rows of %s", table)
+		}
+		if count > 0 {
+			return errors.Errorf("database is not empty, table %s has %d rows", table, count)
+		}
+	}
+
+	for _, release := range export.Releases {
+		envVarMap, err := release.EnvVariables.ToJSON()
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode environment variables of ring release %s", release.ID)
+		}
+		excludedInstallationGroups, err := encodeExcludedInstallationGroups(release.ExcludedInstallationGroups)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode excluded installation groups of ring release %s", release.ID)
+		}
+
+		if _, err = sqlStore.execBuilder(tx, sq.Insert("RingRelease").
+			SetMap(map[string]interface{}{
+				"ID":                         release.ID,
+				"Image":                      release.Image,
+				"Version":                    release.Version,
+				"EnvVariables":               envVarMap,
+				"CreateAt":                   release.CreateAt,
+				"Force":                      release.Force,
+				"CreatedBy":                  release.CreatedBy,
+				"ExcludedInstallationGroups": excludedInstallationGroups,
+			}),
+		); err != nil {
+			return errors.Wrapf(err, "failed to restore ring release %s", release.ID)
+		}
+	}
+
+	for _, ring := range export.Rings {
+		if _, err = sqlStore.execBuilder(tx, sq.Insert("Ring").
+			SetMap(map[string]interface{}{
+				"ID":               ring.ID,
+				"Name":             ring.Name,
+				"Priority":         ring.Priority,
+				"State":            ring.State,
+				"SoakTime":         ring.SoakTime,
+				"ActiveReleaseID":  ring.ActiveReleaseID,
+				"DesiredReleaseID": ring.DesiredReleaseID,
+				"Provisioner":      ring.Provisioner,
+				"CreateAt":         ring.CreateAt,
+				"ReleaseAt":        ring.ReleaseAt,
+				"DeleteAt":         ring.DeleteAt,
+				"APISecurityLock":  ring.APISecurityLock,
+				"LockAcquiredBy":   nil,
+				"LockAcquiredAt":   0,
+			}),
+		); err != nil {
+			return errors.Wrapf(err, "failed to restore ring %s", ring.ID)
+		}
+
+		if err = sqlStore.setRingDependencies(tx, ring.ID, ring.DependsOn); err != nil {
+			return errors.Wrapf(err, "failed to restore dependencies of ring %s", ring.ID)
+		}
+
+		for _, installationGroup := range ring.InstallationGroups {
+			if _, err = sqlStore.execBuilder(tx, sq.Insert("InstallationGroup").
+				SetMap(map[string]interface{}{
+					"ID":                 installationGroup.ID,
+					"Name":               installationGroup.Name,
+					"State":              installationGroup.State,
+					"ReleaseAt":          installationGroup.ReleaseAt,
+					"SoakTime":           installationGroup.SoakTime,
+					"ProvisionerGroupID": installationGroup.ProvisionerGroupID,
+					"ProvisionerTarget":  installationGroup.ProvisionerTarget,
+					"Priority":           installationGroup.Priority,
+					"Paused":             installationGroup.Paused,
+					"SkippedReleaseID":   installationGroup.SkippedReleaseID,
+					"ActiveReleaseID":    installationGroup.ActiveReleaseID,
+					"DesiredReleaseID":   installationGroup.DesiredReleaseID,
+					"Drift":              installationGroup.Drift,
+					"DriftCheckedAt":     installationGroup.DriftCheckedAt,
+					"ReleaseFailure":     installationGroup.ReleaseFailure,
+					"LockAcquiredBy":     nil,
+					"LockAcquiredAt":     0,
+				}),
+			); err != nil {
+				return errors.Wrapf(err, "failed to restore installation group %s", installationGroup.ID)
+			}
+
+			if _, err = sqlStore.createRingInstallationGroup(tx, ring.ID, installationGroup); err != nil {
+				return errors.Wrapf(err, "failed to restore registration of installation group %s", installationGroup.ID)
+			}
+		}
+	}
+
+	for _, blockedRelease := range export.BlockedReleases {
+		if _, err = sqlStore.execBuilder(tx, sq.Insert("BlockedRelease").
+			SetMap(map[string]interface{}{
+				"ID":        blockedRelease.ID,
+				"Image":     blockedRelease.Image,
+				"Version":   blockedRelease.Version,
+				"Reason":    blockedRelease.Reason,
+				"CreatedBy": blockedRelease.CreatedBy,
+				"CreateAt":  blockedRelease.CreateAt,
+			}),
+		); err != nil {
+			return errors.Wrapf(err, "failed to restore blocked release %s", blockedRelease.ID)
+		}
+	}
+
+	for _, webhook := range export.Webhooks {
+		if _, err = sqlStore.execBuilder(tx, sq.Insert("Webhooks").
+			SetMap(map[string]interface{}{
+				"ID":       webhook.ID,
+				"OwnerID":  webhook.OwnerID,
+				"URL":      webhook.URL,
+				"CreateAt": webhook.CreateAt,
+				"DeleteAt": webhook.DeleteAt,
+			}),
+		); err != nil {
+			return errors.Wrapf(err, "failed to restore webhook %s", webhook.ID)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	cmodel "github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTopology(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	ring1 := &model.Ring{Name: "ring-1", Priority: 1, SoakTime: 60, State: model.RingStateStable}
+	err := sqlStore.CreateRing(ring1, &model.InstallationGroup{Name: "ig-1", SoakTime: 30, ProvisionerGroupID: "pg1"})
+	require.NoError(t, err)
+	ring2 := &model.Ring{Name: "ring-2", Priority: 2, SoakTime: 60, State: model.RingStateStable}
+	err = sqlStore.CreateRing(ring2, &model.InstallationGroup{Name: "ig-2", SoakTime: 30, ProvisionerGroupID: "pg2"})
+	require.NoError(t, err)
+
+	currentRings := func() []*model.Ring {
+		rings, getErr := sqlStore.GetRings(&model.RingFilter{PerPage: model.AllPerPage})
+		require.NoError(t, getErr)
+		installationGroups, getErr := sqlStore.GetInstallationGroupsForRings(&model.RingFilter{PerPage: model.AllPerPage})
+		require.NoError(t, getErr)
+		for _, ring := range rings {
+			ring.InstallationGroups = installationGroups[ring.ID]
+		}
+		return rings
+	}
+
+	topology := &model.Topology{Rings: []*model.TopologyRing{
+		{Name: "ring-1", Priority: 5, SoakTime: 60, InstallationGroups: []*model.TopologyInstallationGroup{
+			{Name: "ig-1", SoakTime: 90, ProvisionerGroupID: "pg1"},
+			{Name: "ig-2", SoakTime: 30, ProvisionerGroupID: "pg2"},
+		}},
+		{Name: "ring-3", Priority: 3, SoakTime: 120, InstallationGroups: []*model.TopologyInstallationGroup{
+			{Name: "ig-3", SoakTime: 10, ProvisionerGroupID: "pg3"},
+		}},
+	}}
+
+	plan, err := model.PlanTopology(topology, currentRings(), true)
+	require.NoError(t, err)
+
+	createdRings, err := sqlStore.ApplyTopology(plan, "release")
+	require.NoError(t, err)
+	require.Len(t, createdRings, 1)
+	require.Equal(t, "ring-3", createdRings[0].Name)
+	require.Equal(t, model.RingStateCreationRequested, createdRings[0].State)
+	require.Equal(t, "release", createdRings[0].DesiredReleaseID)
+
+	ring, err := sqlStore.GetRing(ring1.ID)
+	require.NoError(t, err)
+	require.Equal(t, 5, ring.Priority)
+
+	ring, err = sqlStore.GetRing(ring2.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateDeletionRequested, ring.State)
+
+	installationGroups, err := sqlStore.GetInstallationGroupsForRing(ring1.ID)
+	require.NoError(t, err)
+	require.Len(t, installationGroups, 2)
+	model.SortInstallationGroups(installationGroups)
+	require.Equal(t, 90, installationGroups[0].SoakTime)
+	require.Equal(t, "ig-2", installationGroups[1].Name)
+
+	installationGroups, err = sqlStore.GetInstallationGroupsForRing(ring2.ID)
+	require.NoError(t, err)
+	require.Empty(t, installationGroups)
+
+	installationGroups, err = sqlStore.GetInstallationGroupsForRing(createdRings[0].ID)
+	require.NoError(t, err)
+	require.Len(t, installationGroups, 1)
+	require.Equal(t, "pg3", installationGroups[0].ProvisionerGroupID)
+
+	t.Run("converged", func(t *testing.T) {
+		plan, err = model.PlanTopology(topology, currentRings(), false)
+		require.NoError(t, err)
+		require.True(t, plan.IsEmpty())
+	})
+
+	t.Run("failed change rolls back", func(t *testing.T) {
+		_, err = sqlStore.ApplyTopology(&model.TopologyPlan{Changes: []*model.TopologyChange{
+			{Action: model.TopologyActionUpdate, Kind: model.TopologyKindRing, Name: "ring-1", ID: ring1.ID, Priority: 9, SoakTime: 60},
+			{Action: model.TopologyActionRegister, Kind: model.TopologyKindInstallationGroup, Name: "ig-9", Ring: "unknown"},
+		}}, "release")
+		require.Error(t, err)
+
+		ring, err = sqlStore.GetRing(ring1.ID)
+		require.NoError(t, err)
+		require.Equal(t, 5, ring.Priority)
+	})
+}
+
+func TestExportRestoreTopology(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{
+		Image:        "image",
+		Version:      "1.0.0",
+		EnvVariables: cmodel.EnvVarMap{"MM_TEST": cmodel.EnvVar{Value: "true"}},
+		CreatedBy:    "ci",
+	})
+	require.NoError(t, err)
+
+	ring1 := &model.Ring{Name: "ring-1", Priority: 1, State: model.RingStateStable, ActiveReleaseID: release.ID, DesiredReleaseID: release.ID}
+	err = sqlStore.CreateRing(ring1, &model.InstallationGroup{Name: "ig-1", State: model.InstallationGroupStable, ProvisionerGroupID: "pg1"})
+	require.NoError(t, err)
+	ring2 := &model.Ring{Name: "ring-2", Priority: 2, State: model.RingStateStable, APISecurityLock: true}
+	err = sqlStore.CreateRing(ring2, nil)
+	require.NoError(t, err)
+	err = sqlStore.DeleteRing(ring2.ID)
+	require.NoError(t, err)
+
+	locked, err := sqlStore.LockRing(ring1.ID, "locker")
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	err = sqlStore.CreateWebhook(&model.Webhook{OwnerID: "owner", URL: "https://example.com"})
+	require.NoError(t, err)
+
+	err = sqlStore.CreateBlockedRelease(&model.BlockedRelease{Image: "image", Version: "0.9.0", Reason: "broken"})
+	require.NoError(t, err)
+
+	export, err := sqlStore.ExportTopology()
+	require.NoError(t, err)
+	require.Len(t, export.Rings, 2)
+	require.Len(t, export.Rings[0].InstallationGroups, 1)
+	require.NotZero(t, export.Rings[1].DeleteAt)
+	require.Len(t, export.Webhooks, 1)
+	require.Len(t, export.Releases, 1)
+	require.Len(t, export.BlockedReleases, 1)
+
+	t.Run("refuse non-empty database", func(t *testing.T) {
+		err = sqlStore.RestoreTopology(export)
+		require.Error(t, err)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		restoredStore := MakeTestSQLStore(t, logger)
+		defer CloseConnection(t, restoredStore)
+
+		err = restoredStore.RestoreTopology(export)
+		require.NoError(t, err)
+
+		restored, err := restoredStore.ExportTopology()
+		require.NoError(t, err)
+
+		require.Nil(t, restored.Rings[0].LockAcquiredBy)
+		export.Rings[0].LockAcquiredBy = nil
+		export.Rings[0].LockAcquiredAt = 0
+		restored.ExportAt = export.ExportAt
+		require.Equal(t, export, restored)
+
+		restoredRelease, err := restoredStore.GetRingRelease(release.ID)
+		require.NoError(t, err)
+		require.Equal(t, "true", restoredRelease.EnvVariables["MM_TEST"].Value)
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/elrond/internal/webhook"
+	"github.com/mattermost/elrond/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// driftStore abstracts the database operations required to detect provisioner group drift.
+type driftStore interface {
+	GetRings(filter *model.RingFilter) ([]*model.Ring, error)
+	GetInstallationGroupsForRing(ringID string) ([]*model.InstallationGroup, error)
+	GetInstallationGroupByID(id string) (*model.InstallationGroup, error)
+	UpdateInstallationGroup(installationGroup *model.InstallationGroup) error
+	GetRingRelease(releaseID string) (*model.RingRelease, error)
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	LockRingInstallationGroup(installationGroupID, lockerID string) (bool, error)
+	UnlockRingInstallationGroup(installationGroupID string, lockerID string, force bool) (bool, error)
+}
+
+// driftProvisioner abstracts the provisioning operations required by the drift supervisor.
+type driftProvisioner interface {
+	GetInstallationGroupRelease(installationGroup *model.InstallationGroup) (*model.RingRelease, error)
+	ReleaseInstallationGroup(installationGroup *model.InstallationGroup, release *model.RingRelease) error
+}
+
+// DriftSupervisor periodically compares the provisioner group of every stable installation
+// group with the release it should run, records any drift on the installation group and,
+// when remediation is enabled, releases the installation group again.
+type DriftSupervisor struct {
+	store       driftStore
+	provisioner driftProvisioner
+	instanceID  string
+	interval    time.Duration
+	remediate   bool
+	lastCheck   time.Time
+	logger      log.FieldLogger
+}
+
+// NewDriftSupervisor creates a new DriftSupervisor.
+func NewDriftSupervisor(store driftStore, provisioner driftProvisioner, instanceID string, interval time.Duration, remediate bool, logger log.FieldLogger) *DriftSupervisor {
+	return &DriftSupervisor{
+		store:       store,
+		provisioner: provisioner,
+		instanceID:  instanceID,
+		interval:    interval,
+		remediate:   remediate,
+		logger:      logger.WithField("supervisor", "drift"),
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the drift supervisor.
+func (s *DriftSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down drift supervisor")
+}
+
+// Do checks the installation groups of the stable rings for drift, at most once per interval.
+func (s *DriftSupervisor) Do() error {
+	if time.Since(s.lastCheck) < s.interval {
+		return nil
+	}
+	s.lastCheck = time.Now()
+
+	rings, err := s.store.GetRings(&model.RingFilter{PerPage: model.AllPerPage})
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for rings")
+		return nil
+	}
+
+	for _, ring := range rings {
+		// Provisioner groups are expected to differ while their ring is being released.
+		if ring.State != model.RingStateStable {
+			continue
+		}
+
+		installationGroups, err := s.store.GetInstallationGroupsForRing(ring.ID)
+		if err != nil {
+			s.logger.WithError(err).Warnf("Failed to query for the installation groups of ring %s", ring.ID)
+			continue
+		}
+
+		for _, installationGroup := range installationGroups {
+			s.checkDrift(ring, installationGroup)
+		}
+	}
+
+	return nil
+}
+
+// checkDrift compares the provisioner group of the installation group with the release it
+// should run.
+func (s *DriftSupervisor) checkDrift(ring *model.Ring, installationGroup *model.InstallationGroup) {
+	logger := s.logger.WithFields(log.Fields{
+		"ring":              ring.ID,
+		"installationgroup": installationGroup.ID,
+	})
+
+	if installationGroup.State != model.InstallationGroupStable || installationGroup.ProvisionerGroupID == "" {
+		return
+	}
+	releaseID := installationGroup.RunningReleaseID(ring)
+	if releaseID == "" {
+		logger.Debug("The release of the installation group is unknown, skipping drift check")
+		return
+	}
+
+	lock := newInstallationGroupLock(installationGroup.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	installationGroup, err := s.store.GetInstallationGroupByID(installationGroup.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed installation group")
+		return
+	}
+	if installationGroup == nil || installationGroup.State != model.InstallationGroupStable {
+		return
+	}
+
+	release, err := s.store.GetRingRelease(releaseID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get the release of the installation group")
+		return
+	}
+	if release == nil {
+		logger.Warnf("Release %s of the installation group does not exist", releaseID)
+		return
+	}
+
+	deployed, err := s.provisioner.GetInstallationGroupRelease(installationGroup)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get the release of the provisioner group")
+		return
+	}
+	if deployed == nil {
+		logger.Debug("The provisioner target does not report the release of the provisioner group, skipping drift check")
+		return
+	}
+
+	drift := release.DriftFrom(deployed)
+	oldDrift := installationGroup.Drift
+	if drift != "" && drift != oldDrift {
+		logger.Warnf("Provisioner group %s drifted: %s", installationGroup.ProvisionerGroupID, drift)
+		s.sendWebhook(ring, installationGroup, "drift-detected", drift, logger)
+	}
+
+	if drift != "" && s.remediate {
+		logger.Infof("Releasing %s:%s to provisioner group %s again", release.Image, release.Version, installationGroup.ProvisionerGroupID)
+		if err = s.provisioner.ReleaseInstallationGroup(installationGroup, release); err != nil {
+			logger.WithError(err).Error("Failed to remediate provisioner group drift")
+		} else {
+			s.sendWebhook(ring, installationGroup, "drift-remediated", drift, logger)
+			drift = ""
+		}
+	}
+
+	installationGroup.Drift = drift
+	installationGroup.DriftCheckedAt = time.Now().UnixNano()
+	if err = s.store.UpdateInstallationGroup(installationGroup); err != nil {
+		logger.WithError(err).Error("Failed to record installation group drift")
+	}
+}
+
+func (s *DriftSupervisor) sendWebhook(ring *model.Ring, installationGroup *model.InstallationGroup, event, drift string, logger log.FieldLogger) {
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationGroup,
+		ID:        installationGroup.ID,
+		Name:      installationGroup.Name,
+		NewState:  installationGroup.State,
+		OldState:  installationGroup.State,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Event": event, "Drift": drift, "RingID": ring.ID},
+	}
+	if err := webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", event)); err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+}
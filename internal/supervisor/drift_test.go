@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/supervisor"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDriftProvisioner struct {
+	Deployed map[string]*model.RingRelease
+	Released []string
+}
+
+func (p *mockDriftProvisioner) GetInstallationGroupRelease(installationGroup *model.InstallationGroup) (*model.RingRelease, error) {
+	return p.Deployed[installationGroup.ProvisionerGroupID], nil
+}
+
+func (p *mockDriftProvisioner) ReleaseInstallationGroup(installationGroup *model.InstallationGroup, release *model.RingRelease) error {
+	p.Released = append(p.Released, installationGroup.ProvisionerGroupID)
+	p.Deployed[installationGroup.ProvisionerGroupID] = release
+	return nil
+}
+
+func TestDriftSupervisor(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "7.9.0"})
+	require.NoError(t, err)
+
+	ring := &model.Ring{Name: "ring", State: model.RingStateStable, ActiveReleaseID: release.ID, DesiredReleaseID: release.ID}
+	inSync := &model.InstallationGroup{Name: "in-sync", State: model.InstallationGroupStable, ProvisionerGroupID: "pg1"}
+	err = sqlStore.CreateRing(ring, inSync)
+	require.NoError(t, err)
+	drifted := &model.InstallationGroup{Name: "drifted", State: model.InstallationGroupStable, ProvisionerGroupID: "pg2"}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, drifted)
+	require.NoError(t, err)
+
+	// Targets using the http driver do not report the release of their groups.
+	unreported := &model.InstallationGroup{Name: "unreported", State: model.InstallationGroupStable, ProvisionerGroupID: "pg4", ProvisionerTarget: "service"}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, unreported)
+	require.NoError(t, err)
+
+	releasing := &model.Ring{Name: "releasing", State: model.RingStateReleaseInProgress, ActiveReleaseID: release.ID, DesiredReleaseID: release.ID}
+	releasingGroup := &model.InstallationGroup{Name: "releasing", State: model.InstallationGroupStable, ProvisionerGroupID: "pg3"}
+	err = sqlStore.CreateRing(releasing, releasingGroup)
+	require.NoError(t, err)
+
+	newProvisioner := func() *mockDriftProvisioner {
+		return &mockDriftProvisioner{Deployed: map[string]*model.RingRelease{
+			"pg1": {Image: "image", Version: "7.9.0"},
+			"pg2": {Image: "image", Version: "7.8.0"},
+			"pg3": {Image: "image", Version: "7.8.0"},
+		}}
+	}
+
+	requireDrift := func(t *testing.T, installationGroup *model.InstallationGroup, expectedDrift string) {
+		stored, getErr := sqlStore.GetInstallationGroupByID(installationGroup.ID)
+		require.NoError(t, getErr)
+		require.Equal(t, expectedDrift, stored.Drift, stored.Name)
+	}
+
+	t.Run("detect", func(t *testing.T) {
+		provisioner := newProvisioner()
+		err = supervisor.NewDriftSupervisor(sqlStore, provisioner, "instanceID", time.Hour, false, logger).Do()
+		require.NoError(t, err)
+
+		requireDrift(t, inSync, "")
+		requireDrift(t, drifted, "runs image:7.8.0 instead of image:7.9.0")
+		requireDrift(t, releasingGroup, "")
+		requireDrift(t, unreported, "")
+		require.Empty(t, provisioner.Released)
+
+		stored, err := sqlStore.GetInstallationGroupByID(inSync.ID)
+		require.NoError(t, err)
+		require.NotZero(t, stored.DriftCheckedAt)
+
+		stored, err = sqlStore.GetInstallationGroupByID(unreported.ID)
+		require.NoError(t, err)
+		require.Zero(t, stored.DriftCheckedAt)
+	})
+
+	t.Run("checked once per interval", func(t *testing.T) {
+		provisioner := newProvisioner()
+		driftSupervisor := supervisor.NewDriftSupervisor(sqlStore, provisioner, "instanceID", time.Hour, true, logger)
+		require.NoError(t, driftSupervisor.Do())
+		require.Equal(t, []string{"pg2"}, provisioner.Released)
+
+		provisioner.Deployed["pg2"] = &model.RingRelease{Image: "image", Version: "7.8.0"}
+		require.NoError(t, driftSupervisor.Do())
+		require.Equal(t, []string{"pg2"}, provisioner.Released)
+	})
+
+	t.Run("remediate", func(t *testing.T) {
+		provisioner := newProvisioner()
+		err = supervisor.NewDriftSupervisor(sqlStore, provisioner, "instanceID", time.Hour, true, logger).Do()
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"pg2"}, provisioner.Released)
+		requireDrift(t, drifted, "")
+	})
+}
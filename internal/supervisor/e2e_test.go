@@ -0,0 +1,225 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/elrond/internal/elrond"
+	"github.com/mattermost/elrond/internal/fakeprovisioner"
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/supervisor"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/stretchr/testify/require"
+)
+
+// e2eEnvironment runs the ring and installation group supervisors against a fake provisioner.
+type e2eEnvironment struct {
+	t           *testing.T
+	store       *store.SQLStore
+	provisioner *fakeprovisioner.Provisioner
+	supervisors supervisor.MultiDoer
+}
+
+func newE2EEnvironment(t *testing.T, unstableThreshold int) *e2eEnvironment {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	t.Cleanup(func() { store.CloseConnection(t, sqlStore) })
+
+	provisioner := fakeprovisioner.New(50*time.Millisecond, logger)
+	server := httptest.NewServer(provisioner)
+	t.Cleanup(server.Close)
+
+	elrondProvisioner := elrond.NewElrondProvisioner(elrond.ProvisioningParams{
+		ProvisionerGroupReleaseTimeout:    10,
+		ProvisionerGroupUnstableThreshold: unstableThreshold,
+		ProvisionerGroupPollInterval:      10 * time.Millisecond,
+	}, logger, server.URL, "", "", "")
+
+	return &e2eEnvironment{
+		t:           t,
+		store:       sqlStore,
+		provisioner: provisioner,
+		supervisors: supervisor.MultiDoer{
+			supervisor.NewRingSupervisor(sqlStore, elrondProvisioner, "instanceID", logger),
+			supervisor.NewInstallationGroupSupervisor(sqlStore, elrondProvisioner, "instanceID", logger),
+		},
+	}
+}
+
+// createRing creates a stable ring running the release, with an installation group for each
+// of the provisioner groups.
+func (e *e2eEnvironment) createRing(release *model.RingRelease, provisionerGroupIDs ...string) *model.Ring {
+	ring := &model.Ring{
+		Name:             "ring",
+		State:            model.RingStateStable,
+		ActiveReleaseID:  release.ID,
+		DesiredReleaseID: release.ID,
+	}
+	for i, provisionerGroupID := range provisionerGroupIDs {
+		err := e.provisioner.AddGroup(fakeprovisioner.Group{ID: provisionerGroupID, Name: provisionerGroupID, Image: release.Image, Version: release.Version, Installations: 2})
+		require.NoError(e.t, err)
+
+		installationGroup := &model.InstallationGroup{
+			Name:               provisionerGroupID,
+			ProvisionerGroupID: provisionerGroupID,
+			Priority:           i,
+			State:              model.InstallationGroupStable,
+			ActiveReleaseID:    release.ID,
+			DesiredReleaseID:   release.ID,
+		}
+		if i == 0 {
+			err = e.store.CreateRing(ring, installationGroup)
+		} else {
+			_, err = e.store.CreateRingInstallationGroup(ring.ID, installationGroup)
+		}
+		require.NoError(e.t, err)
+	}
+
+	return ring
+}
+
+func (e *e2eEnvironment) createRelease(version string) *model.RingRelease {
+	release, err := e.store.GetOrCreateRingRelease(&model.RingRelease{
+		Image:    "test-image",
+		Version:  version,
+		Force:    true,
+		CreateAt: time.Now().UnixNano(),
+	})
+	require.NoError(e.t, err)
+
+	return release
+}
+
+func (e *e2eEnvironment) releaseRing(ring *model.Ring, release *model.RingRelease) {
+	ring.State = model.RingStateReleasePending
+	ring.DesiredReleaseID = release.ID
+	err := e.store.UpdateRing(ring)
+	require.NoError(e.t, err)
+}
+
+// superviseUntil runs the supervisors until the ring reaches one of the given states.
+func (e *e2eEnvironment) superviseUntil(ringID string, states ...string) *model.Ring {
+	for i := 0; i < 50; i++ {
+		err := e.supervisors.Do()
+		require.NoError(e.t, err)
+
+		ring, err := e.store.GetRing(ringID)
+		require.NoError(e.t, err)
+		for _, state := range states {
+			if ring.State == state {
+				return ring
+			}
+		}
+	}
+
+	e.t.Fatalf("ring %s did not reach any of the states %v", ringID, states)
+	return nil
+}
+
+func (e *e2eEnvironment) requireInstallationGroup(provisionerGroupID, state, activeReleaseID string) *model.InstallationGroup {
+	installationGroup, err := e.store.GetInstallationGroupByName(provisionerGroupID)
+	require.NoError(e.t, err)
+	require.NotNil(e.t, installationGroup)
+	require.Equal(e.t, state, installationGroup.State, provisionerGroupID)
+	require.Equal(e.t, activeReleaseID, installationGroup.ActiveReleaseID, provisionerGroupID)
+
+	return installationGroup
+}
+
+func TestE2ERelease(t *testing.T) {
+	e := newE2EEnvironment(t, 0)
+	previous := e.createRelease("v1")
+	ring := e.createRing(previous, "group1", "group2")
+
+	release := e.createRelease("v2")
+	e.releaseRing(ring, release)
+
+	ring = e.superviseUntil(ring.ID, model.RingStateStable)
+	require.Equal(t, release.ID, ring.ActiveReleaseID)
+
+	for _, provisionerGroupID := range []string{"group1", "group2"} {
+		e.requireInstallationGroup(provisionerGroupID, model.InstallationGroupStable, release.ID)
+		require.Equal(t, "v2", e.provisioner.GetGroup(provisionerGroupID).Version)
+		require.Equal(t, 1, e.provisioner.Updates(provisionerGroupID))
+	}
+}
+
+func TestE2EReleaseUnstableInstallations(t *testing.T) {
+	t.Run("above the threshold", func(t *testing.T) {
+		e := newE2EEnvironment(t, 0)
+		previous := e.createRelease("v1")
+		ring := e.createRing(previous, "group1", "group2")
+		err := e.provisioner.AddGroup(fakeprovisioner.Group{ID: "group1", Name: "group1", Image: "test-image", Version: "v1", Installations: 2, UnstableInstallations: 1})
+		require.NoError(t, err)
+
+		e.releaseRing(ring, e.createRelease("v2"))
+
+		ring = e.superviseUntil(ring.ID, model.RingStateReleaseFailed)
+		require.Equal(t, previous.ID, ring.ActiveReleaseID)
+
+		installationGroup := e.requireInstallationGroup("group1", model.InstallationGroupReleaseFailed, previous.ID)
+		require.Contains(t, installationGroup.ReleaseFailure, "group1-installation-1")
+		e.requireInstallationGroup("group2", model.InstallationGroupReleaseFailed, previous.ID)
+		require.Equal(t, 0, e.provisioner.Updates("group2"))
+	})
+
+	t.Run("within the threshold", func(t *testing.T) {
+		e := newE2EEnvironment(t, 1)
+		previous := e.createRelease("v1")
+		ring := e.createRing(previous, "group1")
+		err := e.provisioner.AddGroup(fakeprovisioner.Group{ID: "group1", Name: "group1", Image: "test-image", Version: "v1", Installations: 2, UnstableInstallations: 1})
+		require.NoError(t, err)
+
+		release := e.createRelease("v2")
+		e.releaseRing(ring, release)
+
+		e.superviseUntil(ring.ID, model.RingStateStable)
+		e.requireInstallationGroup("group1", model.InstallationGroupStable, release.ID)
+	})
+}
+
+func TestE2EReleaseFailedUpdate(t *testing.T) {
+	e := newE2EEnvironment(t, 0)
+	previous := e.createRelease("v1")
+	ring := e.createRing(previous, "group1")
+	err := e.provisioner.AddGroup(fakeprovisioner.Group{ID: "group1", Name: "group1", Image: "test-image", Version: "v1", Installations: 2, FailUpdates: true})
+	require.NoError(t, err)
+
+	e.releaseRing(ring, e.createRelease("v2"))
+
+	e.superviseUntil(ring.ID, model.RingStateReleaseFailed)
+	installationGroup := e.requireInstallationGroup("group1", model.InstallationGroupReleaseFailed, previous.ID)
+	require.Contains(t, installationGroup.ReleaseFailure, "failed to patch provisioner group")
+	require.Equal(t, "v1", e.provisioner.GetGroup("group1").Version)
+}
+
+func TestE2ERollBack(t *testing.T) {
+	e := newE2EEnvironment(t, 0)
+	previous := e.createRelease("v1")
+	ring := e.createRing(previous, "group1", "group2")
+
+	release := e.createRelease("v2")
+	e.releaseRing(ring, release)
+	ring = e.superviseUntil(ring.ID, model.RingStateStable)
+
+	// Roll the ring back as if the release was blocked while in progress.
+	ring.State = model.RingStateReleaseRollbackRequested
+	ring.ActiveReleaseID = previous.ID
+	ring.DesiredReleaseID = previous.ID
+	err := e.store.UpdateRing(ring)
+	require.NoError(t, err)
+
+	e.superviseUntil(ring.ID, model.RingStateReleaseRollbackComplete)
+
+	for _, provisionerGroupID := range []string{"group1", "group2"} {
+		e.requireInstallationGroup(provisionerGroupID, model.InstallationGroupStable, previous.ID)
+		require.Equal(t, "v1", e.provisioner.GetGroup(provisionerGroupID).Version)
+		require.Equal(t, 2, e.provisioner.Updates(provisionerGroupID))
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// idempotencyStore abstracts the database operations required to expire idempotency records.
+type idempotencyStore interface {
+	DeleteIdempotencyRecordsCreatedBefore(createAt int64) (int64, error)
+}
+
+// IdempotencySupervisor removes idempotency records older than the retention period.
+type IdempotencySupervisor struct {
+	store     idempotencyStore
+	retention time.Duration
+	logger    log.FieldLogger
+}
+
+// NewIdempotencySupervisor creates a new IdempotencySupervisor.
+func NewIdempotencySupervisor(store idempotencyStore, retention time.Duration, logger log.FieldLogger) *IdempotencySupervisor {
+	return &IdempotencySupervisor{
+		store:     store,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the idempotency supervisor.
+func (s *IdempotencySupervisor) Shutdown() {
+	s.logger.Debug("Shutting down idempotency supervisor")
+}
+
+// Do removes expired idempotency records.
+func (s *IdempotencySupervisor) Do() error {
+	deleted, err := s.store.DeleteIdempotencyRecordsCreatedBefore(time.Now().Add(-s.retention).UnixMilli())
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to delete expired idempotency records")
+		return nil
+	}
+	if deleted > 0 {
+		s.logger.Debugf("Deleted %d expired idempotency records", deleted)
+	}
+
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 type installationGroupStore interface {
 	GetInstallationGroupsPendingWork() ([]*model.InstallationGroup, error)
 	GetInstallationGroupByID(id string) (*model.InstallationGroup, error)
+	GetInstallationGroupsForRing(ringID string) ([]*model.InstallationGroup, error)
 	UpdateInstallationGroup(installationGroup *model.InstallationGroup) error
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
 	GetRingFromInstallationGroupID(installationGroupID string) (*model.Ring, error)
@@ -26,7 +27,9 @@ type installationGroupStore interface {
 	GetInstallationGroupsLocked() ([]*model.InstallationGroup, error)
 	GetInstallationGroupsReleaseInProgress() ([]*model.InstallationGroup, error)
 	GetRingRelease(releaseID string) (*model.RingRelease, error)
+	IsReleaseBlocked(releaseID string) (bool, error)
 	GetRingsPendingWork() ([]*model.Ring, error)
+	GetRingDependencies() (map[string][]string, error)
 	UpdateRings(rings []*model.Ring) error
 }
 
@@ -131,6 +134,10 @@ func (s *InstallationGroupSupervisor) Supervise(installationGroup *model.Install
 	installationGroup.State = newState
 	if oldState == model.InstallationGroupReleaseRequested && (newState == model.InstallationGroupReleaseSoakingRequested || newState == model.InstallationGroupStable) {
 		installationGroup.ReleaseAt = time.Now().UnixNano()
+		installationGroup.ReleaseFailure = ""
+	}
+	if (oldState == model.InstallationGroupReleaseRequested || oldState == model.InstallationGroupReleaseSoakingRequested) && newState == model.InstallationGroupStable {
+		installationGroup.ActiveReleaseID = installationGroup.DesiredReleaseID
 	}
 
 	if err = s.store.UpdateInstallationGroup(installationGroup); err != nil {
@@ -138,19 +145,10 @@ func (s *InstallationGroupSupervisor) Supervise(installationGroup *model.Install
 		return
 	}
 
-	//Move rings to release-failed as soon as an IG release fails
+	//Move the ring to release-failed as soon as an IG release fails
 	if newState == model.InstallationGroupReleaseFailed || newState == model.InstallationGroupReleaseSoakingFailed {
 		logger.Info("Installation group release has failed, moving ring to failed state")
-		rings, getErr := s.store.GetRingsPendingWork()
-		if getErr != nil {
-			logger.WithError(getErr).Error("failed to get all rings pending work")
-			return
-		}
-		for _, ring := range rings {
-			ring.State = model.RingStateReleaseFailed
-		}
-
-		if err = s.store.UpdateRings(rings); err != nil {
+		if err = s.failRingRelease(installationGroup); err != nil {
 			logger.WithError(err).Error("failed to move rings to failed state")
 			return
 		}
@@ -163,6 +161,9 @@ func (s *InstallationGroupSupervisor) Supervise(installationGroup *model.Install
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
 	}
+	if newState == model.InstallationGroupReleaseFailed && installationGroup.ReleaseFailure != "" {
+		webhookPayload.ExtraData = map[string]string{"ReleaseFailure": installationGroup.ReleaseFailure}
+	}
 	if err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState)); err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
@@ -170,6 +171,35 @@ func (s *InstallationGroupSupervisor) Supervise(installationGroup *model.Install
 	logger.Debugf("Transitioned installation group from %s to %s", oldState, newState)
 }
 
+// failRingRelease moves the ring of the installation group to release-failed. Without ring
+// dependencies, the rings pending work are released after it by priority and fail with it.
+// With dependencies, rings releasing in parallel are left alone and the rings depending on
+// it fail once they check their dependencies.
+func (s *InstallationGroupSupervisor) failRingRelease(installationGroup *model.InstallationGroup) error {
+	ring, err := s.store.GetRingFromInstallationGroupID(installationGroup.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the ring of the installation group")
+	}
+
+	dependencies, err := s.store.GetRingDependencies()
+	if err != nil {
+		return errors.Wrap(err, "failed to get ring dependencies")
+	}
+
+	rings := []*model.Ring{ring}
+	if len(dependencies) == 0 {
+		rings, err = s.store.GetRingsPendingWork()
+		if err != nil {
+			return errors.Wrap(err, "failed to get all rings pending work")
+		}
+	}
+	for _, ring := range rings {
+		ring.State = model.RingStateReleaseFailed
+	}
+
+	return s.store.UpdateRings(rings)
+}
+
 // Do works with the given ring to transition it to a final state.
 func (s *InstallationGroupSupervisor) transitionInstallationGroup(installationGroup *model.InstallationGroup, logger log.FieldLogger) string {
 	switch installationGroup.State {
@@ -201,6 +231,41 @@ func (s *InstallationGroupSupervisor) checkInstallationGroupPending(installation
 		return model.InstallationGroupReleasePending
 	}
 
+	blocked, err := s.store.IsReleaseBlocked(ring.DesiredReleaseID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check if the ring release is blocked")
+		return model.InstallationGroupReleaseFailed
+	}
+	if blocked {
+		logger.Warnf("Release %s of ring %s is blocked, holding the installation group release...", ring.DesiredReleaseID, ring.ID)
+		return model.InstallationGroupReleasePending
+	}
+
+	if installationGroup.Paused {
+		logger.Infof("Installation group %s was paused while pending release, skipping it...", installationGroup.ID)
+		installationGroup.SkippedReleaseID = ring.DesiredReleaseID
+		if err = s.store.UpdateInstallationGroup(installationGroup); err != nil {
+			logger.WithError(err).Error("Failed to record skipped installation group")
+			return model.InstallationGroupReleaseFailed
+		}
+		return model.InstallationGroupStable
+	}
+
+	logger.Debug("Checking if installation groups of the ring with a lower priority are still releasing...")
+
+	ringInstallationGroups, err := s.store.GetInstallationGroupsForRing(ring.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query for the installation groups of the ring")
+		return model.InstallationGroupReleaseFailed
+	}
+
+	for _, ringInstallationGroup := range ringInstallationGroups {
+		if ringInstallationGroup.Priority < installationGroup.Priority && ringInstallationGroup.IsReleasing() {
+			logger.Debugf("Installation group %s has a lower priority and is releasing first...", ringInstallationGroup.ID)
+			return model.InstallationGroupReleasePending
+		}
+	}
+
 	logger.Debug("Checking if other Installation Groups are locked...")
 
 	installationGroupsLocked, err := s.store.GetInstallationGroupsLocked()
@@ -215,6 +280,19 @@ func (s *InstallationGroupSupervisor) checkInstallationGroupPending(installation
 		return model.InstallationGroupReleaseFailed
 	}
 
+	dependencies, err := s.store.GetRingDependencies()
+	if err != nil {
+		logger.WithError(err).Error("Failed to query for ring dependencies")
+		return model.InstallationGroupReleaseFailed
+	}
+
+	// Rings ordered by dependencies release in parallel, so only the installation groups
+	// of the same ring are released one at a time.
+	if len(dependencies) > 0 {
+		installationGroupsLocked = filterRingInstallationGroups(installationGroupsLocked, ringInstallationGroups)
+		installationGroupsReleaseInProgress = filterRingInstallationGroups(installationGroupsReleaseInProgress, ringInstallationGroups)
+	}
+
 	//The total installation groups locked at this time will be at least 1
 	if len(installationGroupsLocked) > 1 || len(installationGroupsReleaseInProgress) > 0 {
 		logger.Debug("Another installation group is under lock and being updated...")
@@ -246,6 +324,10 @@ func (s *InstallationGroupSupervisor) releaseInstallationGroup(installationGroup
 	err = s.provisioner.ReleaseInstallationGroup(installationGroup, release)
 	if err != nil {
 		logger.WithError(err).Error("Failed to release installation group")
+		installationGroup.ReleaseFailure = err.Error()
+		if err = s.store.UpdateInstallationGroup(installationGroup); err != nil {
+			logger.WithError(err).Error("Failed to record the installation group release failure")
+		}
 		return model.InstallationGroupReleaseFailed
 	}
 	logger.Infof("Finished releasing installation group %s", installationGroup.ID)
@@ -442,3 +524,19 @@ func (s *InstallationGroupSupervisor) checkPendingGroupStuck(group *model.Instal
 
 	return isStuck, nil
 }
+
+// filterRingInstallationGroups returns the installation groups that are among the
+// installation groups of a ring.
+func filterRingInstallationGroups(installationGroups, ringInstallationGroups []*model.InstallationGroup) []*model.InstallationGroup {
+	var filtered []*model.InstallationGroup
+	for _, installationGroup := range installationGroups {
+		for _, ringInstallationGroup := range ringInstallationGroups {
+			if installationGroup.ID == ringInstallationGroup.ID {
+				filtered = append(filtered, installationGroup)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/elrond/internal/store"
+	"github.com/mattermost/elrond/internal/supervisor"
+	"github.com/mattermost/elrond/internal/testlib"
+	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type mockInstallationGroupProvisioner struct{}
+
+func (p *mockInstallationGroupProvisioner) ReleaseInstallationGroup(_ *model.InstallationGroup, _ *model.RingRelease) error {
+	return nil
+}
+
+func (p *mockInstallationGroupProvisioner) SoakInstallationGroup(_ *model.InstallationGroup) error {
+	return nil
+}
+
+func (p *mockInstallationGroupProvisioner) AddGrafanaAnnotations(_ string, _ *model.Ring, _ *model.InstallationGroup, _ *model.RingRelease) error {
+	return nil
+}
+
+func TestInstallationGroupSupervisorPriority(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	supervisor := supervisor.NewInstallationGroupSupervisor(sqlStore, &mockInstallationGroupProvisioner{}, "instanceID", logger)
+
+	ring := &model.Ring{Name: "ring", State: model.RingStateReleaseRequested}
+	canary := &model.InstallationGroup{Name: "canary", State: model.InstallationGroupReleasePending, Priority: 1}
+	err := sqlStore.CreateRing(ring, canary)
+	require.NoError(t, err)
+	broad := &model.InstallationGroup{Name: "broad", State: model.InstallationGroupReleasePending, Priority: 2}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, broad)
+	require.NoError(t, err)
+
+	requireState := func(t *testing.T, installationGroup *model.InstallationGroup, expectedState string) {
+		stored, getErr := sqlStore.GetInstallationGroupByID(installationGroup.ID)
+		require.NoError(t, getErr)
+		require.Equal(t, expectedState, stored.State)
+	}
+
+	t.Run("higher priority waits", func(t *testing.T) {
+		supervisor.Supervise(broad)
+		requireState(t, broad, model.InstallationGroupReleasePending)
+	})
+
+	t.Run("lowest priority releases first", func(t *testing.T) {
+		supervisor.Supervise(canary)
+		requireState(t, canary, model.InstallationGroupReleaseRequested)
+	})
+
+	t.Run("pending work is ordered by priority", func(t *testing.T) {
+		installationGroups, err := sqlStore.GetInstallationGroupsPendingWork()
+		require.NoError(t, err)
+		require.Len(t, installationGroups, 2)
+		require.Equal(t, "canary", installationGroups[0].Name)
+	})
+}
+
+func TestInstallationGroupSupervisorPaused(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	supervisor := supervisor.NewInstallationGroupSupervisor(sqlStore, &mockInstallationGroupProvisioner{}, "instanceID", logger)
+
+	ring := &model.Ring{Name: "ring", State: model.RingStateReleaseInProgress, DesiredReleaseID: "release"}
+	installationGroup := &model.InstallationGroup{Name: "paused", State: model.InstallationGroupReleasePending, Paused: true}
+	err := sqlStore.CreateRing(ring, installationGroup)
+	require.NoError(t, err)
+
+	supervisor.Supervise(installationGroup)
+
+	installationGroup, err = sqlStore.GetInstallationGroupByID(installationGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.InstallationGroupStable, installationGroup.State)
+	require.Equal(t, "release", installationGroup.SkippedReleaseID)
+	require.Empty(t, installationGroup.ActiveReleaseID)
+}
+
+func TestInstallationGroupSupervisorCompletedRelease(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	supervisor := supervisor.NewInstallationGroupSupervisor(sqlStore, &mockInstallationGroupProvisioner{}, "instanceID", logger)
+
+	ring := &model.Ring{Name: "ring", State: model.RingStateReleaseInProgress, ActiveReleaseID: "previous", DesiredReleaseID: "release"}
+	installationGroup := &model.InstallationGroup{
+		Name:             "soaking",
+		State:            model.InstallationGroupReleaseSoakingRequested,
+		ActiveReleaseID:  "previous",
+		DesiredReleaseID: "release",
+	}
+	err := sqlStore.CreateRing(ring, installationGroup)
+	require.NoError(t, err)
+
+	supervisor.Supervise(installationGroup)
+
+	installationGroup, err = sqlStore.GetInstallationGroupByID(installationGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.InstallationGroupStable, installationGroup.State)
+	require.Equal(t, "release", installationGroup.ActiveReleaseID)
+	require.Equal(t, "release", installationGroup.DesiredReleaseID)
+}
+
+func TestInstallationGroupSupervisorBlockedRelease(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	supervisor := supervisor.NewInstallationGroupSupervisor(sqlStore, &mockInstallationGroupProvisioner{}, "instanceID", logger)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Version: "test-version", Image: "test-image"})
+	require.NoError(t, err)
+	err = sqlStore.CreateBlockedRelease(&model.BlockedRelease{Image: "test-image", Version: "test-version"})
+	require.NoError(t, err)
+
+	ring := &model.Ring{Name: "ring", State: model.RingStateReleaseInProgress, DesiredReleaseID: release.ID}
+	installationGroup := &model.InstallationGroup{Name: "held", State: model.InstallationGroupReleasePending}
+	err = sqlStore.CreateRing(ring, installationGroup)
+	require.NoError(t, err)
+
+	supervisor.Supervise(installationGroup)
+
+	installationGroup, err = sqlStore.GetInstallationGroupByID(installationGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.InstallationGroupReleasePending, installationGroup.State)
+}
+
+type mockFailingInstallationGroupProvisioner struct {
+	mockInstallationGroupProvisioner
+	err error
+}
+
+func (p *mockFailingInstallationGroupProvisioner) ReleaseInstallationGroup(_ *model.InstallationGroup, _ *model.RingRelease) error {
+	return p.err
+}
+
+func TestInstallationGroupSupervisorFailedRelease(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "2.0.0"})
+	require.NoError(t, err)
+
+	ring := &model.Ring{Name: "ring", State: model.RingStateReleaseInProgress, DesiredReleaseID: release.ID}
+	installationGroup := &model.InstallationGroup{
+		Name:             "unstable",
+		State:            model.InstallationGroupReleaseRequested,
+		DesiredReleaseID: release.ID,
+	}
+	err = sqlStore.CreateRing(ring, installationGroup)
+	require.NoError(t, err)
+
+	provisioner := &mockFailingInstallationGroupProvisioner{
+		err: errors.New("2 installations of provisioner group pg1 are unstable, above the threshold of 1: installation1, installation2"),
+	}
+	supervisor.NewInstallationGroupSupervisor(sqlStore, provisioner, "instanceID", logger).Supervise(installationGroup)
+
+	installationGroup, err = sqlStore.GetInstallationGroupByID(installationGroup.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.InstallationGroupReleaseFailed, installationGroup.State)
+	require.Equal(t, provisioner.err.Error(), installationGroup.ReleaseFailure)
+	require.Empty(t, installationGroup.ActiveReleaseID)
+
+	t.Run("successful release clears the failure", func(t *testing.T) {
+		installationGroup.State = model.InstallationGroupReleaseRequested
+		err = sqlStore.UpdateInstallationGroup(installationGroup)
+		require.NoError(t, err)
+
+		supervisor.NewInstallationGroupSupervisor(sqlStore, &mockInstallationGroupProvisioner{}, "instanceID", logger).Supervise(installationGroup)
+
+		installationGroup, err = sqlStore.GetInstallationGroupByID(installationGroup.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationGroupReleaseSoakingRequested, installationGroup.State)
+		require.Empty(t, installationGroup.ReleaseFailure)
+	})
+}
+
+func TestInstallationGroupSupervisorFailedReleaseOfIndependentRing(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Image: "image", Version: "2.0.0"})
+	require.NoError(t, err)
+
+	region1 := &model.Ring{Name: "region-1", State: model.RingStateReleaseInProgress, DesiredReleaseID: release.ID}
+	failing := &model.InstallationGroup{Name: "failing", State: model.InstallationGroupReleaseRequested, DesiredReleaseID: release.ID}
+	err = sqlStore.CreateRing(region1, failing)
+	require.NoError(t, err)
+	region2 := &model.Ring{Name: "region-2", State: model.RingStateReleaseInProgress, DesiredReleaseID: release.ID}
+	releasing := &model.InstallationGroup{Name: "releasing", State: model.InstallationGroupReleaseRequested, DesiredReleaseID: release.ID}
+	err = sqlStore.CreateRing(region2, releasing)
+	require.NoError(t, err)
+	global := &model.Ring{Name: "global", State: model.RingStateReleasePending, DesiredReleaseID: release.ID, DependsOn: []string{region1.ID, region2.ID}}
+	err = sqlStore.CreateRing(global, nil)
+	require.NoError(t, err)
+
+	provisioner := &mockFailingInstallationGroupProvisioner{err: errors.New("release failed")}
+	supervisor.NewInstallationGroupSupervisor(sqlStore, provisioner, "instanceID", logger).Supervise(failing)
+
+	failing, err = sqlStore.GetInstallationGroupByID(failing.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.InstallationGroupReleaseFailed, failing.State)
+
+	region1, err = sqlStore.GetRing(region1.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateReleaseFailed, region1.State)
+
+	region2, err = sqlStore.GetRing(region2.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateReleaseInProgress, region2.State)
+
+	global, err = sqlStore.GetRing(global.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateReleasePending, global.State)
+
+	supervisor.NewInstallationGroupSupervisor(sqlStore, &mockInstallationGroupProvisioner{}, "instanceID", logger).Supervise(releasing)
+
+	releasing, err = sqlStore.GetInstallationGroupByID(releasing.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.InstallationGroupReleaseSoakingRequested, releasing.State)
+}
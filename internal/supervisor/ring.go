@@ -10,6 +10,7 @@ import (
 	"github.com/mattermost/elrond/internal/webhook"
 
 	"github.com/mattermost/elrond/model"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -27,9 +28,14 @@ type ringStore interface {
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
 	GetRingsLocked() ([]*model.Ring, error)
 	GetRingsReleaseInProgress() ([]*model.Ring, error)
+	GetRingDependencies() (map[string][]string, error)
 	GetInstallationGroupsForRing(ringID string) ([]*model.InstallationGroup, error)
+	GetInstallationGroupByID(id string) (*model.InstallationGroup, error)
+	LockRingInstallationGroup(installationGroupID, lockerID string) (bool, error)
+	UnlockRingInstallationGroup(installationGroupID, lockerID string, force bool) (bool, error)
 	UpdateInstallationGroup(installationGroup *model.InstallationGroup) error
 	GetRingRelease(releaseID string) (*model.RingRelease, error)
+	IsReleaseBlocked(releaseID string) (bool, error)
 	GetRingsPendingWork() ([]*model.Ring, error)
 	UpdateRings(rings []*model.Ring) error
 }
@@ -41,6 +47,7 @@ type ringProvisioner interface {
 	ReleaseRing(ring *model.Ring) error
 	SoakRing(ring *model.Ring) error
 	RollBackRing(ring *model.Ring) error
+	RollBackInstallationGroup(installationGroup *model.InstallationGroup, release *model.RingRelease) error
 	DeleteRing(ring *model.Ring) error
 }
 
@@ -138,21 +145,29 @@ func (s *RingSupervisor) Supervise(ring *model.Ring) {
 		return
 	}
 
-	//Move pending rings to release-failed as soon as an ring release fails
+	//Move pending rings to release-failed as soon as an ring release fails. Rings ordered by
+	//dependencies fail once they check the ring they depend on instead.
 	if newState == model.RingStateReleaseFailed || newState == model.RingStateSoakingFailed {
-		logger.Info("Ring release has failed, moving pending rings to failed state")
-		rings, getErr := s.store.GetRingsPendingWork()
+		dependencies, getErr := s.store.GetRingDependencies()
 		if getErr != nil {
-			logger.WithError(getErr).Error("failed to get all rings pending work")
+			logger.WithError(getErr).Error("failed to get ring dependencies")
 			return
 		}
-		for _, ring := range rings {
-			ring.State = model.RingStateReleaseFailed
-		}
+		if len(dependencies) == 0 {
+			logger.Info("Ring release has failed, moving pending rings to failed state")
+			rings, getErr := s.store.GetRingsPendingWork()
+			if getErr != nil {
+				logger.WithError(getErr).Error("failed to get all rings pending work")
+				return
+			}
+			for _, ring := range rings {
+				ring.State = model.RingStateReleaseFailed
+			}
 
-		if err = s.store.UpdateRings(rings); err != nil {
-			logger.WithError(err).Error("failed to move rings to failed state")
-			return
+			if err = s.store.UpdateRings(rings); err != nil {
+				logger.WithError(err).Error("failed to move rings to failed state")
+				return
+			}
 		}
 	}
 
@@ -242,38 +257,80 @@ func (s *RingSupervisor) checkRingReleasePending(ring *model.Ring, logger log.Fi
 		return model.RingStateReleaseFailed
 	}
 
-	if !release.Force {
-		logger.Debug("Checking if other Rings are locked...")
+	blocked, err := s.store.IsReleaseBlocked(release.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check if the ring release is blocked")
+		return model.RingStateReleaseFailed
+	}
+	if blocked {
+		logger.Warnf("Release %s:%s is blocked, pausing the ring release", release.Image, release.Version)
+		return model.RingStateReleasePaused
+	}
 
-		ringsLocked, getLockedErr := s.store.GetRingsLocked()
-		if getLockedErr != nil {
-			logger.WithError(getLockedErr).Error("Failed to query for rings that are under lock")
+	if !release.Force {
+		dependencies, dependenciesErr := s.store.GetRingDependencies()
+		if dependenciesErr != nil {
+			logger.WithError(dependenciesErr).Error("Failed to query for ring dependencies")
 			return model.RingStateReleaseFailed
 		}
 
-		ringsReleaseInProgress, ringsReleaseInProgressErr := s.store.GetRingsReleaseInProgress()
-		if ringsReleaseInProgressErr != nil {
-			logger.WithError(ringsReleaseInProgressErr).Error("Failed to query for rings that are under release")
-			return model.RingStateReleaseFailed
-		}
+		// Once any ring declares a dependency, the dependency graph replaces the priorities
+		// and the global lock for every ring, so rings without dependencies release in
+		// parallel with any ring they do not depend on.
+		if len(dependencies) > 0 {
+			logger.Debugf("Checking ring %s dependencies", ring.ID)
+			for _, dependencyID := range dependencies[ring.ID] {
+				dependency, getErr := s.store.GetRing(dependencyID)
+				if getErr != nil {
+					logger.WithError(getErr).Error("Failed to get ring dependency")
+					return model.RingStateReleaseFailed
+				}
+				if dependency == nil {
+					continue
+				}
+				if dependency.State == model.RingStateStable && dependency.ActiveReleaseID == release.ID {
+					continue
+				}
+				if dependency.State != model.RingStateStable && !dependency.IsReleasing() {
+					logger.Errorf("Ring %s depends on ring %s, which is in state %s", ring.ID, dependency.ID, dependency.State)
+					return model.RingStateReleaseFailed
+				}
+				logger.Infof("Ring %s depends on ring %s, which has not completed release %s", ring.ID, dependency.ID, release.ID)
+				return model.RingStateReleasePending
+			}
+		} else {
+			logger.Debug("Checking if other Rings are locked...")
 
-		//The total rings locked at this time will be at least 1
-		if len(ringsLocked) > 1 || len(ringsReleaseInProgress) > 0 {
-			logger.Debug("Another ring is under lock and being updated...")
-			return model.InstallationGroupReleasePending
-		}
+			ringsLocked, getLockedErr := s.store.GetRingsLocked()
+			if getLockedErr != nil {
+				logger.WithError(getLockedErr).Error("Failed to query for rings that are under lock")
+				return model.RingStateReleaseFailed
+			}
 
-		logger.Debugf("Checking ring %s prioritization", ring.ID)
-		rings, ringsErr := s.store.GetUnlockedRingsPendingWork()
-		if ringsErr != nil {
-			logger.WithError(ringsErr).Error("Failed to get rings pending work for prioritization check")
-			return model.RingStateReleaseFailed
-		}
+			ringsReleaseInProgress, ringsReleaseInProgressErr := s.store.GetRingsReleaseInProgress()
+			if ringsReleaseInProgressErr != nil {
+				logger.WithError(ringsReleaseInProgressErr).Error("Failed to query for rings that are under release")
+				return model.RingStateReleaseFailed
+			}
 
-		for _, rg := range rings {
-			if rg.Priority < ring.Priority {
-				logger.Debugf("Ring %s is in priority", rg.ID)
-				return model.RingStateReleasePending
+			//The total rings locked at this time will be at least 1
+			if len(ringsLocked) > 1 || len(ringsReleaseInProgress) > 0 {
+				logger.Debug("Another ring is under lock and being updated...")
+				return model.InstallationGroupReleasePending
+			}
+
+			logger.Debugf("Checking ring %s prioritization", ring.ID)
+			rings, ringsErr := s.store.GetUnlockedRingsPendingWork()
+			if ringsErr != nil {
+				logger.WithError(ringsErr).Error("Failed to get rings pending work for prioritization check")
+				return model.RingStateReleaseFailed
+			}
+
+			for _, rg := range rings {
+				if rg.Priority < ring.Priority {
+					logger.Debugf("Ring %s is in priority", rg.ID)
+					return model.RingStateReleasePending
+				}
 			}
 		}
 	}
@@ -285,6 +342,23 @@ func (s *RingSupervisor) checkRingReleasePending(ring *model.Ring, logger log.Fi
 	}
 
 	for _, ig := range installationGroups {
+		if ig.Paused || release.ExcludesInstallationGroup(ig) {
+			logger.Infof("Skipping installation group %s, which is paused or excluded from the release", ig.Name)
+
+			ig.SkippedReleaseID = release.ID
+			if err = s.store.UpdateInstallationGroup(ig); err != nil {
+				logger.WithError(err).Error("failed to record skipped installation group")
+				return model.RingStateReleaseFailed
+			}
+			continue
+		}
+
+		// A retried release resumes from the installation groups that did not complete it.
+		if ig.ActiveReleaseID == release.ID && ring.ActiveReleaseID != release.ID {
+			logger.Infof("Skipping installation group %s, which already completed the release", ig.Name)
+			continue
+		}
+
 		newInstallationGroupState := model.InstallationGroupReleasePending
 
 		logger.Infof("Setting Installation group %s to %s state", ig.Name, newInstallationGroupState)
@@ -297,6 +371,8 @@ func (s *RingSupervisor) checkRingReleasePending(ring *model.Ring, logger log.Fi
 		logger.Infof("Setting Installation group %s to %s state", ig.Name, newInstallationGroupState)
 
 		ig.State = model.InstallationGroupReleasePending
+		ig.SkippedReleaseID = ""
+		ig.DesiredReleaseID = release.ID
 		if err = s.store.UpdateInstallationGroup(ig); err != nil {
 			logger.WithError(err).Error("failed to update installation group")
 			return model.RingStateReleaseFailed
@@ -382,10 +458,105 @@ func (s *RingSupervisor) rollbackRing(ring *model.Ring, logger log.FieldLogger)
 		return model.RingStateReleaseRollbackFailed
 	}
 
+	if err = s.rollBackInstallationGroups(ring, logger); err != nil {
+		logger.WithError(err).Error("Failed to rollback ring installation groups")
+		return model.RingStateReleaseRollbackFailed
+	}
+
 	logger.Infof("Finished rolling back ring %s", ring.ID)
 	return model.RingStateReleaseRollbackComplete
 }
 
+// rollBackInstallationGroups rolls the installation groups of the ring which moved off the
+// active release of the ring back to it. Installation groups still being released or locked
+// by another supervisor are left alone.
+func (s *RingSupervisor) rollBackInstallationGroups(ring *model.Ring, logger log.FieldLogger) error {
+	if ring.ActiveReleaseID == "" {
+		return nil
+	}
+
+	installationGroups, err := s.store.GetInstallationGroupsForRing(ring.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get ring installation groups")
+	}
+
+	var release *model.RingRelease
+	for _, installationGroup := range installationGroups {
+		if !needsRollback(ring, installationGroup, logger) {
+			continue
+		}
+
+		if release == nil {
+			release, err = s.store.GetRingRelease(ring.ActiveReleaseID)
+			if err != nil {
+				return errors.Wrap(err, "failed to get the active release of the ring")
+			}
+			if release == nil {
+				return errors.Errorf("active release %s of the ring does not exist", ring.ActiveReleaseID)
+			}
+		}
+
+		if err = s.rollBackInstallationGroup(ring, installationGroup.ID, release, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rollBackInstallationGroup rolls the installation group back to the release under its lock.
+func (s *RingSupervisor) rollBackInstallationGroup(ring *model.Ring, installationGroupID string, release *model.RingRelease, logger log.FieldLogger) error {
+	logger = logger.WithField("installationgroup", installationGroupID)
+
+	lock := newInstallationGroupLock(installationGroupID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		logger.Warn("Installation group is locked, skipping its rollback")
+		return nil
+	}
+	defer lock.Unlock()
+
+	// The installation group may have changed before it was locked.
+	installationGroup, err := s.store.GetInstallationGroupByID(installationGroupID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get refreshed installation group")
+	}
+	if installationGroup == nil || !needsRollback(ring, installationGroup, logger) {
+		return nil
+	}
+
+	if err = s.provisioner.RollBackInstallationGroup(installationGroup, release); err != nil {
+		return errors.Wrapf(err, "failed to rollback installation group %s", installationGroup.Name)
+	}
+
+	installationGroup.ActiveReleaseID = ring.ActiveReleaseID
+	installationGroup.DesiredReleaseID = ring.ActiveReleaseID
+	installationGroup.State = model.InstallationGroupStable
+	installationGroup.ReleaseFailure = ""
+	if err = s.store.UpdateInstallationGroup(installationGroup); err != nil {
+		return errors.Wrapf(err, "failed to record rollback of installation group %s", installationGroup.Name)
+	}
+	logger.Infof("Rolled back installation group %s to %s:%s", installationGroup.Name, release.Image, release.Version)
+
+	return nil
+}
+
+// needsRollback returns whether the installation group moved off the active release of the
+// ring and is not being released.
+func needsRollback(ring *model.Ring, installationGroup *model.InstallationGroup, logger log.FieldLogger) bool {
+	if installationGroup.ActiveReleaseID == "" {
+		return false
+	}
+	if installationGroup.ActiveReleaseID == ring.ActiveReleaseID && installationGroup.DesiredReleaseID == ring.ActiveReleaseID {
+		return false
+	}
+	if installationGroup.State != model.InstallationGroupStable && installationGroup.State != model.InstallationGroupReleaseFailed && installationGroup.State != model.InstallationGroupReleaseSoakingFailed {
+		logger.Warnf("Installation group %s is in state %s, skipping its rollback", installationGroup.Name, installationGroup.State)
+		return false
+	}
+
+	return true
+}
+
 func (s *RingSupervisor) deleteRing(ring *model.Ring, logger log.FieldLogger) string {
 	err := s.provisioner.DeleteRing(ring)
 	if err != nil {
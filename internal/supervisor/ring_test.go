@@ -48,6 +48,14 @@ func (s *mockRingStore) GetRingsPendingWork() ([]*model.Ring, error) {
 	return s.Rings, nil
 }
 
+func (s *mockRingStore) GetRingDependencies() (map[string][]string, error) {
+	return nil, nil
+}
+
+func (s *mockRingStore) IsReleaseBlocked(_ string) (bool, error) {
+	return false, nil
+}
+
 func (s *mockRingStore) UpdateRing(_ *model.Ring) error {
 	s.UpdateRingCalls++
 	return nil
@@ -93,6 +101,18 @@ func (s *mockRingStore) UpdateInstallationGroup(_ *model.InstallationGroup) erro
 	return nil
 }
 
+func (s *mockRingStore) GetInstallationGroupByID(_ string) (*model.InstallationGroup, error) {
+	return nil, nil
+}
+
+func (s *mockRingStore) LockRingInstallationGroup(_, _ string) (bool, error) {
+	return true, nil
+}
+
+func (s *mockRingStore) UnlockRingInstallationGroup(_, _ string, _ bool) (bool, error) {
+	return true, nil
+}
+
 func (s *mockRingStore) GetRingRelease(_ string) (*model.RingRelease, error) {
 	return nil, nil
 }
@@ -119,6 +139,10 @@ func (p *mockRingProvisioner) RollBackRing(_ *model.Ring) error {
 	return nil
 }
 
+func (p *mockRingProvisioner) RollBackInstallationGroup(_ *model.InstallationGroup, _ *model.RingRelease) error {
+	return nil
+}
+
 func (p *mockRingProvisioner) DeleteRing(_ *model.Ring) error {
 	return nil
 }
@@ -266,3 +290,274 @@ func TestRingSupervisorSupervise(t *testing.T) {
 		require.Equal(t, model.RingStateDeletionRequested, Ring.State)
 	})
 }
+
+func TestRingSupervisorSkipInstallationGroups(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	supervisor := supervisor.NewRingSupervisor(sqlStore, &mockRingProvisioner{}, "instanceID", logger)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{
+		Version:                    "test-version",
+		Image:                      "test-image",
+		Force:                      true,
+		ExcludedInstallationGroups: []string{"excluded"},
+		CreateAt:                   time.Now().UnixNano(),
+	})
+	require.NoError(t, err)
+
+	ring := &model.Ring{
+		Name:             "ring",
+		State:            model.RingStateReleasePending,
+		DesiredReleaseID: release.ID,
+	}
+	released := &model.InstallationGroup{Name: "released", State: model.InstallationGroupStable, SkippedReleaseID: "previous"}
+	err = sqlStore.CreateRing(ring, released)
+	require.NoError(t, err)
+	excluded := &model.InstallationGroup{Name: "excluded", State: model.InstallationGroupStable}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, excluded)
+	require.NoError(t, err)
+	paused := &model.InstallationGroup{Name: "paused", State: model.InstallationGroupStable, Paused: true}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, paused)
+	require.NoError(t, err)
+
+	supervisor.Supervise(ring)
+
+	ring, err = sqlStore.GetRing(ring.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateReleaseRequested, ring.State)
+
+	for _, tc := range []struct {
+		installationGroup *model.InstallationGroup
+		expectedState     string
+		expectedSkipped   string
+		expectedDesired   string
+	}{
+		{released, model.InstallationGroupReleasePending, "", release.ID},
+		{excluded, model.InstallationGroupStable, release.ID, ""},
+		{paused, model.InstallationGroupStable, release.ID, ""},
+	} {
+		installationGroup, err := sqlStore.GetInstallationGroupByID(tc.installationGroup.ID)
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedState, installationGroup.State, installationGroup.Name)
+		require.Equal(t, tc.expectedSkipped, installationGroup.SkippedReleaseID, installationGroup.Name)
+		require.Equal(t, tc.expectedDesired, installationGroup.DesiredReleaseID, installationGroup.Name)
+	}
+}
+
+func TestRingSupervisorResumeRelease(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	supervisor := supervisor.NewRingSupervisor(sqlStore, &mockRingProvisioner{}, "instanceID", logger)
+
+	previous, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Version: "previous-version", Image: "test-image"})
+	require.NoError(t, err)
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Version: "test-version", Image: "test-image"})
+	require.NoError(t, err)
+
+	ring := &model.Ring{
+		Name:             "ring",
+		State:            model.RingStateReleasePending,
+		ActiveReleaseID:  previous.ID,
+		DesiredReleaseID: release.ID,
+	}
+	completed := &model.InstallationGroup{Name: "completed", State: model.InstallationGroupStable, Priority: 1, ActiveReleaseID: release.ID, DesiredReleaseID: release.ID}
+	err = sqlStore.CreateRing(ring, completed)
+	require.NoError(t, err)
+	failed := &model.InstallationGroup{Name: "failed", State: model.InstallationGroupReleaseFailed, Priority: 2, ActiveReleaseID: previous.ID, DesiredReleaseID: release.ID}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, failed)
+	require.NoError(t, err)
+
+	supervisor.Supervise(ring)
+
+	ring, err = sqlStore.GetRing(ring.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateReleaseRequested, ring.State)
+
+	completed, err = sqlStore.GetInstallationGroupByID(completed.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.InstallationGroupStable, completed.State)
+
+	failed, err = sqlStore.GetInstallationGroupByID(failed.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.InstallationGroupReleasePending, failed.State)
+}
+
+func TestRingSupervisorDependencies(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	supervisor := supervisor.NewRingSupervisor(sqlStore, &mockRingProvisioner{}, "instanceID", logger)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{
+		Version:  "test-version",
+		Image:    "test-image",
+		CreateAt: time.Now().UnixNano(),
+	})
+	require.NoError(t, err)
+
+	region1 := &model.Ring{Name: "region-1", Priority: 1, State: model.RingStateReleaseInProgress, DesiredReleaseID: release.ID}
+	err = sqlStore.CreateRing(region1, nil)
+	require.NoError(t, err)
+	region2 := &model.Ring{Name: "region-2", Priority: 2, State: model.RingStateReleasePending, DesiredReleaseID: release.ID}
+	err = sqlStore.CreateRing(region2, nil)
+	require.NoError(t, err)
+	global := &model.Ring{Name: "global", Priority: 3, State: model.RingStateReleasePending, DesiredReleaseID: release.ID, DependsOn: []string{region1.ID, region2.ID}}
+	err = sqlStore.CreateRing(global, nil)
+	require.NoError(t, err)
+
+	requireGlobalState := func(t *testing.T, expectedState string) {
+		global, err = sqlStore.GetRing(global.ID)
+		require.NoError(t, err)
+		require.Equal(t, expectedState, global.State)
+	}
+
+	t.Run("rings without dependencies skip priorities", func(t *testing.T) {
+		supervisor.Supervise(region2)
+
+		region2, err = sqlStore.GetRing(region2.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.RingStateReleaseRequested, region2.State)
+	})
+
+	t.Run("releasing dependency", func(t *testing.T) {
+		supervisor.Supervise(global)
+		requireGlobalState(t, model.RingStateReleasePending)
+	})
+
+	t.Run("dependency stable on a previous release", func(t *testing.T) {
+		region1.State = model.RingStateStable
+		region1.ActiveReleaseID = "previous"
+		err = sqlStore.UpdateRing(region1)
+		require.NoError(t, err)
+		region2.State = model.RingStateStable
+		region2.ActiveReleaseID = release.ID
+		err = sqlStore.UpdateRing(region2)
+		require.NoError(t, err)
+
+		supervisor.Supervise(global)
+		requireGlobalState(t, model.RingStateReleasePending)
+	})
+
+	t.Run("dependencies completed the release", func(t *testing.T) {
+		region1.ActiveReleaseID = release.ID
+		err = sqlStore.UpdateRing(region1)
+		require.NoError(t, err)
+
+		supervisor.Supervise(global)
+		requireGlobalState(t, model.RingStateReleaseRequested)
+	})
+
+	for _, state := range []string{model.RingStateReleaseFailed, model.RingStateReleaseRollbackComplete} {
+		t.Run("dependency in "+state, func(t *testing.T) {
+			region1.State = state
+			err = sqlStore.UpdateRing(region1)
+			require.NoError(t, err)
+			global.State = model.RingStateReleasePending
+			err = sqlStore.UpdateRing(global)
+			require.NoError(t, err)
+
+			supervisor.Supervise(global)
+			requireGlobalState(t, model.RingStateReleaseFailed)
+
+			region2, err = sqlStore.GetRing(region2.ID)
+			require.NoError(t, err)
+			require.Equal(t, model.RingStateStable, region2.State)
+		})
+	}
+}
+
+func TestRingSupervisorBlockedRelease(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	supervisor := supervisor.NewRingSupervisor(sqlStore, &mockRingProvisioner{}, "instanceID", logger)
+
+	release, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{
+		Version:  "test-version",
+		Image:    "test-image",
+		Force:    true,
+		CreateAt: time.Now().UnixNano(),
+	})
+	require.NoError(t, err)
+	err = sqlStore.CreateBlockedRelease(&model.BlockedRelease{Image: "test-image", Version: "test-version"})
+	require.NoError(t, err)
+
+	ring := &model.Ring{Name: "ring", State: model.RingStateReleasePending, DesiredReleaseID: release.ID}
+	err = sqlStore.CreateRing(ring, nil)
+	require.NoError(t, err)
+
+	supervisor.Supervise(ring)
+
+	ring, err = sqlStore.GetRing(ring.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateReleasePaused, ring.State)
+}
+
+type mockRollBackRingProvisioner struct {
+	mockRingProvisioner
+	RolledBack []string
+}
+
+func (p *mockRollBackRingProvisioner) RollBackInstallationGroup(installationGroup *model.InstallationGroup, release *model.RingRelease) error {
+	p.RolledBack = append(p.RolledBack, installationGroup.Name+":"+release.Version)
+	return nil
+}
+
+func TestRingSupervisorRollBackInstallationGroups(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+	provisioner := &mockRollBackRingProvisioner{}
+	supervisor := supervisor.NewRingSupervisor(sqlStore, provisioner, "instanceID", logger)
+
+	previous, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Version: "previous", Image: "test-image", CreateAt: time.Now().UnixNano()})
+	require.NoError(t, err)
+	blocked, err := sqlStore.GetOrCreateRingRelease(&model.RingRelease{Version: "blocked", Image: "test-image", CreateAt: time.Now().UnixNano()})
+	require.NoError(t, err)
+
+	ring := &model.Ring{
+		Name:             "ring",
+		State:            model.RingStateReleaseRollbackRequested,
+		ActiveReleaseID:  previous.ID,
+		DesiredReleaseID: previous.ID,
+	}
+	released := &model.InstallationGroup{Name: "released", State: model.InstallationGroupStable, ActiveReleaseID: blocked.ID, DesiredReleaseID: blocked.ID}
+	err = sqlStore.CreateRing(ring, released)
+	require.NoError(t, err)
+	failed := &model.InstallationGroup{Name: "failed", State: model.InstallationGroupReleaseFailed, ActiveReleaseID: previous.ID, DesiredReleaseID: blocked.ID, ReleaseFailure: "unstable"}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, failed)
+	require.NoError(t, err)
+	untouched := &model.InstallationGroup{Name: "untouched", State: model.InstallationGroupStable, ActiveReleaseID: previous.ID, DesiredReleaseID: previous.ID}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, untouched)
+	require.NoError(t, err)
+	locked := &model.InstallationGroup{Name: "locked", State: model.InstallationGroupStable, ActiveReleaseID: blocked.ID, DesiredReleaseID: blocked.ID}
+	_, err = sqlStore.CreateRingInstallationGroup(ring.ID, locked)
+	require.NoError(t, err)
+	lockAcquired, err := sqlStore.LockRingInstallationGroup(locked.ID, "drift-supervisor")
+	require.NoError(t, err)
+	require.True(t, lockAcquired)
+
+	supervisor.Supervise(ring)
+
+	ring, err = sqlStore.GetRing(ring.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.RingStateReleaseRollbackComplete, ring.State)
+	require.ElementsMatch(t, []string{"released:previous", "failed:previous"}, provisioner.RolledBack)
+
+	for _, installationGroup := range []*model.InstallationGroup{released, failed, untouched} {
+		installationGroup, err = sqlStore.GetInstallationGroupByID(installationGroup.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationGroupStable, installationGroup.State, installationGroup.Name)
+		require.Equal(t, previous.ID, installationGroup.ActiveReleaseID, installationGroup.Name)
+		require.Equal(t, previous.ID, installationGroup.DesiredReleaseID, installationGroup.Name)
+		require.Empty(t, installationGroup.ReleaseFailure, installationGroup.Name)
+	}
+
+	// Installation groups locked by another supervisor are left alone.
+	locked, err = sqlStore.GetInstallationGroupByID(locked.ID)
+	require.NoError(t, err)
+	require.Equal(t, blocked.ID, locked.ActiveReleaseID)
+	require.Equal(t, blocked.ID, locked.DesiredReleaseID)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RoleViewer can read rings, installation groups, releases and webhooks.
+	RoleViewer = "viewer"
+	// RoleReleaser can additionally create, update and release rings and installation groups.
+	RoleReleaser = "releaser"
+	// RoleAdmin can additionally delete rings and manage webhooks, security locks and API tokens.
+	RoleAdmin = "admin"
+)
+
+// AllRoles is a list of all roles an API caller may be granted, from least to most privileged.
+var AllRoles = []string{
+	RoleViewer,
+	RoleReleaser,
+	RoleAdmin,
+}
+
+// IsValidRole returns whether the given role is known.
+func IsValidRole(role string) bool {
+	return roleRank(role) > 0
+}
+
+// RoleAllows returns whether the given role grants at least the privileges of the required role.
+func RoleAllows(role, required string) bool {
+	return IsValidRole(role) && roleRank(role) >= roleRank(required)
+}
+
+func roleRank(role string) int {
+	for i, r := range AllRoles {
+		if r == role {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// APIToken represents a static token used to authenticate against the elrond API.
+//
+// Only a hash of the token is stored; the token itself is returned once on creation.
+type APIToken struct {
+	ID        string
+	Name      string
+	Role      string
+	TokenHash string `json:"-"`
+	CreatedBy string
+	CreateAt  int64
+	DeleteAt  int64
+}
+
+// APITokenFilter describes the parameters used to constrain a set of API tokens.
+type APITokenFilter struct {
+	Page           int
+	PerPage        int
+	IncludeDeleted bool
+}
+
+// CreateAPITokenResponse is the response to an API token creation, carrying the
+// token value which cannot be retrieved afterwards.
+type CreateAPITokenResponse struct {
+	*APIToken
+	Token string
+}
+
+// IsDeleted returns whether the API token was marked as deleted or not.
+func (t *APIToken) IsDeleted() bool {
+	return t.DeleteAt != 0
+}
+
+// CreateAPITokenRequest specifies the parameters for a new API token.
+type CreateAPITokenRequest struct {
+	Name string
+	Role string
+}
+
+// Validate validates the values of an API token create request.
+func (request *CreateAPITokenRequest) Validate() error {
+	if request.Name == "" {
+		return errors.New("must specify name")
+	}
+	if !IsValidRole(request.Role) {
+		return errors.Errorf("invalid role %q, must be one of %v", request.Role, AllRoles)
+	}
+
+	return nil
+}
+
+// NewCreateAPITokenRequestFromReader will create a CreateAPITokenRequest from an io.Reader with JSON data.
+func NewCreateAPITokenRequestFromReader(reader io.Reader) (*CreateAPITokenRequest, error) {
+	var createAPITokenRequest CreateAPITokenRequest
+	err := json.NewDecoder(reader).Decode(&createAPITokenRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode create API token request")
+	}
+
+	if err = createAPITokenRequest.Validate(); err != nil {
+		return nil, errors.Wrap(err, "create API token request failed validation")
+	}
+
+	return &createAPITokenRequest, nil
+}
+
+// GetAPITokensRequest describes the parameters to request a list of API tokens.
+type GetAPITokensRequest struct {
+	Page           int
+	PerPage        int
+	IncludeDeleted bool
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetAPITokensRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("page", strconv.Itoa(request.Page))
+	q.Add("per_page", strconv.Itoa(request.PerPage))
+	if request.IncludeDeleted {
+		q.Add("include_deleted", "true")
+	}
+	u.RawQuery = q.Encode()
+}
+
+// APITokensFromReader decodes a json-encoded list of API tokens from the given io.Reader.
+func APITokensFromReader(reader io.Reader) ([]*APIToken, error) {
+	apiTokens := []*APIToken{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&apiTokens)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return apiTokens, nil
+}
+
+// CreateAPITokenResponseFromReader decodes a json-encoded API token creation response from the given io.Reader.
+func CreateAPITokenResponseFromReader(reader io.Reader) (*CreateAPITokenResponse, error) {
+	response := CreateAPITokenResponse{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&response)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleAllows(t *testing.T) {
+	testCases := []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{RoleViewer, RoleViewer, true},
+		{RoleViewer, RoleReleaser, false},
+		{RoleViewer, RoleAdmin, false},
+		{RoleReleaser, RoleViewer, true},
+		{RoleReleaser, RoleReleaser, true},
+		{RoleReleaser, RoleAdmin, false},
+		{RoleAdmin, RoleViewer, true},
+		{RoleAdmin, RoleAdmin, true},
+		{"", RoleViewer, false},
+		{"superuser", RoleViewer, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.role+"-"+tc.required, func(t *testing.T) {
+			require.Equal(t, tc.expected, RoleAllows(tc.role, tc.required))
+		})
+	}
+}
+
+func TestNewCreateAPITokenRequestFromReader(t *testing.T) {
+	t.Run("valid request", func(t *testing.T) {
+		request, err := NewCreateAPITokenRequestFromReader(strings.NewReader(`{"Name":"ci","Role":"releaser"}`))
+		require.NoError(t, err)
+		require.Equal(t, &CreateAPITokenRequest{Name: "ci", Role: RoleReleaser}, request)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		_, err := NewCreateAPITokenRequestFromReader(strings.NewReader(`{"Role":"releaser"}`))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		_, err := NewCreateAPITokenRequestFromReader(strings.NewReader(`{"Name":"ci","Role":"root"}`))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := NewCreateAPITokenRequestFromReader(strings.NewReader(`{`))
+		require.Error(t, err)
+	})
+}
+
+func TestAPITokenJSONOmitsHash(t *testing.T) {
+	tokens, err := APITokensFromReader(strings.NewReader(`[{"ID":"id","TokenHash":"secret"}]`))
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Empty(t, tokens[0].TokenHash)
+}
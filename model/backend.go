@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	cmodel "github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	// BackendReleaseInProgress is a backend release still being deployed.
+	BackendReleaseInProgress = "in-progress"
+	// BackendReleaseComplete is a backend release fully deployed.
+	BackendReleaseComplete = "complete"
+	// BackendReleaseFailed is a backend release that failed to deploy.
+	BackendReleaseFailed = "failed"
+)
+
+// BackendRelease is the release an HTTP deployment backend is asked to deploy to one of its
+// groups.
+type BackendRelease struct {
+	InstallationGroupID   string           `json:"installationGroupID"`
+	InstallationGroupName string           `json:"installationGroupName"`
+	ProvisionerGroupID    string           `json:"provisionerGroupID"`
+	ReleaseID             string           `json:"releaseID"`
+	Image                 string           `json:"image"`
+	Version               string           `json:"version"`
+	EnvVariables          cmodel.EnvVarMap `json:"envVariables,omitempty"`
+	// Rollback is set when the release restores the release the group ran before.
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// BackendStatus is the progress of the last release deployed by a backend to one of its
+// groups.
+type BackendStatus struct {
+	// ReleaseID is the release the state is about, empty for backends that do not track
+	// releases.
+	ReleaseID string `json:"releaseID,omitempty"`
+	State     string `json:"state"`
+	// Message describes the state, such as why the release failed.
+	Message string `json:"message,omitempty"`
+}
+
+// NewBackendRelease creates the backend release of the ring release to the installation
+// group.
+func NewBackendRelease(installationGroup *InstallationGroup, release *RingRelease) *BackendRelease {
+	return &BackendRelease{
+		InstallationGroupID:   installationGroup.ID,
+		InstallationGroupName: installationGroup.Name,
+		ProvisionerGroupID:    installationGroup.ProvisionerGroupID,
+		ReleaseID:             release.ID,
+		Image:                 release.Image,
+		Version:               release.Version,
+		EnvVariables:          release.EnvVariables,
+	}
+}
+
+// Validate validates the backend status.
+func (s *BackendStatus) Validate() error {
+	switch s.State {
+	case BackendReleaseInProgress, BackendReleaseComplete, BackendReleaseFailed:
+		return nil
+	}
+
+	return errors.Errorf("unknown backend release state %q", s.State)
+}
+
+// BackendReleaseFromReader decodes a json-encoded backend release from the given io.Reader.
+func BackendReleaseFromReader(reader io.Reader) (*BackendRelease, error) {
+	backendRelease := BackendRelease{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&backendRelease)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &backendRelease, nil
+}
+
+// BackendStatusFromReader decodes a json-encoded backend status from the given io.Reader.
+func BackendStatusFromReader(reader io.Reader) (*BackendStatus, error) {
+	backendStatus := BackendStatus{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&backendStatus)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if err = backendStatus.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &backendStatus, nil
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendStatusFromReader(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		status, err := BackendStatusFromReader(strings.NewReader(`{"state":"failed","message":"unstable"}`))
+		require.NoError(t, err)
+		require.Equal(t, &BackendStatus{State: BackendReleaseFailed, Message: "unstable"}, status)
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		_, err := BackendStatusFromReader(strings.NewReader(`{"state":"done"}`))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := BackendStatusFromReader(strings.NewReader(`{`))
+		require.Error(t, err)
+	})
+}
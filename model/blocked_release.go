@@ -0,0 +1,118 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BlockedReleaseActionPause pauses the releases of a blocked image and version that are
+	// pending, and stops releasing it to further installation groups of the rings it is
+	// being released to.
+	BlockedReleaseActionPause = "pause"
+	// BlockedReleaseActionRollback cancels the releases of a blocked image and version that
+	// are pending, and rolls back the rings it is being released to.
+	BlockedReleaseActionRollback = "rollback"
+)
+
+// BlockedRelease is an image and version that must not be released.
+type BlockedRelease struct {
+	ID        string
+	Image     string
+	Version   string
+	Reason    string
+	CreatedBy string
+	CreateAt  int64
+}
+
+// Blocks returns whether the blocked release matches the given ring release.
+func (b *BlockedRelease) Blocks(release *RingRelease) bool {
+	return release != nil && b.Image == release.Image && b.Version == release.Version
+}
+
+// BlockReleaseRequest specifies the image and version to add to the blocklist.
+type BlockReleaseRequest struct {
+	Image   string
+	Version string
+	Reason  string `json:",omitempty"`
+}
+
+// UnblockReleaseRequest specifies the image and version to remove from the blocklist.
+type UnblockReleaseRequest struct {
+	Image   string
+	Version string
+}
+
+// IsValidBlockedReleaseAction returns whether the action is a known blocked release action.
+func IsValidBlockedReleaseAction(action string) bool {
+	return action == BlockedReleaseActionPause || action == BlockedReleaseActionRollback
+}
+
+// Validate validates the values of a block release request.
+func (request *BlockReleaseRequest) Validate() error {
+	if request.Image == "" {
+		return errors.New("Image cannot be empty")
+	}
+	if request.Version == "" {
+		return errors.New("Version cannot be empty")
+	}
+
+	return nil
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *UnblockReleaseRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("image", request.Image)
+	q.Add("version", request.Version)
+	u.RawQuery = q.Encode()
+}
+
+// NewBlockReleaseRequestFromReader will create a BlockReleaseRequest from an io.Reader
+// with JSON data.
+func NewBlockReleaseRequestFromReader(reader io.Reader) (*BlockReleaseRequest, error) {
+	var blockReleaseRequest BlockReleaseRequest
+	err := json.NewDecoder(reader).Decode(&blockReleaseRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode block release request")
+	}
+
+	if err = blockReleaseRequest.Validate(); err != nil {
+		return nil, errors.Wrap(err, "block release request failed validation")
+	}
+
+	return &blockReleaseRequest, nil
+}
+
+// BlockedReleaseFromReader decodes a json-encoded blocked release from the given io.Reader.
+func BlockedReleaseFromReader(reader io.Reader) (*BlockedRelease, error) {
+	blockedRelease := BlockedRelease{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&blockedRelease)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &blockedRelease, nil
+}
+
+// BlockedReleasesFromReader decodes a json-encoded list of blocked releases from the given
+// io.Reader.
+func BlockedReleasesFromReader(reader io.Reader) ([]*BlockedRelease, error) {
+	blockedReleases := []*BlockedRelease{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&blockedReleases)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return blockedReleases, nil
+}
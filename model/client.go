@@ -42,6 +42,30 @@ func NewClientWithHeaders(address string, headers map[string]string) *Client {
 	}
 }
 
+// NewClientWithToken creates a client to the elrond server at the given
+// address, authenticating every request with the given bearer token.
+func NewClientWithToken(address, token string) *Client {
+	return NewClientWithHeaders(address, map[string]string{
+		"Authorization": fmt.Sprintf("Bearer %s", token),
+	})
+}
+
+// WithIdempotencyKey returns a copy of the client sending the given idempotency key
+// with every request, so that retries of a request are only applied once by the server.
+func (c *Client) WithIdempotencyKey(key string) *Client {
+	headers := make(map[string]string, len(c.headers)+1)
+	for k, v := range c.headers {
+		headers[k] = v
+	}
+	headers[IdempotencyKeyHeader] = key
+
+	return &Client{
+		address:    c.address,
+		headers:    headers,
+		httpClient: c.httpClient,
+	}
+}
+
 // closeBody ensures the Body of an http.Response is properly closed.
 func closeBody(r *http.Response) {
 	if r.Body != nil {
@@ -164,6 +188,49 @@ func (c *Client) ReleaseRing(ringID string, request *RingReleaseRequest) (*Ring,
 	}
 }
 
+// RetryReleaseRing retries the failed release of a ring from the configured elrond server.
+func (c *Client) RetryReleaseRing(ringID string) (*Ring, error) {
+	resp, err := c.doPost(c.buildURL("/api/ring/%s/release/retry", ringID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return RingFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// PromoteRing releases the active release of the source ring to the given ring.
+func (c *Client) PromoteRing(ringID, sourceRingID string) (*Ring, error) {
+	u, err := url.Parse(c.buildURL("/api/ring/%s/promote", ringID))
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Add("from", sourceRingID)
+	u.RawQuery = q.Encode()
+
+	resp, err := c.doPost(u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return RingFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // GetRingRelease fetches the specified ring release from the configured elrond server.
 func (c *Client) GetRingRelease(releaseID string) (*RingRelease, error) {
 	resp, err := c.doGet(c.buildURL("/api/release/%s", releaseID))
@@ -184,6 +251,91 @@ func (c *Client) GetRingRelease(releaseID string) (*RingRelease, error) {
 	}
 }
 
+// GetRingReleases fetches the list of ring releases from the configured elrond server,
+// together with the rings and installation groups running or desiring each one.
+func (c *Client) GetRingReleases(request *GetRingReleasesRequest) ([]*RingReleaseUsage, error) {
+	u, err := url.Parse(c.buildURL("/api/releases"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return RingReleaseUsagesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetBlockedReleases fetches the blocked releases from the configured elrond server.
+func (c *Client) GetBlockedReleases() ([]*BlockedRelease, error) {
+	resp, err := c.doGet(c.buildURL("/api/releases/blocklist"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return BlockedReleasesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// BlockRelease blocks an image and version from being released on the configured elrond
+// server.
+func (c *Client) BlockRelease(request *BlockReleaseRequest) (*BlockedRelease, error) {
+	resp, err := c.doPost(c.buildURL("/api/releases/blocklist"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return BlockedReleaseFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// UnblockRelease allows a blocked image and version to be released again on the
+// configured elrond server.
+func (c *Client) UnblockRelease(request *UnblockReleaseRequest) error {
+	u, err := url.Parse(c.buildURL("/api/releases/blocklist"))
+	if err != nil {
+		return err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doDelete(u.String())
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // ReleaseAllRings releases all ring deployments from the configured elrond server.
 func (c *Client) ReleaseAllRings(request *RingReleaseRequest) ([]*Ring, error) {
 	resp, err := c.doPost(c.buildURL("/api/rings/release"), request)
@@ -272,6 +424,26 @@ func (c *Client) GetRing(ringID string) (*Ring, error) {
 	}
 }
 
+// GetRingByName fetches the non-deleted ring with the given name from the configured elrond server.
+func (c *Client) GetRingByName(name string) (*Ring, error) {
+	resp, err := c.doGet(c.buildURL("/api/ring/name/%s", url.PathEscape(name)))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return RingFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // GetRings fetches the list of rings from the configured elrond server.
 func (c *Client) GetRings(request *GetRingsRequest) ([]*Ring, error) {
 	u, err := url.Parse(c.buildURL("/api/rings"))
@@ -296,6 +468,58 @@ func (c *Client) GetRings(request *GetRingsRequest) ([]*Ring, error) {
 	}
 }
 
+// GetRingGraph fetches the order in which the rings are released.
+func (c *Client) GetRingGraph() (*RingGraph, error) {
+	resp, err := c.doGet(c.buildURL("/api/rings/graph"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return RingGraphFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetRingMatrix fetches the active and desired release of every ring and installation
+// group from the configured elrond server.
+func (c *Client) GetRingMatrix() (*RingMatrix, error) {
+	resp, err := c.doGet(c.buildURL("/api/rings/matrix"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return RingMatrixFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ImportRings requests the configured elrond server to create rings and installation
+// groups from the existing provisioner groups.
+func (c *Client) ImportRings(request *ImportRingsRequest) (*RingImport, error) {
+	resp, err := c.doPost(c.buildURL("/api/rings/import"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return RingImportFromReader(resp.Body)
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // DeleteRing deletes the given ring from the configured elrond server.
 func (c *Client) DeleteRing(ringID string) error {
 	resp, err := c.doDelete(c.buildURL("/api/ring/%s", ringID))
@@ -452,6 +676,68 @@ func (c *Client) DeleteRingInstallationGroup(ringID string, installationGroupID
 	}
 }
 
+// GetInstallationGroup fetches the specified installation group from the configured elrond server.
+func (c *Client) GetInstallationGroup(installationGroupID string) (*InstallationGroup, error) {
+	resp, err := c.doGet(c.buildURL("/api/installationgroup/%s", installationGroupID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return InstallationGroupFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetInstallationGroups fetches the list of installation groups from the configured elrond server.
+func (c *Client) GetInstallationGroups(request *GetInstallationGroupsRequest) ([]*InstallationGroup, error) {
+	u, err := url.Parse(c.buildURL("/api/installationgroups"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return InstallationGroupsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// VerifyInstallationGroups checks the provisioner group of every installation group registered
+// to a ring from the configured elrond server.
+func (c *Client) VerifyInstallationGroups() ([]*InstallationGroupVerification, error) {
+	resp, err := c.doGet(c.buildURL("/api/installationgroups/verify"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return InstallationGroupVerificationsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // UpdateInstallationGroup requests the update of an installation group from the configured elrond server.
 func (c *Client) UpdateInstallationGroup(installationGroup string, request *UpdateInstallationGroupRequest) (*InstallationGroup, error) {
 	resp, err := c.doPost(c.buildURL("/api/installationgroup/%s/update", installationGroup), request)
@@ -467,3 +753,110 @@ func (c *Client) UpdateInstallationGroup(installationGroup string, request *Upda
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
+
+// MoveInstallationGroup requests the configured elrond server to register the installation group
+// to another ring.
+func (c *Client) MoveInstallationGroup(installationGroupID string, request *MoveInstallationGroupRequest) (*InstallationGroup, error) {
+	resp, err := c.doPost(c.buildURL("/api/installationgroup/%s/move", installationGroupID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return InstallationGroupFromReader(resp.Body)
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ApplyTopology requests the configured elrond server to converge the rings to the given topology.
+func (c *Client) ApplyTopology(request *ApplyTopologyRequest) (*TopologyPlan, error) {
+	resp, err := c.doPost(c.buildURL("/api/topology/apply"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return TopologyPlanFromReader(resp.Body)
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ExportTopology requests a snapshot of the rings, installation groups, webhooks and releases from the configured elrond server.
+func (c *Client) ExportTopology() (*TopologyExport, error) {
+	resp, err := c.doGet(c.buildURL("/api/topology/export"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return TopologyExportFromReader(resp.Body)
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateAPIToken requests the creation of an API token from the configured elrond server.
+func (c *Client) CreateAPIToken(request *CreateAPITokenRequest) (*CreateAPITokenResponse, error) {
+	resp, err := c.doPost(c.buildURL("/api/tokens"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return CreateAPITokenResponseFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetAPITokens fetches the list of API tokens from the configured elrond server.
+func (c *Client) GetAPITokens(request *GetAPITokensRequest) ([]*APIToken, error) {
+	u, err := url.Parse(c.buildURL("/api/tokens"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return APITokensFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// DeleteAPIToken revokes the given API token on the configured elrond server.
+func (c *Client) DeleteAPIToken(tokenID string) error {
+	resp, err := c.doDelete(c.buildURL("/api/token/%s", tokenID))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
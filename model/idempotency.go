@@ -0,0 +1,32 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+const (
+	// IdempotencyKeyHeader is the request header carrying a client generated key
+	// identifying a request across retries.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from a previous request
+	// with the same idempotency key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// IdempotencyKeyMaxLength is the maximum length of an idempotency key.
+	IdempotencyKeyMaxLength = 255
+)
+
+// IdempotencyRecord stores the outcome of a request made with an idempotency key.
+//
+// A StatusCode of 0 indicates the original request is still being processed.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	Body        []byte
+	CreateAt    int64
+}
+
+// IsComplete returns whether the original request has finished processing.
+func (r *IdempotencyRecord) IsComplete() bool {
+	return r.StatusCode != 0
+}
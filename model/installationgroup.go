@@ -7,7 +7,9 @@ package model
 import (
 	"encoding/json"
 	"io"
+	"net/url"
 	"sort"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
@@ -20,8 +22,51 @@ type InstallationGroup struct {
 	ReleaseAt          int64  `json:"releaseAt,omitempty"`
 	SoakTime           int    `json:"soakTime,omitempty"`
 	ProvisionerGroupID string `json:"provisionerGroupID,omitempty"`
-	LockAcquiredBy     *string
-	LockAcquiredAt     int64
+	// ProvisionerTarget names the provisioner managing the provisioner group, empty for the
+	// default provisioner.
+	ProvisionerTarget string `json:"provisionerTarget,omitempty"`
+	// Priority orders the releases of the installation groups of a ring, lowest first.
+	Priority int `json:"priority,omitempty"`
+	// Paused installation groups are left out of releases.
+	Paused bool `json:"paused,omitempty"`
+	// SkippedReleaseID is the last release the installation group was left out of.
+	SkippedReleaseID string `json:"skippedReleaseID,omitempty"`
+	// ActiveReleaseID is the release the installation group last completed, and
+	// DesiredReleaseID the release it is being released, both empty until its first release.
+	ActiveReleaseID  string `json:"activeReleaseID,omitempty"`
+	DesiredReleaseID string `json:"desiredReleaseID,omitempty"`
+	// Drift describes how the provisioner group differs from the active release, as last
+	// checked at DriftCheckedAt.
+	Drift          string `json:"drift,omitempty"`
+	DriftCheckedAt int64  `json:"driftCheckedAt,omitempty"`
+	// ReleaseFailure is why the last release failed.
+	ReleaseFailure string `json:"releaseFailure,omitempty"`
+	RingID         string `json:"ringID,omitempty"`
+	LockAcquiredBy *string
+	LockAcquiredAt int64
+}
+
+// MoveInstallationGroupRequest specifies the ring an installation group should be moved to.
+type MoveInstallationGroupRequest struct {
+	RingID string `json:"ringID,omitempty"`
+}
+
+// InstallationGroupFilter describes the parameters used to constrain a set of installation groups.
+type InstallationGroupFilter struct {
+	RingID             string
+	State              string
+	ProvisionerGroupID string
+	Page               int
+	PerPage            int
+}
+
+// GetInstallationGroupsRequest describes the parameters to request a list of installation groups.
+type GetInstallationGroupsRequest struct {
+	RingID             string
+	State              string
+	ProvisionerGroupID string
+	Page               int
+	PerPage            int
 }
 
 // RegisterInstallationGroupRequest represent parameters passed to register an installation group to the Ring.
@@ -29,6 +74,8 @@ type RegisterInstallationGroupRequest struct {
 	Name               string `json:"name,omitempty"`
 	SoakTime           int    `json:"soakTime,omitempty"`
 	ProvisionerGroupID string `json:"provisionerGroupID,omitempty"`
+	ProvisionerTarget  string `json:"provisionerTarget,omitempty"`
+	Priority           int    `json:"priority,omitempty"`
 }
 
 // UpdateInstallationGroupRequest specifies the parameters to update an installation group.
@@ -36,6 +83,42 @@ type UpdateInstallationGroupRequest struct {
 	Name               string `json:"name,omitempty"`
 	SoakTime           int    `json:"soakTime,omitempty"`
 	ProvisionerGroupID string `json:"provisionerGroupID,omitempty"`
+	// ProvisionerTarget, Priority and Paused are only updated when set, so that they can be
+	// reset.
+	ProvisionerTarget *string `json:"provisionerTarget,omitempty"`
+	Priority          *int    `json:"priority,omitempty"`
+	Paused            *bool   `json:"paused,omitempty"`
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetInstallationGroupsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if request.RingID != "" {
+		q.Add("ring", request.RingID)
+	}
+	if request.State != "" {
+		q.Add("state", request.State)
+	}
+	if request.ProvisionerGroupID != "" {
+		q.Add("provisioner_group_id", request.ProvisionerGroupID)
+	}
+	q.Add("page", strconv.Itoa(request.Page))
+	q.Add("per_page", strconv.Itoa(request.PerPage))
+	u.RawQuery = q.Encode()
+}
+
+// RunningReleaseID returns the ID of the release the installation group runs. Until it
+// completes a release, it is assumed to run the active release of its ring, unless it was
+// left out of it, in which case the release it runs is unknown and empty is returned.
+func (ig *InstallationGroup) RunningReleaseID(ring *Ring) string {
+	if ig.ActiveReleaseID != "" {
+		return ig.ActiveReleaseID
+	}
+	if ig.SkippedReleaseID != "" && ig.SkippedReleaseID == ring.ActiveReleaseID {
+		return ""
+	}
+
+	return ring.ActiveReleaseID
 }
 
 // SortInstallationGroups sorts installation groups by name alphabetically.
@@ -68,6 +151,44 @@ func NewUpdateInstallationGroupRequestFromReader(reader io.Reader) (*UpdateInsta
 	return &updateInstallationGroupRequest, nil
 }
 
+// NewMoveInstallationGroupRequestFromReader will create a MoveInstallationGroupRequest from an
+// io.Reader with JSON data.
+func NewMoveInstallationGroupRequestFromReader(reader io.Reader) (*MoveInstallationGroupRequest, error) {
+	var moveInstallationGroupRequest MoveInstallationGroupRequest
+	err := json.NewDecoder(reader).Decode(&moveInstallationGroupRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode move installation group request")
+	}
+
+	err = moveInstallationGroupRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "move installation group request failed validation")
+	}
+
+	return &moveInstallationGroupRequest, nil
+}
+
+// Validate validates the values of a move installation group request.
+func (request *MoveInstallationGroupRequest) Validate() error {
+	if len(request.RingID) == 0 {
+		return errors.New("must specify the ring to move the installation group to")
+	}
+
+	return nil
+}
+
+// InstallationGroupVerification is the result of checking the registration of an installation
+// group against the provisioner.
+type InstallationGroupVerification struct {
+	InstallationGroupID   string `json:"installationGroupID"`
+	InstallationGroupName string `json:"installationGroupName"`
+	RingID                string `json:"ringID,omitempty"`
+	ProvisionerGroupID    string `json:"provisionerGroupID,omitempty"`
+	ProvisionerTarget     string `json:"provisionerTarget,omitempty"`
+	// Problems describe what is wrong with the registration, and are empty when it is valid.
+	Problems []string `json:"problems"`
+}
+
 // ContainsInstallationGroup determines whether slice of InstallationGroups contains a specific installation group.
 func ContainsInstallationGroup(installationGroups []*InstallationGroup, installationGroup *InstallationGroup) bool {
 	for _, ann := range installationGroups {
@@ -89,3 +210,30 @@ func InstallationGroupFromReader(reader io.Reader) (*InstallationGroup, error) {
 
 	return &installationGroup, nil
 }
+
+// InstallationGroupsFromReader decodes a json-encoded list of installation groups from the given io.Reader.
+func InstallationGroupsFromReader(reader io.Reader) ([]*InstallationGroup, error) {
+	installationGroups := []*InstallationGroup{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&installationGroups)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return installationGroups, nil
+}
+
+// InstallationGroupVerificationsFromReader decodes a json-encoded list of installation group
+// verifications from the given io.Reader.
+func InstallationGroupVerificationsFromReader(reader io.Reader) ([]*InstallationGroupVerification, error) {
+	verifications := []*InstallationGroupVerification{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&verifications)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return verifications, nil
+}
@@ -4,6 +4,8 @@
 
 package model
 
+import "slices"
+
 const (
 	// InstallationGroupStable is an installation group in a stable state and undergoing no changes.
 	InstallationGroupStable = "stable"
@@ -59,6 +61,11 @@ var AllInstallationGroupRequestStates = []string{
 	InstallationGroupReleaseSoakingRequested,
 }
 
+// IsReleasing returns whether the installation group has a release pending or in progress.
+func (i *InstallationGroup) IsReleasing() bool {
+	return slices.Contains(AllInstallationGroupStatesPendingWork, i.State)
+}
+
 // ValidInstallationGroupTransitionState returns whether an installation group can be transitioned into the
 // new state or not based on its current state.
 func (i *InstallationGroup) ValidInstallationGroupTransitionState(newState string) bool {
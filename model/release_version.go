@@ -0,0 +1,36 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"github.com/blang/semver"
+)
+
+// ParseReleaseVersion parses the version of a ring release as a semantic version, tolerating
+// a "v" prefix and missing minor or patch numbers. It returns false for versions that are not
+// semantic versions, such as tags or commit hashes, which are not subject to version checks.
+func ParseReleaseVersion(version string) (semver.Version, bool) {
+	parsed, err := semver.ParseTolerant(version)
+	if err != nil {
+		return semver.Version{}, false
+	}
+
+	return parsed, true
+}
+
+// IsNewerReleaseVersion returns whether the version is a newer semantic version than the
+// other version. It returns false when either of them is not a semantic version.
+func IsNewerReleaseVersion(version, other string) bool {
+	parsed, ok := ParseReleaseVersion(version)
+	if !ok {
+		return false
+	}
+	parsedOther, ok := ParseReleaseVersion(other)
+	if !ok {
+		return false
+	}
+
+	return parsed.GT(parsedOther)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNewerReleaseVersion(t *testing.T) {
+	for _, tc := range []struct {
+		version  string
+		other    string
+		expected bool
+	}{
+		{"7.9.0", "7.8.1", true},
+		{"v7.10", "7.9.3", true},
+		{"7.8.0", "7.8.0", false},
+		{"7.8.0-rc1", "7.8.0", false},
+		{"7.8.0", "7.8.0-rc1", true},
+		{"7.7.0", "7.8.0", false},
+		{"latest", "7.8.0", false},
+		{"7.8.0", "abc1234", false},
+	} {
+		t.Run(tc.version+" "+tc.other, func(t *testing.T) {
+			require.Equal(t, tc.expected, IsNewerReleaseVersion(tc.version, tc.other))
+		})
+	}
+}
+
+func TestEarlierRings(t *testing.T) {
+	canary := &Ring{ID: "canary", Priority: 1}
+	region1 := &Ring{ID: "region-1", Priority: 2}
+	region2 := &Ring{ID: "region-2", Priority: 2}
+	global := &Ring{ID: "global", Priority: 3}
+	rings := []*Ring{canary, region1, region2, global}
+
+	t.Run("by priority", func(t *testing.T) {
+		require.Equal(t, []*Ring{canary, region1, region2}, EarlierRings(global, rings, nil))
+		require.Equal(t, []*Ring{canary}, EarlierRings(region2, rings, nil))
+		require.Empty(t, EarlierRings(canary, rings, nil))
+	})
+
+	t.Run("by dependencies", func(t *testing.T) {
+		dependencies := map[string][]string{
+			"region-1": {"canary"},
+			"global":   {"region-1"},
+		}
+		require.Equal(t, []*Ring{canary, region1}, EarlierRings(global, rings, dependencies))
+		require.Empty(t, EarlierRings(region2, rings, dependencies))
+	})
+}
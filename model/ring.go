@@ -6,7 +6,10 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	cmodel "github.com/mattermost/mattermost-cloud/model"
 )
@@ -25,6 +28,7 @@ type Ring struct {
 	DeleteAt           int64
 	ReleaseAt          int64
 	InstallationGroups []*InstallationGroup `json:"installationGroups,omitempty"`
+	DependsOn          []string             `json:"dependsOn,omitempty"`
 	APISecurityLock    bool
 	LockAcquiredBy     *string
 	LockAcquiredAt     int64
@@ -38,6 +42,46 @@ type RingRelease struct {
 	EnvVariables cmodel.EnvVarMap
 	CreateAt     int64
 	Force        bool
+	CreatedBy    string
+	// ExcludedInstallationGroups are the IDs or names of the installation groups left out of
+	// the release.
+	ExcludedInstallationGroups []string `json:",omitempty"`
+}
+
+// ExcludesInstallationGroup returns whether the installation group is left out of the release.
+func (r *RingRelease) ExcludesInstallationGroup(installationGroup *InstallationGroup) bool {
+	for _, excluded := range r.ExcludedInstallationGroups {
+		if excluded == installationGroup.ID || excluded == installationGroup.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DriftFrom describes how the given release, as deployed to a provisioner group, differs
+// from the release, or returns empty if it does not. Environment variables are only
+// compared when the release sets them, since releases without environment variables keep
+// the environment variables of the group. Their values are left out of the description.
+func (r *RingRelease) DriftFrom(deployed *RingRelease) string {
+	var drift []string
+	if deployed.Image != r.Image || deployed.Version != r.Version {
+		drift = append(drift, fmt.Sprintf("runs %s:%s instead of %s:%s", deployed.Image, deployed.Version, r.Image, r.Version))
+	}
+
+	var envVariables []string
+	for name, envVariable := range r.EnvVariables {
+		deployedEnvVariable, ok := deployed.EnvVariables[name]
+		if !ok || deployedEnvVariable.Value != envVariable.Value {
+			envVariables = append(envVariables, name)
+		}
+	}
+	if len(envVariables) > 0 {
+		sort.Strings(envVariables)
+		drift = append(drift, fmt.Sprintf("env variables %s differ", strings.Join(envVariables, ", ")))
+	}
+
+	return strings.Join(drift, "; ")
 }
 
 // Clone returns a deep copy the ring.
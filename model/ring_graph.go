@@ -0,0 +1,217 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RingGraphOrderPriority is a ring graph where the rings are released one at a time in
+	// order of priority, because no ring declares dependencies.
+	RingGraphOrderPriority = "priority"
+	// RingGraphOrderDependencies is a ring graph where each ring is released after the rings
+	// it depends on, and independent rings are released in parallel.
+	RingGraphOrderDependencies = "dependencies"
+)
+
+// RingGraph describes the order in which the rings are released.
+type RingGraph struct {
+	OrderedBy string           `json:"orderedBy"`
+	Rings     []*RingGraphNode `json:"rings"`
+}
+
+// RingGraphNode is a ring of the ring graph.
+type RingGraphNode struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	State     string   `json:"state"`
+	Priority  int      `json:"priority"`
+	DependsOn []string `json:"dependsOn"`
+	// Stage is the position of the ring in the release order, starting at 0. When the rings
+	// are ordered by dependencies, rings of the same stage do not depend on each other.
+	Stage int `json:"stage"`
+}
+
+// RingGraphFromReader decodes a json-encoded ring graph from the given io.Reader.
+func RingGraphFromReader(reader io.Reader) (*RingGraph, error) {
+	ringGraph := RingGraph{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&ringGraph)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &ringGraph, nil
+}
+
+// CheckRingDependencies returns an error if a ring depends on itself or if the
+// dependencies, keyed by the ID of the dependent ring, form a cycle.
+func CheckRingDependencies(dependencies map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	status := make(map[string]int)
+
+	var visit func(ringID string, path []string) error
+	visit = func(ringID string, path []string) error {
+		switch status[ringID] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("ring dependencies form a cycle: %s", strings.Join(append(path, ringID), " -> "))
+		}
+
+		status[ringID] = visiting
+		for _, dependencyID := range dependencies[ringID] {
+			if dependencyID == ringID {
+				return errors.Errorf("ring %s cannot depend on itself", ringID)
+			}
+			if err := visit(dependencyID, append(path, ringID)); err != nil {
+				return err
+			}
+		}
+		status[ringID] = visited
+
+		return nil
+	}
+
+	for _, ringID := range sortedRingIDs(dependencies) {
+		if err := visit(ringID, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewRingGraph builds the ring graph of the given rings from the dependencies, keyed by
+// the ID of the dependent ring. Without any dependency the rings are ordered by priority.
+func NewRingGraph(rings []*Ring, dependencies map[string][]string) (*RingGraph, error) {
+	if err := CheckRingDependencies(dependencies); err != nil {
+		return nil, err
+	}
+
+	graph := &RingGraph{OrderedBy: RingGraphOrderDependencies, Rings: []*RingGraphNode{}}
+	if len(dependencies) == 0 {
+		graph.OrderedBy = RingGraphOrderPriority
+	}
+
+	priorities := make(map[int]bool)
+	ringIDs := make(map[string]bool)
+	for _, ring := range rings {
+		priorities[ring.Priority] = true
+		ringIDs[ring.ID] = true
+	}
+	priorityStages := make(map[int]int)
+	for priority := range priorities {
+		for other := range priorities {
+			if other < priority {
+				priorityStages[priority]++
+			}
+		}
+	}
+
+	stages := make(map[string]int)
+	var stage func(ringID string) int
+	stage = func(ringID string) int {
+		if value, ok := stages[ringID]; ok {
+			return value
+		}
+		value := 0
+		for _, dependencyID := range dependencies[ringID] {
+			if ringIDs[dependencyID] {
+				value = max(value, stage(dependencyID)+1)
+			}
+		}
+		stages[ringID] = value
+
+		return value
+	}
+
+	for _, ring := range rings {
+		node := &RingGraphNode{
+			ID:        ring.ID,
+			Name:      ring.Name,
+			State:     ring.State,
+			Priority:  ring.Priority,
+			DependsOn: []string{},
+		}
+		if dependsOn, ok := dependencies[ring.ID]; ok {
+			node.DependsOn = dependsOn
+		}
+		if graph.OrderedBy == RingGraphOrderPriority {
+			node.Stage = priorityStages[ring.Priority]
+		} else {
+			node.Stage = stage(ring.ID)
+		}
+		graph.Rings = append(graph.Rings, node)
+	}
+
+	sort.SliceStable(graph.Rings, func(i, j int) bool {
+		if graph.Rings[i].Stage != graph.Rings[j].Stage {
+			return graph.Rings[i].Stage < graph.Rings[j].Stage
+		}
+		if graph.Rings[i].Priority != graph.Rings[j].Priority {
+			return graph.Rings[i].Priority < graph.Rings[j].Priority
+		}
+		return graph.Rings[i].Name < graph.Rings[j].Name
+	})
+
+	return graph, nil
+}
+
+// EarlierRings returns the rings released before the given ring. When any ring declares
+// dependencies, these are the rings the ring depends on, directly or not. Otherwise these
+// are the rings of a lower priority.
+func EarlierRings(ring *Ring, rings []*Ring, dependencies map[string][]string) []*Ring {
+	var earlierRings []*Ring
+	if len(dependencies) == 0 {
+		for _, other := range rings {
+			if other.Priority < ring.Priority {
+				earlierRings = append(earlierRings, other)
+			}
+		}
+
+		return earlierRings
+	}
+
+	earlier := make(map[string]bool)
+	var visit func(ringID string)
+	visit = func(ringID string) {
+		for _, dependencyID := range dependencies[ringID] {
+			if !earlier[dependencyID] {
+				earlier[dependencyID] = true
+				visit(dependencyID)
+			}
+		}
+	}
+	visit(ring.ID)
+
+	for _, other := range rings {
+		if earlier[other.ID] && other.ID != ring.ID {
+			earlierRings = append(earlierRings, other)
+		}
+	}
+
+	return earlierRings
+}
+
+func sortedRingIDs(dependencies map[string][]string) []string {
+	keys := make([]string, 0, len(dependencies))
+	for key := range dependencies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
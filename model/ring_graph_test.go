@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRingDependencies(t *testing.T) {
+	t.Run("no dependencies", func(t *testing.T) {
+		require.NoError(t, CheckRingDependencies(nil))
+	})
+
+	t.Run("acyclic", func(t *testing.T) {
+		require.NoError(t, CheckRingDependencies(map[string][]string{
+			"c": {"a", "b"},
+			"d": {"c", "a"},
+		}))
+	})
+
+	t.Run("self dependency", func(t *testing.T) {
+		require.EqualError(t, CheckRingDependencies(map[string][]string{
+			"a": {"a"},
+		}), "ring a cannot depend on itself")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		require.EqualError(t, CheckRingDependencies(map[string][]string{
+			"a": {"b"},
+			"b": {"c"},
+			"c": {"a"},
+		}), "ring dependencies form a cycle: a -> b -> c -> a")
+	})
+}
+
+func TestNewRingGraph(t *testing.T) {
+	rings := []*Ring{
+		{ID: "global", Name: "global", Priority: 3},
+		{ID: "region-1", Name: "region-1", Priority: 1},
+		{ID: "region-2", Name: "region-2", Priority: 2},
+		{ID: "canary", Name: "canary", Priority: 1},
+	}
+
+	t.Run("by priority", func(t *testing.T) {
+		graph, err := NewRingGraph(rings, nil)
+		require.NoError(t, err)
+		require.Equal(t, RingGraphOrderPriority, graph.OrderedBy)
+
+		var stages []int
+		var names []string
+		for _, ring := range graph.Rings {
+			stages = append(stages, ring.Stage)
+			names = append(names, ring.Name)
+		}
+		require.Equal(t, []int{0, 0, 1, 2}, stages)
+		require.Equal(t, []string{"canary", "region-1", "region-2", "global"}, names)
+	})
+
+	t.Run("by dependencies", func(t *testing.T) {
+		graph, err := NewRingGraph(rings, map[string][]string{
+			"region-1": {"canary"},
+			"region-2": {"canary"},
+			"global":   {"region-1", "region-2"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, RingGraphOrderDependencies, graph.OrderedBy)
+
+		stages := make(map[string]int)
+		for _, ring := range graph.Rings {
+			stages[ring.Name] = ring.Stage
+		}
+		require.Equal(t, map[string]int{"canary": 0, "region-1": 1, "region-2": 1, "global": 2}, stages)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := NewRingGraph(rings, map[string][]string{
+			"canary":   {"global"},
+			"global":   {"region-1"},
+			"region-1": {"canary"},
+		})
+		require.Error(t, err)
+	})
+}
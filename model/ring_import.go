@@ -0,0 +1,250 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ProvisionerGroup is a group of installations managed by the provisioner.
+type ProvisionerGroup struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Image       string   `json:"image"`
+	Version     string   `json:"version"`
+	Annotations []string `json:"annotations,omitempty"`
+	// ProvisionerTarget names the provisioner managing the group, empty for the default
+	// provisioner.
+	ProvisionerTarget string `json:"provisionerTarget,omitempty"`
+}
+
+// RingImportRule maps the provisioner groups it matches to a ring. A group matches when its
+// name matches NamePattern and it has Annotation, whichever are set.
+type RingImportRule struct {
+	Ring string `json:"ring"`
+	// Priority and SoakTime are only used when the ring is created.
+	Priority int `json:"priority,omitempty"`
+	SoakTime int `json:"soakTime,omitempty"`
+	// NamePattern is a shell pattern, such as "prod-*", as supported by path.Match.
+	NamePattern string `json:"namePattern,omitempty"`
+	Annotation  string `json:"annotation,omitempty"`
+}
+
+// ImportRingsRequest specifies how to import the provisioner groups into rings.
+type ImportRingsRequest struct {
+	// Rules are evaluated in order, and a group is imported to the ring of the first rule
+	// matching it.
+	Rules []*RingImportRule `json:"rules"`
+	// ProvisionerTarget names the provisioner to import the groups from, empty for the
+	// default provisioner.
+	ProvisionerTarget string `json:"provisionerTarget,omitempty"`
+	// DryRun only computes the import without applying it.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// RingImport describes the rings and installation groups imported from provisioner groups.
+type RingImport struct {
+	Rings []*RingImportRing `json:"rings"`
+	// Skipped are the provisioner groups left out of the import.
+	Skipped []*RingImportSkippedGroup `json:"skipped"`
+	Applied bool                      `json:"applied"`
+}
+
+// RingImportRing is a ring provisioner groups are imported to.
+type RingImportRing struct {
+	Name string `json:"name"`
+	// ID is the ID of the ring, empty when the ring is yet to be created.
+	ID       string `json:"id,omitempty"`
+	Create   bool   `json:"create"`
+	Priority int    `json:"priority,omitempty"`
+	SoakTime int    `json:"soakTime,omitempty"`
+	// Image and Version are the initial active release of a created ring, taken from its
+	// first installation group.
+	Image              string                         `json:"image,omitempty"`
+	Version            string                         `json:"version,omitempty"`
+	InstallationGroups []*RingImportInstallationGroup `json:"installationGroups"`
+}
+
+// RingImportInstallationGroup is an installation group imported from a provisioner group,
+// running the image and version of the group.
+type RingImportInstallationGroup struct {
+	Name               string `json:"name"`
+	ProvisionerGroupID string `json:"provisionerGroupID"`
+	ProvisionerTarget  string `json:"provisionerTarget,omitempty"`
+	Image              string `json:"image"`
+	Version            string `json:"version"`
+	// ReleaseID is the ring release of the image and version, set when the import is applied.
+	ReleaseID string `json:"releaseID,omitempty"`
+}
+
+// RingImportSkippedGroup is a provisioner group left out of an import.
+type RingImportSkippedGroup struct {
+	ProvisionerGroupID string `json:"provisionerGroupID"`
+	Name               string `json:"name"`
+	Reason             string `json:"reason"`
+}
+
+// Matches returns whether the provisioner group matches the rule.
+func (r *RingImportRule) Matches(group *ProvisionerGroup) bool {
+	if r.NamePattern != "" {
+		matched, err := path.Match(r.NamePattern, group.Name)
+		if err != nil || !matched {
+			return false
+		}
+	}
+	if r.Annotation != "" {
+		for _, annotation := range group.Annotations {
+			if annotation == r.Annotation {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// SetDefaults sets the default values of the import request.
+func (request *ImportRingsRequest) SetDefaults() {
+	for _, rule := range request.Rules {
+		if rule != nil && rule.SoakTime == 0 {
+			rule.SoakTime = 7200
+		}
+	}
+}
+
+// Validate validates the import request.
+func (request *ImportRingsRequest) Validate() error {
+	if len(request.Rules) == 0 {
+		return errors.New("at least one rule is required")
+	}
+
+	for _, rule := range request.Rules {
+		if rule == nil {
+			return errors.New("rule cannot be empty")
+		}
+		if err := ValidateRingName(rule.Ring); err != nil {
+			return errors.Wrapf(err, "invalid ring name %q", rule.Ring)
+		}
+		if rule.NamePattern == "" && rule.Annotation == "" {
+			return errors.Errorf("rule for ring %s must set a name pattern or an annotation", rule.Ring)
+		}
+		if _, err := path.Match(rule.NamePattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid name pattern %q", rule.NamePattern)
+		}
+		if rule.Priority < 0 {
+			return errors.Errorf("priority of ring %s cannot be negative", rule.Ring)
+		}
+		if rule.SoakTime < 0 {
+			return errors.Errorf("soak time of ring %s cannot be negative", rule.Ring)
+		}
+	}
+
+	return nil
+}
+
+// NewRingImport maps the provisioner groups to rings with the given rules. Groups are sorted
+// by name, and groups matching no rule are skipped.
+func NewRingImport(groups []*ProvisionerGroup, rules []*RingImportRule) *RingImport {
+	groups = append([]*ProvisionerGroup(nil), groups...)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	ringImport := &RingImport{
+		Rings:   []*RingImportRing{},
+		Skipped: []*RingImportSkippedGroup{},
+	}
+	ringsByName := make(map[string]*RingImportRing)
+
+	for _, group := range groups {
+		var matched *RingImportRule
+		for _, rule := range rules {
+			if rule.Matches(group) {
+				matched = rule
+				break
+			}
+		}
+		if matched == nil {
+			ringImport.Skipped = append(ringImport.Skipped, &RingImportSkippedGroup{
+				ProvisionerGroupID: group.ID,
+				Name:               group.Name,
+				Reason:             "no rule matches the group",
+			})
+			continue
+		}
+
+		ring, ok := ringsByName[matched.Ring]
+		if !ok {
+			ring = &RingImportRing{
+				Name:               matched.Ring,
+				Priority:           matched.Priority,
+				SoakTime:           matched.SoakTime,
+				InstallationGroups: []*RingImportInstallationGroup{},
+			}
+			ringsByName[matched.Ring] = ring
+			ringImport.Rings = append(ringImport.Rings, ring)
+		}
+		ring.InstallationGroups = append(ring.InstallationGroups, &RingImportInstallationGroup{
+			Name:               group.Name,
+			ProvisionerGroupID: group.ID,
+			ProvisionerTarget:  group.ProvisionerTarget,
+			Image:              group.Image,
+			Version:            group.Version,
+		})
+	}
+
+	return ringImport
+}
+
+// Skip moves the installation group out of the ring import, recording why it was skipped.
+func (ri *RingImport) Skip(ring *RingImportRing, installationGroup *RingImportInstallationGroup, reason string) {
+	for i, candidate := range ring.InstallationGroups {
+		if candidate == installationGroup {
+			ring.InstallationGroups = append(ring.InstallationGroups[:i], ring.InstallationGroups[i+1:]...)
+			break
+		}
+	}
+
+	ri.Skipped = append(ri.Skipped, &RingImportSkippedGroup{
+		ProvisionerGroupID: installationGroup.ProvisionerGroupID,
+		Name:               installationGroup.Name,
+		Reason:             reason,
+	})
+}
+
+// NewImportRingsRequestFromReader will create an ImportRingsRequest from an io.Reader with
+// JSON data.
+func NewImportRingsRequestFromReader(reader io.Reader) (*ImportRingsRequest, error) {
+	var importRingsRequest ImportRingsRequest
+	err := json.NewDecoder(reader).Decode(&importRingsRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode import rings request")
+	}
+
+	importRingsRequest.SetDefaults()
+	if err = importRingsRequest.Validate(); err != nil {
+		return nil, errors.Wrap(err, "import rings request failed validation")
+	}
+
+	return &importRingsRequest, nil
+}
+
+// RingImportFromReader decodes a json-encoded ring import from the given io.Reader.
+func RingImportFromReader(reader io.Reader) (*RingImport, error) {
+	ringImport := RingImport{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&ringImport)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &ringImport, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewImportRingsRequestFromReader(t *testing.T) {
+	t.Run("valid request", func(t *testing.T) {
+		request, err := NewImportRingsRequestFromReader(strings.NewReader(
+			`{"rules":[{"ring":"production","priority":2,"namePattern":"prod-*"}],"dryRun":true}`,
+		))
+		require.NoError(t, err)
+		require.True(t, request.DryRun)
+		require.Equal(t, 7200, request.Rules[0].SoakTime)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, body := range []string{
+			`{}`,
+			`{"rules":[null]}`,
+			`{"rules":[{"namePattern":"prod-*"}]}`,
+			`{"rules":[{"ring":"production"}]}`,
+			`{"rules":[{"ring":"production","namePattern":"prod-["}]}`,
+			`{"rules":[{"ring":"production","namePattern":"prod-*","priority":-1}]}`,
+			`{"rules":[{"ring":"production","namePattern":"prod-*","soakTime":-1}]}`,
+			`{"rules":`,
+		} {
+			_, err := NewImportRingsRequestFromReader(strings.NewReader(body))
+			require.Error(t, err, body)
+		}
+	})
+}
+
+func TestRingImportRuleMatches(t *testing.T) {
+	group := &ProvisionerGroup{Name: "prod-eu", Annotations: []string{"region-eu"}}
+
+	require.True(t, (&RingImportRule{NamePattern: "prod-*"}).Matches(group))
+	require.False(t, (&RingImportRule{NamePattern: "test-*"}).Matches(group))
+	require.True(t, (&RingImportRule{Annotation: "region-eu"}).Matches(group))
+	require.False(t, (&RingImportRule{Annotation: "region-us"}).Matches(group))
+	require.True(t, (&RingImportRule{NamePattern: "prod-*", Annotation: "region-eu"}).Matches(group))
+	require.False(t, (&RingImportRule{NamePattern: "prod-*", Annotation: "region-us"}).Matches(group))
+}
+
+func TestNewRingImport(t *testing.T) {
+	groups := []*ProvisionerGroup{
+		{ID: "pg4", Name: "test-1", Image: "image", Version: "1.0.0"},
+		{ID: "pg3", Name: "prod-us", Image: "image", Version: "1.0.0", Annotations: []string{"canary"}},
+		{ID: "pg2", Name: "prod-eu", Image: "image", Version: "1.1.0"},
+		{ID: "pg1", Name: "dev-1", Image: "image", Version: "2.0.0"},
+	}
+	rules := []*RingImportRule{
+		{Ring: "canary", Priority: 1, Annotation: "canary"},
+		{Ring: "production", Priority: 2, NamePattern: "prod-*"},
+		{Ring: "test", Priority: 3, NamePattern: "test-*"},
+	}
+
+	ringImport := NewRingImport(groups, rules)
+	require.Len(t, ringImport.Rings, 3)
+
+	require.Equal(t, "production", ringImport.Rings[0].Name)
+	require.Len(t, ringImport.Rings[0].InstallationGroups, 1)
+	require.Equal(t, "pg2", ringImport.Rings[0].InstallationGroups[0].ProvisionerGroupID)
+	require.Equal(t, "1.1.0", ringImport.Rings[0].InstallationGroups[0].Version)
+
+	require.Equal(t, "canary", ringImport.Rings[1].Name)
+	require.Equal(t, "pg3", ringImport.Rings[1].InstallationGroups[0].ProvisionerGroupID)
+
+	require.Equal(t, "test", ringImport.Rings[2].Name)
+	require.Equal(t, 3, ringImport.Rings[2].Priority)
+
+	require.Len(t, ringImport.Skipped, 1)
+	require.Equal(t, "pg1", ringImport.Skipped[0].ProvisionerGroupID)
+
+	t.Run("skip", func(t *testing.T) {
+		ring := ringImport.Rings[0]
+		ringImport.Skip(ring, ring.InstallationGroups[0], "already registered")
+		require.Empty(t, ring.InstallationGroups)
+		require.Len(t, ringImport.Skipped, 2)
+		require.Equal(t, "prod-eu", ringImport.Skipped[1].Name)
+		require.Equal(t, "already registered", ringImport.Skipped[1].Reason)
+	})
+}
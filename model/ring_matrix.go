@@ -0,0 +1,187 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+const (
+	// DriftReleasePending flags a ring, or an installation group of a ring, whose desired
+	// release is not its active release yet.
+	DriftReleasePending = "release-pending"
+	// DriftAheadOfEarlierRing flags a ring whose active release is a newer version than the
+	// active release of a ring released before it.
+	DriftAheadOfEarlierRing = "ahead-of-earlier-ring"
+	// DriftSkippedRelease flags an installation group that was left out of the active
+	// release of its ring, and so does not run it.
+	DriftSkippedRelease = "skipped-release"
+	// DriftPaused flags a paused installation group, which is left out of the releases of
+	// its ring.
+	DriftPaused = "paused"
+	// DriftProvisioner flags an installation group whose provisioner group differs from its
+	// release.
+	DriftProvisioner = "provisioner-drift"
+)
+
+// RingMatrix is the active and desired release of every ring and installation group.
+type RingMatrix struct {
+	Rings []*RingMatrixRing `json:"rings"`
+}
+
+// RingMatrixRelease identifies a release in the ring matrix.
+type RingMatrixRelease struct {
+	ID      string `json:"id"`
+	Image   string `json:"image"`
+	Version string `json:"version"`
+}
+
+// RingMatrixRing is a ring of the ring matrix.
+type RingMatrixRing struct {
+	ID             string             `json:"id"`
+	Name           string             `json:"name"`
+	State          string             `json:"state"`
+	Priority       int                `json:"priority"`
+	ActiveRelease  *RingMatrixRelease `json:"activeRelease,omitempty"`
+	DesiredRelease *RingMatrixRelease `json:"desiredRelease,omitempty"`
+	ReleaseAt      int64              `json:"releaseAt,omitempty"`
+	// SecondsSinceRelease is the time passed since the last release of the ring, or 0 if
+	// the ring was never released.
+	SecondsSinceRelease int64                          `json:"secondsSinceRelease"`
+	Drift               []string                       `json:"drift"`
+	InstallationGroups  []*RingMatrixInstallationGroup `json:"installationGroups"`
+}
+
+// RingMatrixInstallationGroup is an installation group of the ring matrix.
+type RingMatrixInstallationGroup struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// ActiveRelease is the release the installation group last completed or, until it
+	// completes one, the active release of the ring unless it was left out of it.
+	ActiveRelease       *RingMatrixRelease `json:"activeRelease,omitempty"`
+	DesiredRelease      *RingMatrixRelease `json:"desiredRelease,omitempty"`
+	ReleaseAt           int64              `json:"releaseAt,omitempty"`
+	SecondsSinceRelease int64              `json:"secondsSinceRelease"`
+	Drift               []string           `json:"drift"`
+}
+
+// NewRingMatrix builds the ring matrix of the given rings, with their installation groups,
+// from their releases keyed by ID and the ring dependencies keyed by the ID of the
+// dependent ring. Rings and their installation groups are sorted by priority.
+func NewRingMatrix(rings []*Ring, releases map[string]*RingRelease, dependencies map[string][]string, now time.Time) *RingMatrix {
+	matrix := &RingMatrix{Rings: []*RingMatrixRing{}}
+
+	for _, ring := range rings {
+		row := &RingMatrixRing{
+			ID:                  ring.ID,
+			Name:                ring.Name,
+			State:               ring.State,
+			Priority:            ring.Priority,
+			ActiveRelease:       newRingMatrixRelease(releases[ring.ActiveReleaseID]),
+			DesiredRelease:      newRingMatrixRelease(releases[ring.DesiredReleaseID]),
+			ReleaseAt:           ring.ReleaseAt,
+			SecondsSinceRelease: secondsSince(ring.ReleaseAt, now),
+			Drift:               []string{},
+			InstallationGroups:  []*RingMatrixInstallationGroup{},
+		}
+
+		releasePending := ring.DesiredReleaseID != ring.ActiveReleaseID
+		if releasePending {
+			row.Drift = append(row.Drift, DriftReleasePending)
+		}
+		if activeRelease := releases[ring.ActiveReleaseID]; activeRelease != nil {
+			for _, earlierRing := range EarlierRings(ring, rings, dependencies) {
+				earlierRelease := releases[earlierRing.ActiveReleaseID]
+				if earlierRelease != nil && IsNewerReleaseVersion(activeRelease.Version, earlierRelease.Version) {
+					row.Drift = append(row.Drift, DriftAheadOfEarlierRing)
+					break
+				}
+			}
+		}
+
+		installationGroups := append([]*InstallationGroup(nil), ring.InstallationGroups...)
+		sort.SliceStable(installationGroups, func(i, j int) bool {
+			return installationGroups[i].Priority < installationGroups[j].Priority
+		})
+		for _, installationGroup := range installationGroups {
+			igRow := &RingMatrixInstallationGroup{
+				ID:                  installationGroup.ID,
+				Name:                installationGroup.Name,
+				State:               installationGroup.State,
+				ActiveRelease:       newRingMatrixRelease(releases[installationGroup.RunningReleaseID(ring)]),
+				DesiredRelease:      row.DesiredRelease,
+				ReleaseAt:           installationGroup.ReleaseAt,
+				SecondsSinceRelease: secondsSince(installationGroup.ReleaseAt, now),
+				Drift:               []string{},
+			}
+			skipped := installationGroup.SkippedReleaseID != "" && installationGroup.SkippedReleaseID == ring.DesiredReleaseID
+			if !skipped && installationGroup.RunningReleaseID(ring) != ring.DesiredReleaseID {
+				igRow.Drift = append(igRow.Drift, DriftReleasePending)
+			}
+			if skipped {
+				igRow.Drift = append(igRow.Drift, DriftSkippedRelease)
+			}
+			if installationGroup.Paused {
+				igRow.Drift = append(igRow.Drift, DriftPaused)
+			}
+			if installationGroup.Drift != "" {
+				igRow.Drift = append(igRow.Drift, DriftProvisioner)
+			}
+			row.InstallationGroups = append(row.InstallationGroups, igRow)
+		}
+
+		matrix.Rings = append(matrix.Rings, row)
+	}
+
+	sort.SliceStable(matrix.Rings, func(i, j int) bool {
+		if matrix.Rings[i].Priority != matrix.Rings[j].Priority {
+			return matrix.Rings[i].Priority < matrix.Rings[j].Priority
+		}
+		return matrix.Rings[i].Name < matrix.Rings[j].Name
+	})
+
+	return matrix
+}
+
+// String returns the image and version of the release.
+func (r *RingMatrixRelease) String() string {
+	if r == nil {
+		return ""
+	}
+
+	return r.Image + ":" + r.Version
+}
+
+// RingMatrixFromReader decodes a json-encoded ring matrix from the given io.Reader.
+func RingMatrixFromReader(reader io.Reader) (*RingMatrix, error) {
+	ringMatrix := RingMatrix{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&ringMatrix)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &ringMatrix, nil
+}
+
+func newRingMatrixRelease(release *RingRelease) *RingMatrixRelease {
+	if release == nil {
+		return nil
+	}
+
+	return &RingMatrixRelease{ID: release.ID, Image: release.Image, Version: release.Version}
+}
+
+func secondsSince(at int64, now time.Time) int64 {
+	if at == 0 {
+		return 0
+	}
+
+	return (now.UnixNano() - at) / int64(time.Second)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRingMatrix(t *testing.T) {
+	now := time.Now()
+	releases := map[string]*RingRelease{
+		"release-1": {ID: "release-1", Image: "image", Version: "7.8.0"},
+		"release-2": {ID: "release-2", Image: "image", Version: "7.9.0"},
+	}
+
+	canary := &Ring{
+		ID:               "canary",
+		Name:             "canary",
+		Priority:         1,
+		State:            RingStateReleaseInProgress,
+		ActiveReleaseID:  "release-1",
+		DesiredReleaseID: "release-2",
+		ReleaseAt:        now.Add(-time.Hour).UnixNano(),
+		InstallationGroups: []*InstallationGroup{
+			{ID: "ig-2", Name: "ig-2", Priority: 2, Paused: true},
+			{ID: "ig-1", Name: "ig-1", Priority: 1},
+			{ID: "ig-0", Name: "ig-0", Priority: 0, ActiveReleaseID: "release-2", DesiredReleaseID: "release-2"},
+		},
+	}
+	production := &Ring{
+		ID:               "production",
+		Name:             "production",
+		Priority:         2,
+		State:            RingStateStable,
+		ActiveReleaseID:  "release-2",
+		DesiredReleaseID: "release-2",
+		InstallationGroups: []*InstallationGroup{
+			{ID: "ig-3", Name: "ig-3", SkippedReleaseID: "release-2"},
+			{ID: "ig-4", Name: "ig-4", Priority: 1, Drift: "runs image:7.8.0 instead of image:7.9.0"},
+		},
+	}
+
+	matrix := NewRingMatrix([]*Ring{production, canary}, releases, nil, now)
+	require.Len(t, matrix.Rings, 2)
+
+	canaryRow := matrix.Rings[0]
+	require.Equal(t, "canary", canaryRow.ID)
+	require.Equal(t, "image:7.8.0", canaryRow.ActiveRelease.String())
+	require.Equal(t, "image:7.9.0", canaryRow.DesiredRelease.String())
+	require.Equal(t, int64(3600), canaryRow.SecondsSinceRelease)
+	require.Equal(t, []string{DriftReleasePending}, canaryRow.Drift)
+	require.Len(t, canaryRow.InstallationGroups, 3)
+	require.Equal(t, "ig-0", canaryRow.InstallationGroups[0].ID)
+	require.Equal(t, "image:7.9.0", canaryRow.InstallationGroups[0].ActiveRelease.String())
+	require.Empty(t, canaryRow.InstallationGroups[0].Drift)
+	require.Equal(t, "ig-1", canaryRow.InstallationGroups[1].ID)
+	require.Equal(t, "image:7.8.0", canaryRow.InstallationGroups[1].ActiveRelease.String())
+	require.Equal(t, []string{DriftReleasePending}, canaryRow.InstallationGroups[1].Drift)
+	require.Equal(t, []string{DriftReleasePending, DriftPaused}, canaryRow.InstallationGroups[2].Drift)
+
+	productionRow := matrix.Rings[1]
+	require.Equal(t, "production", productionRow.ID)
+	require.Zero(t, productionRow.SecondsSinceRelease)
+	require.Equal(t, []string{DriftAheadOfEarlierRing}, productionRow.Drift)
+	require.Nil(t, productionRow.InstallationGroups[0].ActiveRelease)
+	require.Equal(t, []string{DriftSkippedRelease}, productionRow.InstallationGroups[0].Drift)
+	require.Equal(t, []string{DriftProvisioner}, productionRow.InstallationGroups[1].Drift)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// RingReleaseFilter describes the parameters used to constrain a set of ring releases.
+type RingReleaseFilter struct {
+	Image   string
+	Version string
+	// CreatedAfter only matches releases created after the given time, in nanoseconds.
+	CreatedAfter int64
+	// Force only matches forced or unforced releases when set.
+	Force   *bool
+	Page    int
+	PerPage int
+}
+
+// GetRingReleasesRequest describes the parameters to request a list of ring releases.
+type GetRingReleasesRequest struct {
+	Image        string
+	Version      string
+	CreatedAfter int64
+	Force        *bool
+	Page         int
+	PerPage      int
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetRingReleasesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if request.Image != "" {
+		q.Add("image", request.Image)
+	}
+	if request.Version != "" {
+		q.Add("version", request.Version)
+	}
+	if request.CreatedAfter != 0 {
+		q.Add("created_after", strconv.FormatInt(request.CreatedAfter, 10))
+	}
+	if request.Force != nil {
+		q.Add("force", strconv.FormatBool(*request.Force))
+	}
+	q.Add("page", strconv.Itoa(request.Page))
+	q.Add("per_page", strconv.Itoa(request.PerPage))
+	u.RawQuery = q.Encode()
+}
+
+// RingReleaseUsage is a ring release together with the rings and installation groups that
+// currently run it or desire it.
+type RingReleaseUsage struct {
+	*RingRelease
+	// ActiveRings are the IDs of the rings whose active release is the release.
+	ActiveRings []string
+	// DesiredRings are the IDs of the rings being released the release.
+	DesiredRings []string
+	// ActiveInstallationGroups are the IDs of the installation groups running the release.
+	ActiveInstallationGroups []string
+	// DesiredInstallationGroups are the IDs of the installation groups of the desired rings
+	// that do not run the release yet and are not left out of it.
+	DesiredInstallationGroups []string
+}
+
+// NewRingReleaseUsages returns the usage of each of the given releases by the given rings
+// and their installation groups. Installation groups run the release they last completed,
+// or the active release of their ring until they complete one.
+func NewRingReleaseUsages(releases []*RingRelease, rings []*Ring) []*RingReleaseUsage {
+	usages := make([]*RingReleaseUsage, 0, len(releases))
+	usageByID := make(map[string]*RingReleaseUsage, len(releases))
+	for _, release := range releases {
+		usage := &RingReleaseUsage{
+			RingRelease:               release,
+			ActiveRings:               []string{},
+			DesiredRings:              []string{},
+			ActiveInstallationGroups:  []string{},
+			DesiredInstallationGroups: []string{},
+		}
+		usages = append(usages, usage)
+		usageByID[release.ID] = usage
+	}
+
+	for _, ring := range rings {
+		if usage, ok := usageByID[ring.ActiveReleaseID]; ok {
+			usage.ActiveRings = append(usage.ActiveRings, ring.ID)
+		}
+		for _, installationGroup := range ring.InstallationGroups {
+			if usage, ok := usageByID[installationGroup.RunningReleaseID(ring)]; ok {
+				usage.ActiveInstallationGroups = append(usage.ActiveInstallationGroups, installationGroup.ID)
+			}
+		}
+
+		if ring.DesiredReleaseID == ring.ActiveReleaseID {
+			continue
+		}
+		if usage, ok := usageByID[ring.DesiredReleaseID]; ok {
+			usage.DesiredRings = append(usage.DesiredRings, ring.ID)
+			for _, installationGroup := range ring.InstallationGroups {
+				if installationGroup.Paused || usage.ExcludesInstallationGroup(installationGroup) || installationGroup.RunningReleaseID(ring) == ring.DesiredReleaseID {
+					continue
+				}
+				usage.DesiredInstallationGroups = append(usage.DesiredInstallationGroups, installationGroup.ID)
+			}
+		}
+	}
+
+	return usages
+}
+
+// RingReleaseUsagesFromReader decodes a json-encoded list of ring release usages from the
+// given io.Reader.
+func RingReleaseUsagesFromReader(reader io.Reader) ([]*RingReleaseUsage, error) {
+	usages := []*RingReleaseUsage{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&usages)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return usages, nil
+}
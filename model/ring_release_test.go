@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRingReleaseUsages(t *testing.T) {
+	release1 := &RingRelease{ID: "release-1", Image: "image", Version: "1.0.0"}
+	release2 := &RingRelease{ID: "release-2", Image: "image", Version: "1.1.0", ExcludedInstallationGroups: []string{"excluded"}}
+	unused := &RingRelease{ID: "unused", Image: "image", Version: "0.9.0"}
+
+	rings := []*Ring{
+		{
+			ID:               "stable",
+			ActiveReleaseID:  "release-1",
+			DesiredReleaseID: "release-1",
+			InstallationGroups: []*InstallationGroup{
+				{ID: "ig-1", Name: "ig-1"},
+			},
+		},
+		{
+			ID:               "releasing",
+			ActiveReleaseID:  "release-1",
+			DesiredReleaseID: "release-2",
+			InstallationGroups: []*InstallationGroup{
+				{ID: "ig-2", Name: "ig-2"},
+				{ID: "ig-3", Name: "excluded"},
+				{ID: "ig-4", Name: "paused", Paused: true},
+				{ID: "ig-5", Name: "released", ActiveReleaseID: "release-2"},
+			},
+		},
+	}
+
+	usages := NewRingReleaseUsages([]*RingRelease{release1, release2, unused}, rings)
+	require.Len(t, usages, 3)
+
+	require.Equal(t, release1, usages[0].RingRelease)
+	require.Equal(t, []string{"stable", "releasing"}, usages[0].ActiveRings)
+	require.Empty(t, usages[0].DesiredRings)
+	require.Equal(t, []string{"ig-1", "ig-2", "ig-3", "ig-4"}, usages[0].ActiveInstallationGroups)
+	require.Empty(t, usages[0].DesiredInstallationGroups)
+
+	require.Equal(t, release2, usages[1].RingRelease)
+	require.Empty(t, usages[1].ActiveRings)
+	require.Equal(t, []string{"releasing"}, usages[1].DesiredRings)
+	require.Equal(t, []string{"ig-5"}, usages[1].ActiveInstallationGroups)
+	require.Equal(t, []string{"ig-2"}, usages[1].DesiredInstallationGroups)
+
+	require.Equal(t, unused, usages[2].RingRelease)
+	require.Empty(t, usages[2].ActiveRings)
+	require.Empty(t, usages[2].DesiredRings)
+}
@@ -9,11 +9,15 @@ import (
 	"io"
 	"net/url"
 	"strconv"
+	"strings"
 
 	cmodel "github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 )
 
+// RingNameMaxLength is the maximum length of a ring name.
+const RingNameMaxLength = 64
+
 // CreateRingRequest specifies the parameters for a new ring.
 type CreateRingRequest struct {
 	Name              string             `json:"name,omitempty"`
@@ -23,6 +27,7 @@ type CreateRingRequest struct {
 	Image             string             `json:"image,omitempty"`
 	Version           string             `json:"version,omitempty"`
 	APISecurityLock   bool               `json:"apiSecurityLock,omitempty"`
+	DependsOn         []string           `json:"dependsOn,omitempty"`
 }
 
 // UpdateRingRequest specifies the parameters to update a ring.
@@ -33,6 +38,9 @@ type UpdateRingRequest struct {
 	Image           string `json:"image,omitempty"`
 	Version         string `json:"version,omitempty"`
 	APISecurityLock bool   `json:"apiSecurityLock,omitempty"`
+	// DependsOn replaces the IDs of the rings the ring is released after when set. An
+	// empty list removes all dependencies of the ring.
+	DependsOn *[]string `json:"dependsOn,omitempty"`
 }
 
 // RingReleaseRequest contains metadata related to changing the installed ring state.
@@ -41,6 +49,12 @@ type RingReleaseRequest struct {
 	Version      string
 	Force        bool
 	EnvVariables cmodel.EnvVarMap
+	// ExcludedInstallationGroups are the IDs or names of the installation groups to leave
+	// out of the release.
+	ExcludedInstallationGroups []string `json:",omitempty"`
+	// AllowDowngrade allows releasing an older semantic version than the active release of
+	// the ring without forcing the release.
+	AllowDowngrade bool `json:",omitempty"`
 }
 
 // GetRingsRequest describes the parameters to request a list of rings.
@@ -59,6 +73,9 @@ func (request *CreateRingRequest) SetDefaults() {
 
 // Validate validates the values of a ring create request.
 func (request *CreateRingRequest) Validate() error {
+	if err := ValidateRingName(request.Name); err != nil {
+		return err
+	}
 	if request.Priority == 0 {
 		return errors.New("Priority cannot be zero")
 	}
@@ -66,6 +83,31 @@ func (request *CreateRingRequest) Validate() error {
 	return nil
 }
 
+// Validate validates the values of a ring update request.
+func (request *UpdateRingRequest) Validate() error {
+	if request.Name != "" {
+		return ValidateRingName(request.Name)
+	}
+
+	return nil
+}
+
+// ValidateRingName checks that the given name can identify a ring. Names are unique
+// among non-deleted rings and are used in URL paths.
+func ValidateRingName(name string) error {
+	if name == "" {
+		return errors.New("Name cannot be empty")
+	}
+	if len(name) > RingNameMaxLength {
+		return errors.Errorf("Name cannot be longer than %d characters", RingNameMaxLength)
+	}
+	if strings.ContainsAny(name, "/?#%") {
+		return errors.New("Name cannot contain any of the characters / ? # %")
+	}
+
+	return nil
+}
+
 // NewCreateRingRequestFromReader will create a CreateRingRequest from an
 // io.Reader with JSON data.
 func NewCreateRingRequestFromReader(reader io.Reader) (*CreateRingRequest, error) {
@@ -90,6 +132,11 @@ func NewUpdateRingRequestFromReader(reader io.Reader) (*UpdateRingRequest, error
 	if err != nil && err != io.EOF {
 		return nil, errors.Wrap(err, "failed to decode provision ring request")
 	}
+
+	if err = updateRingRequest.Validate(); err != nil {
+		return nil, errors.Wrap(err, "update ring request failed validation")
+	}
+
 	return &updateRingRequest, nil
 }
 
@@ -5,6 +5,7 @@
 package model_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/mattermost/elrond/model"
@@ -17,8 +18,11 @@ func TestCreateRingRequestValid(t *testing.T) {
 		request      *model.CreateRingRequest
 		requireError bool
 	}{
-		{"defaults", &model.CreateRingRequest{SoakTime: 3600, Priority: 1, InstallationGroup: &model.InstallationGroup{Name: "test2"}}, false},
-		{"invalid priority", &model.CreateRingRequest{Priority: 0}, true},
+		{"defaults", &model.CreateRingRequest{Name: "ring", SoakTime: 3600, Priority: 1, InstallationGroup: &model.InstallationGroup{Name: "test2"}}, false},
+		{"invalid priority", &model.CreateRingRequest{Name: "ring", Priority: 0}, true},
+		{"missing name", &model.CreateRingRequest{Priority: 1}, true},
+		{"name with slash", &model.CreateRingRequest{Name: "ring/1", Priority: 1}, true},
+		{"name too long", &model.CreateRingRequest{Name: strings.Repeat("r", model.RingNameMaxLength+1), Priority: 1}, true},
 	}
 
 	for _, tc := range testCases {
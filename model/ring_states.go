@@ -4,6 +4,8 @@
 
 package model
 
+import "slices"
+
 const (
 	// RingStateStable is a ring in a stable state and undergoing no changes.
 	RingStateStable = "stable"
@@ -103,6 +105,17 @@ var AllRingRequestStates = []string{
 	RingStateDeletionRequested,
 }
 
+// IsReleasing returns whether the ring has a release pending or in progress.
+func (c *Ring) IsReleasing() bool {
+	return slices.Contains(AllRingStatesReleaseInProgress, c.State) ||
+		slices.Contains(AllRingStatesReleasePending, c.State)
+}
+
+// HasCompletedRelease returns whether the ring is stable with its desired release active.
+func (c *Ring) HasCompletedRelease() bool {
+	return c.State == RingStateStable && c.ActiveReleaseID == c.DesiredReleaseID
+}
+
 // ValidTransitionState returns whether a ring can be transitioned into the
 // new state or not based on its current state.
 func (c *Ring) ValidTransitionState(newState string) bool {
@@ -144,7 +157,8 @@ func validTransitionToRingStateReleasePending(currentState string) bool {
 		RingStateReleasePending,
 		RingStateReleaseFailed,
 		RingStateSoakingFailed,
-		RingStateReleasePaused:
+		RingStateReleasePaused,
+		RingStateReleaseRollbackComplete:
 		return true
 	}
 
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"testing"
 
+	cmodel "github.com/mattermost/mattermost-cloud/model"
 	"github.com/stretchr/testify/require"
 )
 
@@ -80,3 +81,26 @@ func TestRingsFromReader(t *testing.T) {
 		}, ring)
 	})
 }
+
+func TestRingReleaseDriftFrom(t *testing.T) {
+	release := &RingRelease{
+		Image:        "image",
+		Version:      "7.9.0",
+		EnvVariables: cmodel.EnvVarMap{"B": {Value: "b"}, "A": {Value: "a"}},
+	}
+
+	t.Run("no drift", func(t *testing.T) {
+		deployed := &RingRelease{Image: "image", Version: "7.9.0", EnvVariables: cmodel.EnvVarMap{"A": {Value: "a"}, "B": {Value: "b"}, "C": {Value: "c"}}}
+		require.Empty(t, release.DriftFrom(deployed))
+	})
+
+	t.Run("version and env variables", func(t *testing.T) {
+		deployed := &RingRelease{Image: "image", Version: "7.8.0", EnvVariables: cmodel.EnvVarMap{"A": {Value: "changed"}}}
+		require.Equal(t, "runs image:7.8.0 instead of image:7.9.0; env variables A, B differ", release.DriftFrom(deployed))
+	})
+
+	t.Run("release without env variables", func(t *testing.T) {
+		deployed := &RingRelease{Image: "image", Version: "7.9.0", EnvVariables: cmodel.EnvVarMap{"A": {Value: "a"}}}
+		require.Empty(t, (&RingRelease{Image: "image", Version: "7.9.0"}).DriftFrom(deployed))
+	})
+}
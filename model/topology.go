@@ -0,0 +1,380 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TopologyActionCreate is a change creating a ring.
+	TopologyActionCreate = "create"
+	// TopologyActionUpdate is a change updating a ring or an installation group.
+	TopologyActionUpdate = "update"
+	// TopologyActionDelete is a change deleting a ring.
+	TopologyActionDelete = "delete"
+	// TopologyActionRegister is a change registering an installation group to a ring.
+	TopologyActionRegister = "register"
+	// TopologyActionDeregister is a change removing an installation group from a ring.
+	TopologyActionDeregister = "deregister"
+
+	// TopologyKindRing is a change applied to a ring.
+	TopologyKindRing = "ring"
+	// TopologyKindInstallationGroup is a change applied to an installation group.
+	TopologyKindInstallationGroup = "installation-group"
+)
+
+// Topology is the complete desired set of rings and their installation groups.
+type Topology struct {
+	// Image and Version select the release installed on rings created by the topology.
+	Image   string          `json:"image,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Rings   []*TopologyRing `json:"rings"`
+}
+
+// TopologyRing is the desired configuration of a ring.
+type TopologyRing struct {
+	Name               string                       `json:"name"`
+	Priority           int                          `json:"priority"`
+	SoakTime           int                          `json:"soakTime,omitempty"`
+	InstallationGroups []*TopologyInstallationGroup `json:"installationGroups,omitempty"`
+}
+
+// TopologyInstallationGroup is the desired configuration of an installation group.
+type TopologyInstallationGroup struct {
+	Name               string `json:"name"`
+	SoakTime           int    `json:"soakTime,omitempty"`
+	ProvisionerGroupID string `json:"provisionerGroupID,omitempty"`
+	ProvisionerTarget  string `json:"provisionerTarget,omitempty"`
+	Priority           int    `json:"priority,omitempty"`
+}
+
+// ApplyTopologyRequest specifies the topology to converge the rings to.
+type ApplyTopologyRequest struct {
+	Topology *Topology `json:"topology"`
+	// DryRun only computes the plan without applying it.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Prune deletes the rings missing from the topology.
+	Prune bool `json:"prune,omitempty"`
+}
+
+// TopologyChange is a single change needed to converge to a topology.
+type TopologyChange struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	// ID is the ID of the existing ring or installation group, if any.
+	ID string `json:"id,omitempty"`
+	// Ring and RingID identify the ring an installation group change applies to.
+	Ring               string   `json:"ring,omitempty"`
+	RingID             string   `json:"ringID,omitempty"`
+	Priority           int      `json:"priority,omitempty"`
+	SoakTime           int      `json:"soakTime,omitempty"`
+	ProvisionerGroupID string   `json:"provisionerGroupID,omitempty"`
+	ProvisionerTarget  string   `json:"provisionerTarget,omitempty"`
+	Details            []string `json:"details,omitempty"`
+}
+
+// TopologyPlan is the ordered list of changes needed to converge to a topology.
+type TopologyPlan struct {
+	Changes []*TopologyChange `json:"changes"`
+	Applied bool              `json:"applied"`
+}
+
+// IsEmpty returns true if the plan contains no change.
+func (p *TopologyPlan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+// SetDefaults sets the default values of the topology.
+func (t *Topology) SetDefaults() {
+	for _, ring := range t.Rings {
+		if ring != nil && ring.SoakTime == 0 {
+			ring.SoakTime = 7200
+		}
+	}
+}
+
+// Validate validates the topology.
+func (t *Topology) Validate() error {
+	ringNames := map[string]bool{}
+	installationGroupNames := map[string]bool{}
+	provisionerGroups := map[string]string{}
+	for _, ring := range t.Rings {
+		if ring == nil {
+			return errors.New("ring cannot be empty")
+		}
+		if err := ValidateRingName(ring.Name); err != nil {
+			return errors.Wrapf(err, "invalid ring name %q", ring.Name)
+		}
+		if ringNames[ring.Name] {
+			return errors.Errorf("ring %s is defined more than once", ring.Name)
+		}
+		ringNames[ring.Name] = true
+
+		if ring.Priority == 0 {
+			return errors.Errorf("priority of ring %s cannot be zero", ring.Name)
+		}
+		if ring.SoakTime < 0 {
+			return errors.Errorf("soak time of ring %s cannot be negative", ring.Name)
+		}
+
+		for _, installationGroup := range ring.InstallationGroups {
+			if installationGroup == nil || installationGroup.Name == "" {
+				return errors.Errorf("installation group name in ring %s cannot be empty", ring.Name)
+			}
+			if installationGroupNames[installationGroup.Name] {
+				return errors.Errorf("installation group %s is defined more than once", installationGroup.Name)
+			}
+			installationGroupNames[installationGroup.Name] = true
+
+			if installationGroup.SoakTime < 0 {
+				return errors.Errorf("soak time of installation group %s cannot be negative", installationGroup.Name)
+			}
+
+			if installationGroup.ProvisionerGroupID != "" {
+				provisionerGroup := installationGroup.ProvisionerTarget + "/" + installationGroup.ProvisionerGroupID
+				if other, ok := provisionerGroups[provisionerGroup]; ok {
+					return errors.Errorf("provisioner group %s is registered to installation groups %s and %s", installationGroup.ProvisionerGroupID, other, installationGroup.Name)
+				}
+				provisionerGroups[provisionerGroup] = installationGroup.Name
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewApplyTopologyRequestFromReader will create an ApplyTopologyRequest from an
+// io.Reader with JSON data.
+func NewApplyTopologyRequestFromReader(reader io.Reader) (*ApplyTopologyRequest, error) {
+	var applyTopologyRequest ApplyTopologyRequest
+	err := json.NewDecoder(reader).Decode(&applyTopologyRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode apply topology request")
+	}
+
+	if applyTopologyRequest.Topology == nil {
+		return nil, errors.New("apply topology request failed validation: topology cannot be empty")
+	}
+	applyTopologyRequest.Topology.SetDefaults()
+	if err = applyTopologyRequest.Topology.Validate(); err != nil {
+		return nil, errors.Wrap(err, "apply topology request failed validation")
+	}
+
+	return &applyTopologyRequest, nil
+}
+
+// TopologyPlanFromReader decodes a json-encoded topology plan from the given io.Reader.
+func TopologyPlanFromReader(reader io.Reader) (*TopologyPlan, error) {
+	plan := TopologyPlan{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&plan)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// PlanTopology computes the changes needed to converge the given rings, with their
+// installation groups populated, to the desired topology. Rings are matched by name,
+// as are installation groups, whose names are unique across rings. Rings missing from
+// the topology are only deleted when prune is set.
+//
+// Changes are ordered so that they can be applied one after the other: ring creations
+// and updates first, then installation group removals, then installation group
+// registrations and updates, and finally ring deletions.
+func PlanTopology(desired *Topology, current []*Ring, prune bool) (*TopologyPlan, error) {
+	currentRings := map[string]*Ring{}
+	duplicateRingNames := map[string]bool{}
+	currentInstallationGroups := map[string]*InstallationGroup{}
+	installationGroupRings := map[string]*Ring{}
+	for _, ring := range current {
+		if _, ok := currentRings[ring.Name]; ok {
+			duplicateRingNames[ring.Name] = true
+		}
+		currentRings[ring.Name] = ring
+
+		for _, installationGroup := range ring.InstallationGroups {
+			currentInstallationGroups[installationGroup.Name] = installationGroup
+			installationGroupRings[installationGroup.Name] = ring
+		}
+	}
+
+	var ringChanges, deregisterChanges, installationGroupChanges, deleteChanges []*TopologyChange
+	desiredRings := map[string]bool{}
+	desiredInstallationGroups := map[string]bool{}
+
+	for _, desiredRing := range desired.Rings {
+		desiredRings[desiredRing.Name] = true
+		if duplicateRingNames[desiredRing.Name] {
+			return nil, errors.Errorf("more than one ring is named %q, rename them before applying a topology", desiredRing.Name)
+		}
+
+		ring, exists := currentRings[desiredRing.Name]
+		if !exists {
+			ringChanges = append(ringChanges, &TopologyChange{
+				Action:   TopologyActionCreate,
+				Kind:     TopologyKindRing,
+				Name:     desiredRing.Name,
+				Priority: desiredRing.Priority,
+				SoakTime: desiredRing.SoakTime,
+			})
+		} else {
+			var details []string
+			if ring.Priority != desiredRing.Priority {
+				details = append(details, fmt.Sprintf("priority: %d -> %d", ring.Priority, desiredRing.Priority))
+			}
+			if ring.SoakTime != desiredRing.SoakTime {
+				details = append(details, fmt.Sprintf("soakTime: %d -> %d", ring.SoakTime, desiredRing.SoakTime))
+			}
+			if len(details) > 0 {
+				ringChanges = append(ringChanges, &TopologyChange{
+					Action:   TopologyActionUpdate,
+					Kind:     TopologyKindRing,
+					Name:     desiredRing.Name,
+					ID:       ring.ID,
+					Priority: desiredRing.Priority,
+					SoakTime: desiredRing.SoakTime,
+					Details:  details,
+				})
+			}
+		}
+
+		for _, desiredInstallationGroup := range desiredRing.InstallationGroups {
+			desiredInstallationGroups[desiredInstallationGroup.Name] = true
+
+			change := &TopologyChange{
+				Kind:               TopologyKindInstallationGroup,
+				Name:               desiredInstallationGroup.Name,
+				Ring:               desiredRing.Name,
+				SoakTime:           desiredInstallationGroup.SoakTime,
+				ProvisionerGroupID: desiredInstallationGroup.ProvisionerGroupID,
+				ProvisionerTarget:  desiredInstallationGroup.ProvisionerTarget,
+				Priority:           desiredInstallationGroup.Priority,
+			}
+			if ring != nil {
+				change.RingID = ring.ID
+			}
+
+			installationGroup, registered := currentInstallationGroups[desiredInstallationGroup.Name]
+			if !registered {
+				change.Action = TopologyActionRegister
+				installationGroupChanges = append(installationGroupChanges, change)
+				continue
+			}
+			change.ID = installationGroup.ID
+			change.Details = installationGroupDetails(installationGroup, desiredInstallationGroup)
+
+			previousRing := installationGroupRings[desiredInstallationGroup.Name]
+			if previousRing.Name != desiredRing.Name {
+				deregisterChanges = append(deregisterChanges, &TopologyChange{
+					Action:  TopologyActionDeregister,
+					Kind:    TopologyKindInstallationGroup,
+					Name:    installationGroup.Name,
+					ID:      installationGroup.ID,
+					Ring:    previousRing.Name,
+					RingID:  previousRing.ID,
+					Details: []string{fmt.Sprintf("moved to ring %s", desiredRing.Name)},
+				})
+				change.Action = TopologyActionRegister
+				installationGroupChanges = append(installationGroupChanges, change)
+				continue
+			}
+
+			if len(change.Details) > 0 {
+				change.Action = TopologyActionUpdate
+				installationGroupChanges = append(installationGroupChanges, change)
+			}
+		}
+	}
+
+	for _, ring := range current {
+		if !desiredRings[ring.Name] {
+			if prune {
+				deleteChanges = append(deleteChanges, &TopologyChange{
+					Action: TopologyActionDelete,
+					Kind:   TopologyKindRing,
+					Name:   ring.Name,
+					ID:     ring.ID,
+				})
+			}
+			continue
+		}
+
+		for _, installationGroup := range ring.InstallationGroups {
+			if !desiredInstallationGroups[installationGroup.Name] {
+				deregisterChanges = append(deregisterChanges, &TopologyChange{
+					Action: TopologyActionDeregister,
+					Kind:   TopologyKindInstallationGroup,
+					Name:   installationGroup.Name,
+					ID:     installationGroup.ID,
+					Ring:   ring.Name,
+					RingID: ring.ID,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(deregisterChanges, func(i, j int) bool {
+		return deregisterChanges[i].Name < deregisterChanges[j].Name
+	})
+
+	plan := &TopologyPlan{Changes: []*TopologyChange{}}
+	plan.Changes = append(plan.Changes, ringChanges...)
+	plan.Changes = append(plan.Changes, deregisterChanges...)
+	plan.Changes = append(plan.Changes, installationGroupChanges...)
+	plan.Changes = append(plan.Changes, deleteChanges...)
+
+	return plan, nil
+}
+
+func installationGroupDetails(current *InstallationGroup, desired *TopologyInstallationGroup) []string {
+	var details []string
+	if current.SoakTime != desired.SoakTime {
+		details = append(details, fmt.Sprintf("soakTime: %d -> %d", current.SoakTime, desired.SoakTime))
+	}
+	if current.ProvisionerGroupID != desired.ProvisionerGroupID {
+		details = append(details, fmt.Sprintf("provisionerGroupID: %q -> %q", current.ProvisionerGroupID, desired.ProvisionerGroupID))
+	}
+	if current.ProvisionerTarget != desired.ProvisionerTarget {
+		details = append(details, fmt.Sprintf("provisionerTarget: %q -> %q", current.ProvisionerTarget, desired.ProvisionerTarget))
+	}
+	if current.Priority != desired.Priority {
+		details = append(details, fmt.Sprintf("priority: %d -> %d", current.Priority, desired.Priority))
+	}
+
+	return details
+}
+
+// TopologyExport is a snapshot of the configuration of an elrond server, from which
+// another database can be restored.
+type TopologyExport struct {
+	ExportAt int64          `json:"exportAt"`
+	Rings    []*Ring        `json:"rings"`
+	Webhooks []*Webhook     `json:"webhooks"`
+	Releases []*RingRelease `json:"releases"`
+	// BlockedReleases is optional so that exports taken before the blocklist existed can
+	// still be restored.
+	BlockedReleases []*BlockedRelease `json:"blockedReleases,omitempty"`
+}
+
+// TopologyExportFromReader decodes a json-encoded topology export from the given io.Reader.
+func TopologyExportFromReader(reader io.Reader) (*TopologyExport, error) {
+	export := TopologyExport{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&export)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &export, nil
+}
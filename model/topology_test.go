@@ -0,0 +1,146 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewApplyTopologyRequestFromReader(t *testing.T) {
+	t.Run("valid request", func(t *testing.T) {
+		request, err := NewApplyTopologyRequestFromReader(strings.NewReader(
+			`{"topology":{"rings":[{"name":"ring-1","priority":1,"installationGroups":[{"name":"ig-1"}]}]},"dryRun":true}`,
+		))
+		require.NoError(t, err)
+		require.True(t, request.DryRun)
+		require.Equal(t, 7200, request.Topology.Rings[0].SoakTime)
+	})
+
+	t.Run("missing topology", func(t *testing.T) {
+		_, err := NewApplyTopologyRequestFromReader(strings.NewReader(`{"dryRun":true}`))
+		require.Error(t, err)
+	})
+
+	t.Run("provisioner group of several targets", func(t *testing.T) {
+		request, err := NewApplyTopologyRequestFromReader(strings.NewReader(`{"topology":{"rings":[{"name":"ring-1","priority":1,"installationGroups":[{"name":"ig-1","provisionerGroupID":"pg1"},{"name":"ig-2","provisionerGroupID":"pg1","provisionerTarget":"eu"}]}]}}`))
+		require.NoError(t, err)
+		require.Len(t, request.Topology.Rings[0].InstallationGroups, 2)
+	})
+
+	t.Run("invalid topologies", func(t *testing.T) {
+		for _, body := range []string{
+			`{"topology":{"rings":[{"priority":1}]}}`,
+			`{"topology":{"rings":[{"name":"ring-1"}]}}`,
+			`{"topology":{"rings":[{"name":"ring-1","priority":1},{"name":"ring-1","priority":2}]}}`,
+			`{"topology":{"rings":[{"name":"ring-1","priority":1,"installationGroups":[{"name":"ig-1"}]},{"name":"ring-2","priority":2,"installationGroups":[{"name":"ig-1"}]}]}}`,
+			`{"topology":{"rings":[{"name":"ring-1","priority":1,"installationGroups":[{}]}]}}`,
+			`{"topology":{"rings":[{"name":"ring-1","priority":1,"installationGroups":[{"name":"ig-1","provisionerGroupID":"pg1"}]},{"name":"ring-2","priority":2,"installationGroups":[{"name":"ig-2","provisionerGroupID":"pg1"}]}]}}`,
+			`{"topology":`,
+		} {
+			_, err := NewApplyTopologyRequestFromReader(strings.NewReader(body))
+			require.Error(t, err, body)
+		}
+	})
+}
+
+func TestPlanTopology(t *testing.T) {
+	current := []*Ring{
+		{
+			ID:       "ring1",
+			Name:     "ring-1",
+			Priority: 1,
+			SoakTime: 60,
+			InstallationGroups: []*InstallationGroup{
+				{ID: "ig1", Name: "ig-1", SoakTime: 30, ProvisionerGroupID: "pg1"},
+				{ID: "ig2", Name: "ig-2", SoakTime: 30, ProvisionerGroupID: "pg2"},
+			},
+		},
+		{
+			ID:       "ring2",
+			Name:     "ring-2",
+			Priority: 2,
+			SoakTime: 60,
+			InstallationGroups: []*InstallationGroup{
+				{ID: "ig3", Name: "ig-3", SoakTime: 30, ProvisionerGroupID: "pg3"},
+			},
+		},
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		plan, err := PlanTopology(&Topology{Rings: []*TopologyRing{
+			{Name: "ring-1", Priority: 1, SoakTime: 60, InstallationGroups: []*TopologyInstallationGroup{
+				{Name: "ig-1", SoakTime: 30, ProvisionerGroupID: "pg1"},
+				{Name: "ig-2", SoakTime: 30, ProvisionerGroupID: "pg2"},
+			}},
+			{Name: "ring-2", Priority: 2, SoakTime: 60, InstallationGroups: []*TopologyInstallationGroup{
+				{Name: "ig-3", SoakTime: 30, ProvisionerGroupID: "pg3"},
+			}},
+		}}, current, true)
+		require.NoError(t, err)
+		require.True(t, plan.IsEmpty())
+	})
+
+	t.Run("converge", func(t *testing.T) {
+		plan, err := PlanTopology(&Topology{Rings: []*TopologyRing{
+			{Name: "ring-1", Priority: 3, SoakTime: 60, InstallationGroups: []*TopologyInstallationGroup{
+				{Name: "ig-1", SoakTime: 90, ProvisionerGroupID: "pg1", Priority: 1},
+				{Name: "ig-3", SoakTime: 30, ProvisionerGroupID: "pg3"},
+			}},
+			{Name: "ring-3", Priority: 4, SoakTime: 60, InstallationGroups: []*TopologyInstallationGroup{
+				{Name: "ig-4", SoakTime: 30, ProvisionerGroupID: "pg4"},
+			}},
+		}}, current, false)
+		require.NoError(t, err)
+
+		var summary []string
+		for _, change := range plan.Changes {
+			summary = append(summary, change.Action+" "+change.Kind+" "+change.Name+" "+change.Ring)
+		}
+		require.Equal(t, []string{
+			"update ring ring-1 ",
+			"create ring ring-3 ",
+			"deregister installation-group ig-2 ring-1",
+			"deregister installation-group ig-3 ring-2",
+			"update installation-group ig-1 ring-1",
+			"register installation-group ig-3 ring-1",
+			"register installation-group ig-4 ring-3",
+		}, summary)
+
+		require.Equal(t, []string{"priority: 1 -> 3"}, plan.Changes[0].Details)
+		require.Equal(t, []string{"soakTime: 30 -> 90", "priority: 0 -> 1"}, plan.Changes[4].Details)
+		require.Equal(t, "ring1", plan.Changes[0].ID)
+		require.Equal(t, "ring2", plan.Changes[3].RingID)
+		require.Equal(t, "ig3", plan.Changes[5].ID)
+		require.Equal(t, "ring1", plan.Changes[5].RingID)
+		require.Empty(t, plan.Changes[6].RingID)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		plan, err := PlanTopology(&Topology{Rings: []*TopologyRing{
+			{Name: "ring-1", Priority: 1, SoakTime: 60, InstallationGroups: []*TopologyInstallationGroup{
+				{Name: "ig-1", SoakTime: 30, ProvisionerGroupID: "pg1"},
+				{Name: "ig-2", SoakTime: 30, ProvisionerGroupID: "pg2"},
+			}},
+		}}, current, true)
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, 1)
+		require.Equal(t, TopologyActionDelete, plan.Changes[0].Action)
+		require.Equal(t, "ring2", plan.Changes[0].ID)
+	})
+
+	t.Run("duplicate ring names", func(t *testing.T) {
+		rings := []*Ring{{ID: "a", Name: "ring"}, {ID: "b", Name: "ring"}}
+
+		_, err := PlanTopology(&Topology{Rings: []*TopologyRing{{Name: "ring", Priority: 1}}}, rings, false)
+		require.Error(t, err)
+
+		plan, err := PlanTopology(&Topology{}, rings, true)
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, 2)
+	})
+}
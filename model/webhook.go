@@ -12,6 +12,8 @@ import (
 const (
 	// TypeRing is the string value that represents a ring
 	TypeRing = "ring"
+	// TypeInstallationGroup is the string value that represents an installation group
+	TypeInstallationGroup = "installation-group"
 )
 
 // Webhook represents a elrond webhook
@@ -40,6 +42,7 @@ type WebhookPayload struct {
 	NewState  string            `json:"new_state"`
 	OldState  string            `json:"old_state"`
 	ExtraData map[string]string `json:"extra_data,omitempty"`
+	Actor     string            `json:"actor,omitempty"`
 }
 
 // IsDeleted returns whether the webhook was marked as deleted or not.